@@ -55,6 +55,10 @@ func (s *stubConversationLabelStore) Remove(string, string, int64, string) (uint
 	return 0, nil
 }
 
+func (s *stubConversationLabelStore) RemoveByChats(string, []string) (uint, error) {
+	return 0, nil
+}
+
 func (s *stubConversationLabelStore) FindConversationLabels(string, string, string) ([]*models.QpConversationLabel, error) {
 	return nil, nil
 }
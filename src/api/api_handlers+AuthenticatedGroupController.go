@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
 	media "github.com/nocodeleaks/quepasa/media"
 	models "github.com/nocodeleaks/quepasa/models"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
@@ -317,24 +317,16 @@ func AuthenticatedGroupPhotoController(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpClient := &http.Client{Timeout: 30 * time.Second}
-	resp, err := httpClient.Get(request.ImageURL)
+	result, err := library.SafeFetch(r.Context(), request.ImageURL, library.FetchOptions{
+		Timeout:      30 * time.Second,
+		ContentTypes: []string{"image/"},
+	})
 	if err != nil {
 		RespondErrorCode(w, fmt.Errorf("failed to download image: %w", err), http.StatusBadRequest)
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		RespondErrorCode(w, fmt.Errorf("failed to download image, server returned status: %s", resp.Status), http.StatusBadRequest)
-		return
-	}
 
-	imageData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		RespondErrorCode(w, fmt.Errorf("failed to read image data: %w", err), http.StatusBadRequest)
-		return
-	}
+	imageData := result.Content
 
 	// Convert image to JPEG (WhatsApp requires JPEG for group photos)
 	convertedData, err := media.ConvertToJpeg(imageData)
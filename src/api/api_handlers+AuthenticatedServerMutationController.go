@@ -58,6 +58,12 @@ func AuthenticatedServerCreateController(w http.ResponseWriter, r *http.Request)
 	}
 
 	patch := buildSessionConfigurationPatch(request)
+	if err := runtime.ValidateSessionConfigurationPatch(patch); err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
 	sessionToken := uuid.NewString()
 	if requestedSessionToken != "" {
 		sessionToken = requestedSessionToken
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// GetBusinessProfileController returns the business profile of a contact, or of this session when chatid is omitted.
+//
+//	@Summary		Get business profile
+//	@Description	Returns category, description, address, email, websites and opening hours of a WhatsApp Business account. Omit chatid to read the profile of this session.
+//	@Tags			Business
+//	@Produce		json
+//	@Param			chatid	query		string	false	"Business contact phone or WID"
+//	@Success		200		{object}	api.BusinessProfileResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/business/profile [get]
+func GetBusinessProfileController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.BusinessProfileResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+		return
+	}
+
+	chatid := strings.TrimSpace(library.GetChatId(r))
+	profile, err := server.GetBusinessManager().GetBusinessProfile(chatid)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Profile = profile
+	RespondSuccess(w, response)
+}
+
+// GetCatalogController returns a page of products of a business catalog, or of this session when chatid is omitted.
+//
+//	@Summary		Get business catalog
+//	@Description	Returns products of a WhatsApp Business catalog. Use the returned cursor to get the next page. Omit chatid to read the catalog of this session.
+//	@Tags			Business
+//	@Produce		json
+//	@Param			chatid	query		string	false	"Business contact phone or WID"
+//	@Param			limit	query		int		false	"Products per page (default 10, max 100)"
+//	@Param			cursor	query		string	false	"Cursor returned by the previous page"
+//	@Success		200		{object}	api.CatalogResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/business/catalog [get]
+func GetCatalogController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.CatalogResponse{}
+
+	var limit int
+	if param := library.GetRequestParameter(r, "limit"); len(param) > 0 {
+		value, err := strconv.Atoi(param)
+		if err != nil || value < 0 {
+			response.ParseError(fmt.Errorf("invalid limit: %s", param))
+			RespondInterface(w, response)
+			return
+		}
+		limit = value
+	}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+		return
+	}
+
+	chatid := strings.TrimSpace(library.GetChatId(r))
+	cursor := library.GetRequestParameter(r, "cursor")
+	catalog, err := server.GetBusinessManager().GetCatalog(chatid, limit, cursor)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Catalog = catalog
+	RespondSuccess(w, response)
+}
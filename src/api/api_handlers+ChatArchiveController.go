@@ -65,7 +65,7 @@ func ArchiveChatWithServer(w http.ResponseWriter, r *http.Request, server *model
 	}
 
 	// Format and validate the chat ID
-	formattedChatId, err := whatsapp.FormatEndpoint(request.ChatId)
+	formattedChatId, err := server.FormatEndpoint(request.ChatId)
 	if err != nil {
 		response.ParseError(fmt.Errorf("invalid chatid: %v", err))
 		RespondInterface(w, response)
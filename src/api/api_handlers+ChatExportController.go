@@ -0,0 +1,228 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// ChatExportController exports a conversation.
+//
+//	@Summary		Export chat
+//	@Description	Exports a chat on the phone transcript layout (txt), as stored messages (json) or as a ZIP bundling the transcript with media still available for download. Uses the message store when enabled (MESSAGESTORE=true), otherwise the cache. ZIP exports, exports with async=true or with more than 1000 messages run as background jobs, returning 202 with the job and its download link.
+//	@Tags			Chats
+//	@Produce		json,plain,application/zip
+//	@Param			chatid		path		string	true	"Chat id"
+//	@Param			format		query		string	false	"txt (default), json or zip"
+//	@Param			from		query		int		false	"Only messages since this unix timestamp (seconds)"
+//	@Param			to			query		int		false	"Only messages before this unix timestamp (seconds)"
+//	@Param			timezone	query		string	false	"IANA time zone of transcript dates, default server local"
+//	@Param			async		query		bool	false	"Always run as background job"
+//	@Success		200			{file}		file
+//	@Success		202			{object}	api.ChatExportResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/{chatid}/export [get]
+func ChatExportController(w http.ResponseWriter, r *http.Request) {
+	response := &apiModels.ChatExportResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	query, err := GetChatExportQuery(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	// counted first, large exports are loaded by the background job
+	count, err := server.CountChatExportMessages(query)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	async := strings.EqualFold(library.GetRequestParameter(r, "async"), "true")
+	if async || query.Format == models.QpChatExportZip || count > models.QpChatExportSyncLimit {
+		w.Header().Set("Content-Type", "application/json")
+
+		job, err := server.StartChatExport(query, count)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Job = job
+		response.Url = GetChatExportDownloadPath(r, job.Id)
+		response.ParseSuccess(fmt.Sprintf("export started, job: %s", job.Id))
+		RespondInterfaceCode(w, response, http.StatusAccepted)
+		return
+	}
+
+	messages, err := server.GetChatExportMessages(query)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	filename := query.GetFileName(server.GetChatExportTitle(query.ChatId, messages))
+	w.Header().Set("Content-Type", query.Format.GetContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = server.WriteChatExport(w, query, messages); err != nil {
+		server.GetLogger().Errorf("chat export failed: %s", err.Error())
+	}
+}
+
+// GetChatExportJobController returns the status of a background chat export.
+//
+//	@Summary		Get chat export job
+//	@Description	Returns a background chat export: pending, completed (with its download link) or failed. Files are kept for one hour after completion.
+//	@Tags			Chats
+//	@Produce		json
+//	@Param			jobid	path		string	true	"Job id"
+//	@Success		200		{object}	api.ChatExportResponse
+//	@Failure		404		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/export/{jobid} [get]
+func GetChatExportJobController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ChatExportResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	id := library.GetRequestParameter(r, "jobid")
+	job, ok := server.GetChatExport(id)
+	if !ok {
+		response.ParseError(fmt.Errorf("export not found: %s", id))
+		RespondInterfaceCode(w, response, http.StatusNotFound)
+		return
+	}
+
+	response.Job = job
+	if job.Status == models.QpHistoryJobCompleted {
+		response.Url = GetChatExportDownloadPath(r, job.Id)
+	}
+	RespondSuccess(w, response)
+}
+
+// DownloadChatExportController downloads the file of a completed background chat export.
+//
+//	@Summary		Download chat export
+//	@Description	Downloads the file of a completed background chat export
+//	@Tags			Chats
+//	@Produce		plain,json,application/zip
+//	@Param			jobid	path		string	true	"Job id"
+//	@Success		200		{file}		file
+//	@Failure		404		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/export/{jobid}/download [get]
+func DownloadChatExportController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	job, file, err := server.OpenChatExport(library.GetRequestParameter(r, "jobid"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", job.Format.GetContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, file); err != nil {
+		server.GetLogger().Errorf("chat export %s download failed: %s", job.Id, err.Error())
+	}
+}
+
+// GetChatExportQuery reads chat export filters from request
+func GetChatExportQuery(r *http.Request) (query models.QpChatExportQuery, err error) {
+	chatid := library.GetChatId(r)
+	if len(chatid) == 0 {
+		return query, fmt.Errorf("missing chatid parameter")
+	}
+
+	query.ChatId, err = whatsapp.FormatEndpoint(chatid)
+	if err != nil {
+		return query, err
+	}
+
+	query.Format, err = models.ParseQpChatExportFormat(library.GetRequestParameter(r, "format"))
+	if err != nil {
+		return query, err
+	}
+
+	from, err := StringToTimestamp(library.GetRequestParameter(r, "from"))
+	if err != nil {
+		return query, fmt.Errorf("invalid from parameter: %s", err.Error())
+	}
+	if from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+
+	to, err := StringToTimestamp(library.GetRequestParameter(r, "to"))
+	if err != nil {
+		return query, fmt.Errorf("invalid to parameter: %s", err.Error())
+	}
+	if to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return query, fmt.Errorf("from must be before to")
+	}
+
+	if timezone := library.GetRequestParameter(r, "timezone"); len(timezone) > 0 {
+		query.Location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return query, fmt.Errorf("invalid timezone parameter: %s", err.Error())
+		}
+	}
+
+	return query, nil
+}
+
+// GetChatExportDownloadPath returns the download link of an export, under the same api prefix of the request
+func GetChatExportDownloadPath(r *http.Request, id string) string {
+	prefix := r.URL.Path
+	if index := strings.Index(prefix, "/chats/"); index >= 0 {
+		prefix = prefix[:index]
+	}
+	return prefix + "/chats/export/" + id + "/download"
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// RequestChatHistoryController asks the phone for older messages of a chat.
+//
+//	@Summary		Load older chat messages
+//	@Description	Requests on demand history of a chat, before the given message or the oldest known one. Returns a job immediately; received messages are merged into the cache and message store and dispatched as history, then a system event with the job is dispatched on completion. Pending jobs of the same chat are reused.
+//	@Tags			Chats
+//	@Produce		json
+//	@Param			chatid		path		string	true	"Chat id"
+//	@Param			messageid	query		string	false	"Load messages before this message, default is the oldest known message of the chat"
+//	@Param			count		query		int		false	"Messages to load, default 50, max 100"
+//	@Success		202			{object}	api.ChatHistoryResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/{chatid}/history [post]
+func RequestChatHistoryController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ChatHistoryResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatid := library.GetChatId(r)
+	if len(chatid) == 0 {
+		response.ParseError(fmt.Errorf("missing chatid parameter"))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	chatid, err = whatsapp.FormatEndpoint(chatid)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	var count int
+	if param := library.GetRequestParameter(r, "count"); len(param) > 0 {
+		count, err = strconv.Atoi(param)
+		if err != nil {
+			response.ParseError(fmt.Errorf("invalid count parameter: %s", err.Error()))
+			RespondInterfaceCode(w, response, http.StatusBadRequest)
+			return
+		}
+	}
+
+	job, err := server.RequestChatHistory(chatid, GetMessageId(r), count)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	response.Job = job
+	response.ParseSuccess(fmt.Sprintf("history requested, job: %s", job.Id))
+	RespondInterfaceCode(w, response, http.StatusAccepted)
+}
+
+// GetChatHistoryJobController returns the status of an on demand history job.
+//
+//	@Summary		Get chat history job
+//	@Description	Returns an on demand history job: pending, completed (with received messages and if older ones remain on the phone), failed or expired
+//	@Tags			Chats
+//	@Produce		json
+//	@Param			jobid	path		string	true	"Job id"
+//	@Success		200		{object}	api.ChatHistoryResponse
+//	@Failure		404		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/history/{jobid} [get]
+func GetChatHistoryJobController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ChatHistoryResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	id := library.GetRequestParameter(r, "jobid")
+	job, ok := server.GetHistoryJob(id)
+	if !ok {
+		response.ParseError(fmt.Errorf("history job not found: %s", id))
+		RespondInterfaceCode(w, response, http.StatusNotFound)
+		return
+	}
+
+	response.Job = job
+	RespondSuccess(w, response)
+}
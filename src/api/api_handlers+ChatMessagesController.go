@@ -0,0 +1,128 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// GetChatMessagesController lists the persisted history of a chat.
+//
+//	@Summary		List chat messages
+//	@Description	Returns messages from the durable message store (MESSAGESTORE=true), newest first, with cursor pagination. Includes attachments metadata, reactions and previous texts of edited messages.
+//	@Tags			Chats
+//	@Produce		json
+//	@Param			chatid	path		string	true	"Chat id"
+//	@Param			cursor	query		string	false	"Cursor returned as next by the previous page"
+//	@Param			before	query		int		false	"Only messages before this unix timestamp (seconds)"
+//	@Param			after	query		int		false	"Only messages after this unix timestamp (seconds)"
+//	@Param			type	query		string	false	"Comma separated message types (text, image, audio, ...)"
+//	@Param			order	query		string	false	"desc (default) or asc"
+//	@Param			limit	query		int		false	"Page size, default 50, max 500"
+//	@Success		200		{object}	api.ChatMessagesResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/{chatid}/messages [get]
+func GetChatMessagesController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ChatMessagesResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	query, err := GetStoredMessagesQuery(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	messages, next, err := server.GetStoredMessages(query)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	response.ChatId = query.ChatId
+	response.Total = len(messages)
+	response.Messages = messages
+	response.Next = next
+	RespondSuccess(w, response)
+}
+
+// GetStoredMessagesQuery reads stored messages filters from request
+func GetStoredMessagesQuery(r *http.Request) (query models.QpStoredMessagesQuery, err error) {
+	chatid := library.GetChatId(r)
+	if len(chatid) == 0 {
+		return query, fmt.Errorf("missing chatid parameter")
+	}
+
+	query.ChatId, err = whatsapp.FormatEndpoint(chatid)
+	if err != nil {
+		return query, err
+	}
+
+	query.Cursor = library.GetRequestParameter(r, "cursor")
+
+	before, err := StringToTimestamp(library.GetRequestParameter(r, "before"))
+	if err != nil {
+		return query, fmt.Errorf("invalid before parameter: %s", err.Error())
+	}
+	if before > 0 {
+		query.Before = time.Unix(before, 0)
+	}
+
+	after, err := StringToTimestamp(library.GetRequestParameter(r, "after"))
+	if err != nil {
+		return query, fmt.Errorf("invalid after parameter: %s", err.Error())
+	}
+	if after > 0 {
+		query.After = time.Unix(after, 0)
+	}
+
+	query.Types, err = GetMessageTypesParameter(r)
+	if err != nil {
+		return query, err
+	}
+
+	query.Ascending = strings.EqualFold(library.GetRequestParameter(r, "order"), "asc")
+
+	if limit := library.GetRequestParameter(r, "limit"); len(limit) > 0 {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return query, fmt.Errorf("invalid limit parameter: %s", err.Error())
+		}
+	}
+
+	return query, nil
+}
+
+// GetMessageTypesParameter reads comma separated message type names from request
+func GetMessageTypesParameter(r *http.Request) (types []string, err error) {
+	param := library.GetRequestParameter(r, "type")
+	if len(param) == 0 {
+		return
+	}
+
+	for _, name := range strings.Split(param, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := whatsapp.ParseWhatsappMessageType(name); !ok {
+			return nil, fmt.Errorf("invalid message type: %s", name)
+		}
+		types = append(types, name)
+	}
+	return
+}
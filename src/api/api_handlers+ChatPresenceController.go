@@ -64,7 +64,7 @@ func ChatPresenceWithServer(w http.ResponseWriter, r *http.Request, server *mode
 	}
 
 	// Format and validate the chat ID
-	formattedChatId, err := whatsapp.FormatEndpoint(request.ChatId)
+	formattedChatId, err := server.FormatEndpoint(request.ChatId)
 	if err != nil {
 		response.ParseError(fmt.Errorf("invalid chatid: %v", err))
 		RespondInterface(w, response)
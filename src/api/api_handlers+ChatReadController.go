@@ -60,7 +60,7 @@ func MarkChatAsReadController(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Format and validate the chat ID
-	formattedChatId, err := whatsapp.FormatEndpoint(request.ChatId)
+	formattedChatId, err := server.FormatEndpoint(request.ChatId)
 	if err != nil {
 		response.ParseError(fmt.Errorf("invalid chatid: %v", err))
 		RespondInterface(w, response)
@@ -146,7 +146,7 @@ func MarkChatAsUnreadController(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Format and validate the chat ID
-	formattedChatId, err := whatsapp.FormatEndpoint(request.ChatId)
+	formattedChatId, err := server.FormatEndpoint(request.ChatId)
 	if err != nil {
 		response.ParseError(fmt.Errorf("invalid chatid: %v", err))
 		RespondInterface(w, response)
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// GetChatsController lists the chats of the session.
+//
+//	@Summary		List chats
+//	@Description	Returns the chat list from the durable message store (MESSAGESTORE=true) with title, last message preview, unread count, archived, pinned and muted flags, labels and disappearing messages timer. Kept in sync from history sync, live messages and app state events.
+//	@Tags			Chats
+//	@Produce		json
+//	@Param			sort		query		string	false	"timestamp (default, pinned first), unread or title"
+//	@Param			order		query		string	false	"desc (default) or asc"
+//	@Param			archived	query		bool	false	"Only archived (true) or not archived (false) chats"
+//	@Param			offset		query		int		false	"Chats to skip"
+//	@Param			limit		query		int		false	"Page size, default 50, max 500"
+//	@Success		200			{object}	api.ChatsResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats [get]
+func GetChatsController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ChatsResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	query, err := GetStoredChatsQuery(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	chats, total, err := server.GetStoredChats(query)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	query.Normalize()
+	response.Total = total
+	response.Offset = query.Offset
+	response.Limit = query.Limit
+	response.Chats = chats
+	RespondSuccess(w, response)
+}
+
+// GetStoredChatsQuery reads chat list sorting and pagination from request
+func GetStoredChatsQuery(r *http.Request) (query models.QpStoredChatsQuery, err error) {
+	query.Sort = library.GetRequestParameter(r, "sort")
+	switch strings.ToLower(query.Sort) {
+	case "", "timestamp", "unread", "title":
+	default:
+		return query, fmt.Errorf("invalid sort parameter: %s", query.Sort)
+	}
+
+	query.Ascending = strings.EqualFold(library.GetRequestParameter(r, "order"), "asc")
+
+	if archived := library.GetRequestParameter(r, "archived"); len(archived) > 0 {
+		value, err := strconv.ParseBool(archived)
+		if err != nil {
+			return query, fmt.Errorf("invalid archived parameter: %s", err.Error())
+		}
+		query.Archived = &value
+	}
+
+	if offset := library.GetRequestParameter(r, "offset"); len(offset) > 0 {
+		query.Offset, err = strconv.Atoi(offset)
+		if err != nil {
+			return query, fmt.Errorf("invalid offset parameter: %s", err.Error())
+		}
+	}
+
+	if limit := library.GetRequestParameter(r, "limit"); len(limit) > 0 {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return query, fmt.Errorf("invalid limit parameter: %s", err.Error())
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// ContactPurgeController removes every data of a contact, right to be forgotten
+//
+//	@Summary		Purge contact data
+//	@Description	Removes all data of a contact, given by phone and/or lid: cached messages and media, stored messages and reactions, chat list entries, conversation labels, whatsapp contact store entries, chat exports and pending dispatches on the retry queue. Applies to the session of the token, or to all sessions of the authenticated user with all=true. Returns a report of what was purged.
+//	@Tags			Contacts
+//	@Produce		json
+//	@Param			phone	query		string	false	"Phone number (E164) or user jid"
+//	@Param			lid		query		string	false	"Contact lid"
+//	@Param			all		query		bool	false	"Purge on all sessions of the user"
+//	@Success		200		{object}	api.ContactPurgeResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		403		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/contacts/purge [post]
+func ContactPurgeController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ContactPurgeResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	servers := []*models.QpWhatsappServer{server}
+	if strings.EqualFold(library.GetRequestParameter(r, "all"), "true") {
+		if _, scoped := getScopedSessionToken(r); scoped {
+			response.ParseError(fmt.Errorf("session scoped access can not purge other sessions"))
+			RespondInterfaceCode(w, response, http.StatusForbidden)
+			return
+		}
+
+		user, err := GetAuthenticatedUser(r)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterfaceCode(w, response, http.StatusUnauthorized)
+			return
+		}
+
+		servers = servers[:0]
+		for _, item := range models.GetServersForUser(user) {
+			servers = append(servers, item)
+		}
+	}
+
+	phone := library.GetRequestParameter(r, "phone")
+	lid := library.GetRequestParameter(r, "lid")
+	report, err := models.PurgeContact(phone, lid, servers)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	response.Report = report
+	response.ParseSuccess(fmt.Sprintf("contact purged from %d sessions", len(report.Sessions)))
+	RespondInterface(w, response)
+}
@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -322,31 +321,17 @@ func SetGroupPhotoController(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Download the image from the URL
-	httpClient := &http.Client{
-		Timeout: time.Second * 30, // Set a timeout for the request
-	}
-
-	resp, err := httpClient.Get(t.ImageURL)
+	result, err := library.SafeFetch(r.Context(), t.ImageURL, library.FetchOptions{
+		Timeout:      time.Second * 30, // Set a timeout for the request
+		ContentTypes: []string{"image/"},
+	})
 	if err != nil {
 		response.ParseError(fmt.Errorf("failed to download image: %v", err))
 		RespondInterface(w, response)
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		response.ParseError(fmt.Errorf("failed to download image, server returned status: %s", resp.Status))
-		RespondInterface(w, response)
-		return
-	}
 
-	// Read the image data
-	imageData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		response.ParseError(fmt.Errorf("failed to read image data: %v", err))
-		RespondInterface(w, response)
-		return
-	}
+	imageData := result.Content
 
 	// Convert image to JPEG (WhatsApp requires JPEG for group photos)
 	convertedData, err := media.ConvertToJpeg(imageData)
@@ -178,6 +178,12 @@ func InformationPostRequest(w http.ResponseWriter, r *http.Request) {
 	} else {
 		// CREATE: Server doesn't exist, create new one
 		patch := buildSessionConfigurationPatch(request)
+		if err := runtime.ValidateSessionConfigurationPatch(patch); err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
 		info := runtime.BuildSessionRecord(token, username, patch)
 
 		server, err := runtime.CreateSessionRecord(info, "server created without connection via POST")
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - LINK PREVIEW
+
+/*
+<summary>
+
+	Renders route GET|PUT|DELETE "/media/linkpreview"
+
+	Url parameters: ?url={url}, also accepted on the PUT body
+	Curated previews belong to the session of the token, other sessions never use them
+	Refresh (GET): ?refresh=true fetches again, ignoring the cached preview
+
+</summary>
+*/
+// LinkPreviewController inspects, curates and refreshes the link previews used on sends
+//
+//	@Summary		Manage link previews
+//	@Description	GET returns the preview of an url (curated, cached or fetched), PUT stores a curated preview used by sends of this session with that url and preview set, DELETE removes the curated preview of this session and the cached one
+//	@Tags			Media
+//	@Accept			json
+//	@Produce		json
+//	@Param			url		query		string																false	"Previewed url"
+//	@Param			refresh	query		string																false	"Fetch again, ignoring the cached preview"
+//	@Param			request	body		object{url=string,title=string,description=string,image_url=string,thumbnail=string}	false	"Curated preview (for PUT), thumbnail as base64, downloaded from image_url when empty"
+//	@Success		200		{object}	api.LinkPreviewResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/media/linkpreview [get]
+//	@Router			/media/linkpreview [put]
+//	@Router			/media/linkpreview [delete]
+func LinkPreviewController(w http.ResponseWriter, r *http.Request) {
+	response := &apiModels.LinkPreviewResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	logger := server.GetLogger()
+	url := strings.TrimSpace(r.URL.Query().Get("url"))
+
+	// curated previews are scoped by session, never shared between tenants
+	owner := server.GetWId()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		preview := &library.LinkPreview{}
+		if len(body) > 0 {
+			if err = json.Unmarshal(body, preview); err != nil {
+				jsonError := fmt.Errorf("error converting body to json: %v", err.Error())
+				response.ParseError(jsonError)
+				RespondInterface(w, response)
+				return
+			}
+		}
+
+		if len(url) > 0 {
+			preview.URL = url
+		}
+
+		if len(preview.URL) == 0 {
+			response.ParseError(fmt.Errorf("missing url parameter"))
+			RespondInterface(w, response)
+			return
+		}
+
+		if len(preview.Thumbnail) == 0 && len(preview.ImageURL) > 0 {
+			thumbnail, err := library.DownloadImage(preview.ImageURL)
+			if err != nil {
+				response.ParseError(err)
+				RespondInterface(w, response)
+				return
+			}
+			preview.Thumbnail = thumbnail
+		}
+
+		if err = library.SetCuratedLinkPreview(owner, preview); err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		logger.Infof("curated link preview updated, url: %s", preview.URL)
+		response.Preview = preview
+		response.ParseSuccess("updated with success")
+		RespondSuccess(w, response)
+		return
+	}
+
+	if len(url) == 0 {
+		response.ParseError(fmt.Errorf("missing url parameter"))
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if err = library.DeleteLinkPreview(owner, url); err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		logger.Infof("link preview removed, url: %s", url)
+		response.ParseSuccess("deleted with success")
+		RespondSuccess(w, response)
+		return
+	}
+
+	refresh := models.ToBoolean(library.GetRequestParameter(r, "refresh"))
+	preview, err := library.GetLinkPreview(owner, url, refresh)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Preview = preview
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -179,6 +179,58 @@ func EditMessageController(w http.ResponseWriter, r *http.Request) {
 	RespondSuccess(w, response)
 }
 
+// GetMessageReceiptsController lists the per participant receipts of a message sent to a group
+//
+//	@Summary		Get message receipts
+//	@Description	Returns delivered, read and played receipts per participant of a message sent by this session to a group, while it remains on cache
+//	@Tags			Message
+//	@Produce		json
+//	@Param			id	query		string	true	"Message ID"
+//	@Success		200	{object}	api.MessageReceiptsResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Failure		404	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/messages/receipts [get]
+func GetMessageReceiptsController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.MessageReceiptsResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	messageid := GetMessageId(r)
+	if len(messageid) == 0 {
+		response.ParseError(fmt.Errorf("empty message id"))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	msg, err := server.Handler.GetById(messageid)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusNotFound)
+		return
+	}
+
+	response.Id = msg.Id
+	response.ChatId = msg.Chat.Id
+	response.Status = msg.Status
+	if msg.Receipts != nil {
+		response.Recipients = msg.Receipts.Recipients
+		response.Delivered = msg.Receipts.CountDelivered()
+		response.Read = msg.Receipts.CountRead()
+		response.Played = msg.Receipts.CountPlayed()
+		response.Participants = msg.Receipts.Participants
+	}
+
+	RespondSuccess(w, response)
+}
+
 //endregion
 
 // MarkReadController marks one or more messages as read on the WhatsApp connection
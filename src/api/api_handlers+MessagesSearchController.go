@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// SearchMessagesController runs a full text search over the stored messages of this session.
+//
+//	@Summary		Search messages
+//	@Description	Ranked full text search over text, captions, filenames and contact names of stored messages (MESSAGESTORE=true), including history sync imports. Matched terms are enclosed in brackets on snippets.
+//	@Tags			Message
+//	@Produce		json
+//	@Param			q		query		string	true	"Search text, every term is matched as prefix"
+//	@Param			chatid	query		string	false	"Only messages from this chat"
+//	@Param			from	query		int		false	"Only messages since this unix timestamp (seconds)"
+//	@Param			to		query		int		false	"Only messages until this unix timestamp (seconds)"
+//	@Param			type	query		string	false	"Comma separated message types (text, image, document, ...)"
+//	@Param			limit	query		int		false	"Max hits, default 20, max 100"
+//	@Param			offset	query		int		false	"Ranked hits to skip, for paging"
+//	@Success		200		{object}	api.MessagesSearchResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/messages/search [get]
+func SearchMessagesController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.MessagesSearchResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	query, err := GetMessagesSearchQuery(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	hits, err := server.SearchStoredMessages(query)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	response.Query = query.Text
+	response.Total = len(hits)
+	response.Hits = hits
+	RespondSuccess(w, response)
+}
+
+// GetMessagesSearchQuery reads search filters from request
+func GetMessagesSearchQuery(r *http.Request) (query models.QpMessagesSearchQuery, err error) {
+	query.Text = library.GetRequestParameter(r, "q")
+	if len(query.Text) == 0 {
+		return query, fmt.Errorf("missing q parameter")
+	}
+
+	if chatid := library.GetChatId(r); len(chatid) > 0 {
+		query.ChatId, err = whatsapp.FormatEndpoint(chatid)
+		if err != nil {
+			return query, err
+		}
+	}
+
+	from, err := StringToTimestamp(library.GetRequestParameter(r, "from"))
+	if err != nil {
+		return query, fmt.Errorf("invalid from parameter: %s", err.Error())
+	}
+	if from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+
+	to, err := StringToTimestamp(library.GetRequestParameter(r, "to"))
+	if err != nil {
+		return query, fmt.Errorf("invalid to parameter: %s", err.Error())
+	}
+	if to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+
+	query.Types, err = GetMessageTypesParameter(r)
+	if err != nil {
+		return query, err
+	}
+
+	if limit := library.GetRequestParameter(r, "limit"); len(limit) > 0 {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return query, fmt.Errorf("invalid limit parameter: %s", err.Error())
+		}
+	}
+
+	if offset := library.GetRequestParameter(r, "offset"); len(offset) > 0 {
+		query.Offset, err = strconv.Atoi(offset)
+		if err != nil {
+			return query, fmt.Errorf("invalid offset parameter: %s", err.Error())
+		}
+	}
+
+	return query, nil
+}
@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 
@@ -61,7 +60,7 @@ func PictureController(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	chatId, err = whatsapp.FormatEndpoint(chatId)
+	chatId, err = server.FormatEndpoint(chatId)
 	if err != nil {
 		chatIdErr := fmt.Errorf("invalid chatId: %s", err.Error())
 		response.ParseError(chatIdErr)
@@ -79,15 +78,7 @@ func PictureController(w http.ResponseWriter, r *http.Request) {
 	if info != nil {
 		response.Info = info
 		if strings.Contains(r.URL.Path, "picdata") {
-			resp, err := http.Get(response.Info.Url)
-			if err != nil {
-				response.ParseError(err)
-				RespondInterface(w, response)
-				return
-			}
-			defer resp.Body.Close()
-
-			content, err := io.ReadAll(resp.Body)
+			content, err := response.Info.Download()
 			if err != nil {
 				response.ParseError(err)
 				RespondInterface(w, response)
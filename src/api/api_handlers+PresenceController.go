@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	runtime "github.com/nocodeleaks/quepasa/runtime"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// PresenceSubscribeRequest defines the contact to receive presence updates from
+type PresenceSubscribeRequest struct {
+	ChatId string `json:"chatid"`
+}
+
+// PresenceWebhooksRequest enables or disables the dispatching of presence updates to webhooks
+type PresenceWebhooksRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// PresenceSubscribeController subscribes to online and last seen updates of a contact.
+//
+//	@Summary		Subscribe contact presence
+//	@Description	Asks WhatsApp for presence updates (online, offline and last seen) of a contact. Updates reach cable/SignalR subscribers, and webhooks when enabled. Without a privacy token, usually issued after the contact messages us, WhatsApp ignores the subscription.
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		PresenceSubscribeRequest	false	"Contact to subscribe, chatid may also be informed as parameter"
+//	@Success		200		{object}	api.PresenceSubscribeResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/presence/subscribe [post]
+func PresenceSubscribeController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.PresenceSubscribeResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+		return
+	}
+
+	request := &PresenceSubscribeRequest{}
+	if r.ContentLength > 0 {
+		if err = json.NewDecoder(r.Body).Decode(request); err != nil {
+			response.ParseError(fmt.Errorf("invalid request body: %w", err))
+			RespondInterfaceCode(w, response, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if len(request.ChatId) == 0 {
+		request.ChatId = library.GetChatId(r)
+	}
+
+	if len(request.ChatId) == 0 {
+		response.ParseError(fmt.Errorf("chatid is required"))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	chatId, err := server.FormatEndpoint(request.ChatId)
+	if err != nil {
+		response.ParseError(fmt.Errorf("invalid chatid: %v", err))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	tokened, err := server.SubscribePresence(chatId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ChatId = chatId
+	response.PrivacyToken = tokened
+	RespondSuccess(w, response)
+}
+
+// GetPresenceWebhooksController returns whether presence updates are dispatched to webhooks.
+//
+//	@Summary		Get presences dispatching
+//	@Description	Returns whether presence updates (typing, recording, paused, online and offline) are dispatched to webhooks. Realtime subscribers always receive them.
+//	@Tags			Chat
+//	@Produce		json
+//	@Success		200	{object}	api.PresenceWebhooksResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/presence/webhooks [get]
+func GetPresenceWebhooksController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.PresenceWebhooksResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	enabled, err := runtime.GetSessionPresences(server)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Enabled = enabled
+	RespondSuccess(w, response)
+}
+
+// UpdatePresenceWebhooksController enables or disables the dispatching of presence updates to webhooks.
+//
+//	@Summary		Update presences dispatching
+//	@Description	Enables or disables the dispatching of presence updates to webhooks for this session.
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		PresenceWebhooksRequest	true	"Presences dispatching option"
+//	@Success		200		{object}	api.PresenceWebhooksResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/presence/webhooks [put]
+func UpdatePresenceWebhooksController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.PresenceWebhooksResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	var request PresenceWebhooksRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ParseError(fmt.Errorf("invalid request body: %w", err))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	if err = runtime.SetSessionPresences(server, request.Enabled); err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Enabled = request.Enabled
+	RespondSuccess(w, response)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	media "github.com/nocodeleaks/quepasa/media"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - PREVIEW
+
+// preview size served by the api, larger than the thumbnails embedded on messages
+var previewThumbnailConfig = media.ThumbnailConfig{
+	MaxWidth:  480,
+	MaxHeight: 480,
+	Quality:   80,
+	MaxBytes:  65536,
+}
+
+/*
+<summary>
+
+	Renders route GET "/media/preview"
+
+	Any of then, at this order of priority
+	Url parameters: ?messageid={messageid} || ?id={messageid}
+	Header parameters: X-QUEPASA-MESSAGEID = {messageid}
+
+</summary>
+*/
+// PreviewController renders a preview of the media of a message
+//	@Summary		Preview media
+//	@Description	Renders a JPEG preview of the first page of pdf and office documents (page count on X-QUEPASA-PAGECOUNT header), or of images and videos
+//	@Tags			Media
+//	@Produce		image/jpeg
+//	@Param			messageid	query		string	false	"Message ID"
+//	@Param			cache		query		string	false	"Use cached content"
+//	@Success		200			{file}		binary	"JPEG preview"
+//	@Failure		400			{object}	models.QpResponse
+//	@Failure		415			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/media/preview [get]
+func PreviewController(w http.ResponseWriter, r *http.Request) {
+
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Checking for ready state
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+		return
+	}
+
+	messageid := GetMessageId(r)
+	if len(messageid) == 0 {
+		err := fmt.Errorf("empty message id")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	cache := GetCache(r)
+
+	att, err := server.Download(messageid, cache)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if !media.CanGeneratePreview(att.Mimetype, att.FileName) {
+		err = fmt.Errorf("preview not available for mime type: %s", att.Mimetype)
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	preview, err := media.GeneratePreview(*att.GetContent(), att.Mimetype, att.FileName, previewThumbnailConfig)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if preview.PageCount > 0 {
+		w.Header().Set("X-QUEPASA-PAGECOUNT", fmt.Sprint(preview.PageCount))
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", messageid+".jpg"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(preview.Thumbnail)
+}
+
+//endregion
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// getReadyServerForPrivacy returns the live server only when it is ready to talk with WhatsApp servers
+func getReadyServerForPrivacy(w http.ResponseWriter, r *http.Request, response *apiModels.PrivacyResponse) *models.QpWhatsappServer {
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return nil
+	}
+
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+		return nil
+	}
+
+	return server
+}
+
+// GetPrivacyController returns the account privacy settings of this session.
+//
+//	@Summary		Get privacy settings
+//	@Description	Returns who can see last seen, online, profile photo, about and status, the read receipts setting and who can add this account to groups or call it.
+//	@Tags			Account
+//	@Produce		json
+//	@Success		200	{object}	api.PrivacyResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Failure		503	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/account/privacy [get]
+func GetPrivacyController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.PrivacyResponse{}
+
+	server := getReadyServerForPrivacy(w, r, response)
+	if server == nil {
+		return
+	}
+
+	settings, err := server.GetPrivacyManager().GetPrivacySettings()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Settings = settings
+	RespondSuccess(w, response)
+}
+
+// UpdatePrivacyController changes one or more account privacy settings of this session.
+//
+//	@Summary		Update privacy settings
+//	@Description	Changes the informed privacy settings, empty fields are kept as is. The status audience can only be changed from the phone.
+//	@Tags			Account
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		whatsapp.WhatsappPrivacySettings	true	"Settings to change"
+//	@Success		200		{object}	api.PrivacyResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/account/privacy [put]
+func UpdatePrivacyController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.PrivacyResponse{}
+
+	var request whatsapp.WhatsappPrivacySettings
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ParseError(fmt.Errorf("invalid request body: %w", err))
+		RespondInterface(w, response)
+		return
+	}
+
+	// validating everything before changing anything, status included as it is read only
+	if len(request.Status) > 0 {
+		if err := whatsapp.ValidatePrivacySettingChange(whatsapp.PrivacySettingStatus, request.Status); err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	changes := []whatsapp.WhatsappPrivacySettingType{}
+	for _, setting := range whatsapp.WhatsappPrivacySettingsWritable {
+		value := request.Get(setting)
+		if len(value) == 0 {
+			continue
+		}
+
+		if err := whatsapp.ValidatePrivacySettingChange(setting, value); err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+		changes = append(changes, setting)
+	}
+
+	if len(changes) == 0 {
+		response.ParseError(fmt.Errorf("at least one privacy setting is required"))
+		RespondInterface(w, response)
+		return
+	}
+
+	server := getReadyServerForPrivacy(w, r, response)
+	if server == nil {
+		return
+	}
+
+	// applied in a fixed order, so a failure leaves a predictable set of changes
+	manager := server.GetPrivacyManager()
+	applied := []string{}
+	for _, setting := range changes {
+		settings, err := manager.SetPrivacySetting(setting, request.Get(setting))
+		if err != nil {
+			response.Settings = getFinalPrivacySettings(manager, settings)
+			response.ParseError(fmt.Errorf("error on updating privacy setting '%s', applied: [%s]: %w", setting, strings.Join(applied, ","), err))
+			RespondInterface(w, response)
+			return
+		}
+		response.Settings = settings
+		applied = append(applied, string(setting))
+	}
+
+	response.Settings = getFinalPrivacySettings(manager, response.Settings)
+	RespondSuccess(w, response)
+}
+
+// getFinalPrivacySettings returns the settings after all changes, falling back to the last known ones
+func getFinalPrivacySettings(manager whatsapp.WhatsappPrivacyManagerInterface, last *whatsapp.WhatsappPrivacySettings) *whatsapp.WhatsappPrivacySettings {
+	settings, err := manager.GetPrivacySettings()
+	if err != nil {
+		return last
+	}
+	return settings
+}
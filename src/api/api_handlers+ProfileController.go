@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
+	media "github.com/nocodeleaks/quepasa/media"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// ProfileUpdateRequest changes the push name and/or the about text, empty fields are kept as is
+type ProfileUpdateRequest struct {
+	PushName string  `json:"pushname,omitempty"`
+	About    *string `json:"about,omitempty"` // pointer, so an empty about can be set
+}
+
+// ProfilePictureRequest carries the new photo, from a public url or a base64 (data uri) content
+type ProfilePictureRequest struct {
+	Url     string `json:"url,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// getReadyServerForProfile returns the live server only when it is ready to talk with WhatsApp servers
+func getReadyServerForProfile(w http.ResponseWriter, r *http.Request, response *apiModels.ProfileResponse) *models.QpWhatsappServer {
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return nil
+	}
+
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+		return nil
+	}
+
+	return server
+}
+
+// UpdateProfileController changes the push name and/or the about text of this session.
+//
+//	@Summary		Update own profile
+//	@Description	Changes the push name (display name) and/or the about text of this session, empty fields are kept as is. Send an empty about to clear it.
+//	@Tags			Account
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.ProfileUpdateRequest	true	"Profile fields to change"
+//	@Success		200		{object}	api.ProfileResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/account/profile [put]
+func UpdateProfileController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ProfileResponse{}
+
+	var request ProfileUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ParseError(fmt.Errorf("invalid request body: %w", err))
+		RespondInterface(w, response)
+		return
+	}
+
+	request.PushName = strings.TrimSpace(request.PushName)
+	if len(request.PushName) == 0 && request.About == nil {
+		response.ParseError(fmt.Errorf("pushname or about is required"))
+		RespondInterface(w, response)
+		return
+	}
+
+	server := getReadyServerForProfile(w, r, response)
+	if server == nil {
+		return
+	}
+
+	manager := server.GetProfileManager()
+	if len(request.PushName) > 0 {
+		if err := manager.SetPushName(request.PushName); err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+		response.PushName = request.PushName
+	}
+
+	if request.About != nil {
+		if err := manager.SetAbout(*request.About); err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+		response.About = *request.About
+	}
+
+	response.ParseSuccess("profile updated")
+	RespondSuccess(w, response)
+}
+
+// SetProfilePictureController replaces the profile photo of this session.
+//
+//	@Summary		Set own profile picture
+//	@Description	Replaces the profile photo of this session. The image is converted to JPEG, center cropped to a square and resized before upload.
+//	@Tags			Account
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.ProfilePictureRequest	true	"Image url or base64 content"
+//	@Success		200		{object}	api.ProfileResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/account/profile/picture [put]
+func SetProfilePictureController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ProfileResponse{}
+
+	var request ProfilePictureRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ParseError(fmt.Errorf("invalid request body: %w", err))
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.Url) == 0 && len(request.Content) == 0 {
+		response.ParseError(fmt.Errorf("url or content is required"))
+		RespondInterface(w, response)
+		return
+	}
+
+	server := getReadyServerForProfile(w, r, response)
+	if server == nil {
+		return
+	}
+
+	imageData, err := request.GetImageData()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	imageData, err = media.PrepareProfilePicture(imageData)
+	if err != nil {
+		response.ParseError(fmt.Errorf("failed to prepare profile picture: %w", err))
+		RespondInterface(w, response)
+		return
+	}
+
+	pictureID, err := server.GetProfileManager().SetProfilePicture(imageData)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.PictureId = pictureID
+	response.ParseSuccess("profile picture updated")
+	RespondSuccess(w, response)
+}
+
+// RemoveProfilePictureController removes the profile photo of this session.
+//
+//	@Summary		Remove own profile picture
+//	@Description	Removes the profile photo of this session
+//	@Tags			Account
+//	@Produce		json
+//	@Success		200	{object}	api.ProfileResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Failure		503	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/account/profile/picture [delete]
+func RemoveProfilePictureController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.ProfileResponse{}
+
+	server := getReadyServerForProfile(w, r, response)
+	if server == nil {
+		return
+	}
+
+	if err := server.GetProfileManager().RemoveProfilePicture(); err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess("profile picture removed")
+	RespondSuccess(w, response)
+}
+
+// GetImageData returns the raw image bytes, decoding the content or downloading the url
+func (source *ProfilePictureRequest) GetImageData() ([]byte, error) {
+	if len(source.Content) > 0 {
+		content := source.Content
+		if strings.HasPrefix(content, "data:") {
+			parts := strings.SplitN(content, ",", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid data uri format for picture content")
+			}
+			content = parts[1]
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding base64 picture content: %w", err)
+		}
+		return decoded, nil
+	}
+
+	result, err := library.SafeFetch(context.Background(), source.Url, library.FetchOptions{
+		MaxSize:      10 * 1024 * 1024,
+		Timeout:      time.Second * 30,
+		ContentTypes: []string{"image/"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+
+	return result.Content, nil
+}
@@ -28,6 +28,8 @@ import (
 //	@Description	- Location (field "location") — send location with latitude/longitude in the "location" object
 //	@Description	- Contact (field "contact") — send contact with phone/name in the "contact" object
 //	@Description	- Sticker (field "sticker") — send a sticker from URL or base64 content; auto-converted to WebP 512×512 via FFmpeg
+//	@Description	- Product (field "product") — send a product of the business catalog, an image "url"/"content" becomes the product image
+//	@Description	- Catalog (field "catalog") — send the link to the business catalog of this session
 //	@Description
 //	@Description	Main fields:
 //	@Description	- chatId: chat identifier (can be WID, LID or number with suffix @s.whatsapp.net)
@@ -39,6 +41,8 @@ import (
 //	@Description	- location: JSON object with location data (latitude, longitude, name, address, url)
 //	@Description	- contact: JSON object with contact data (phone, name, vcard)
 //	@Description	- sticker: JSON object with sticker source (url or content as base64/data URI)
+//	@Description	- product: JSON object with product data (id, name, description, price, currency, retailerid, url)
+//	@Description	- catalog: JSON object with link preview data (title, description)
 //	@Description
 //	@Description	Location object fields:
 //	@Description	- latitude (float64, required): Location latitude in degrees (e.g.: -23.550520)
@@ -58,6 +62,7 @@ import (
 //	@Description	Note: images and videos are automatically converted to WebP 512×512 using FFmpeg.
 //	@Description	Animated formats (video, gif, apng) produce animated WebP stickers (max 10s, 15fps).
 //	@Description	Static images produce static WebP stickers.
+//	@Description	Lottie animations (application/json, .tgs) are not supported and are rejected.
 //	@Description
 //	@Description	Examples:
 //	@Description	Text:
@@ -178,7 +183,7 @@ func SendAnyWithServer(w http.ResponseWriter, r *http.Request, server *models.Qp
 	}
 
 	// Getting ChatId parameter
-	err := request.EnsureValidChatId(r)
+	err := request.EnsureValidChatId(r, server.GetRegion())
 	if err != nil {
 		MessageSendErrors.Inc()
 		response.ParseError(err)
@@ -222,7 +227,7 @@ func SendAnyWithServer(w http.ResponseWriter, r *http.Request, server *models.Qp
 
 	// Generate link preview when requested and message has text with a URL but no attachment
 	if request.Preview && len(request.Text) > 0 && len(request.Content) == 0 {
-		handleLinkPreview(request, response)
+		handleLinkPreview(server.GetWId(), request, response)
 	}
 
 	SendRequest(w, r, &request.SendRequest, server)
@@ -230,11 +235,11 @@ func SendAnyWithServer(w http.ResponseWriter, r *http.Request, server *models.Qp
 
 //endregion
 
-// handleLinkPreview fetches Open Graph metadata for the first URL found in
-// request.Text and populates request.SendRequest.LinkPreview. Custom override
-// fields (PreviewTitle, PreviewDesc, PreviewThumb) take precedence over the
-// fetched values. Errors are non-fatal and appended to response.Debug.
-func handleLinkPreview(request *apiModels.SendAnyRequest, response *apiModels.SendResponse) {
+// handleLinkPreview gets the preview of the first URL found in request.Text, curated
+// by the owner session, cached or fetched, and populates request.SendRequest.LinkPreview.
+// Custom override fields (PreviewTitle, PreviewDesc, PreviewThumb) take precedence
+// over the preview values. Errors are non-fatal and appended to response.Debug.
+func handleLinkPreview(owner string, request *apiModels.SendAnyRequest, response *apiModels.SendResponse) {
 	urlToPreview := library.ExtractURLFromText(request.Text)
 	if urlToPreview == "" {
 		response.Debug = append(response.Debug, "[debug][handleLinkPreview] no URL found in text, skipping preview")
@@ -242,40 +247,41 @@ func handleLinkPreview(request *apiModels.SendAnyRequest, response *apiModels.Se
 	}
 	response.Debug = append(response.Debug, "[debug][handleLinkPreview] found URL: "+urlToPreview)
 
-	ogData, err := library.FetchOpenGraph(urlToPreview)
+	linkPreview, err := library.GetLinkPreview(owner, urlToPreview, false)
 	if err != nil {
 		response.Debug = append(response.Debug, "[warn][handleLinkPreview] failed to fetch OG data: "+err.Error())
 		return
 	}
 
+	if linkPreview.Curated {
+		response.Debug = append(response.Debug, "[debug][handleLinkPreview] using curated preview for: "+urlToPreview)
+	}
+
 	preview := &whatsapp.WhatsappMessageUrl{Reference: urlToPreview}
 
 	if request.PreviewTitle != "" {
 		preview.Title = request.PreviewTitle
-	} else if ogData.Title != "" {
-		preview.Title = ogData.Title
+	} else if linkPreview.Title != "" {
+		preview.Title = linkPreview.Title
 	}
 
 	if request.PreviewDesc != "" {
 		preview.Description = request.PreviewDesc
-	} else if ogData.Description != "" {
-		preview.Description = ogData.Description
-	}
-
-	thumbnailURL := request.PreviewThumb
-	if thumbnailURL == "" {
-		thumbnailURL = ogData.ImageURL
+	} else if linkPreview.Description != "" {
+		preview.Description = linkPreview.Description
 	}
 
-	if thumbnailURL != "" {
-		response.Debug = append(response.Debug, "[debug][handleLinkPreview] downloading thumbnail: "+thumbnailURL)
-		thumbData, err := library.DownloadImage(thumbnailURL)
+	if request.PreviewThumb != "" {
+		response.Debug = append(response.Debug, "[debug][handleLinkPreview] downloading thumbnail: "+request.PreviewThumb)
+		thumbData, err := library.DownloadImage(request.PreviewThumb)
 		if err != nil {
 			response.Debug = append(response.Debug, "[warn][handleLinkPreview] failed to download thumbnail: "+err.Error())
 		} else {
 			preview.SetThumbnail(thumbData)
 			response.Debug = append(response.Debug, fmt.Sprintf("[debug][handleLinkPreview] thumbnail downloaded: %d bytes", len(thumbData)))
 		}
+	} else if len(linkPreview.Thumbnail) > 0 {
+		preview.SetThumbnail(linkPreview.Thumbnail)
 	}
 
 	request.SendRequest.LinkPreview = preview
@@ -289,7 +295,7 @@ func SendRequest(w http.ResponseWriter, r *http.Request, request *apiModels.Send
 	response := &apiModels.SendResponse{}
 	var err error
 
-	att := request.ToWhatsappAttachment()
+	att := request.ToWhatsappAttachment(server.GetAttachmentOptions())
 
 	// if not set, try to recover "text"
 	if len(request.Text) == 0 {
@@ -319,7 +325,7 @@ func SendRequest(w http.ResponseWriter, r *http.Request, request *apiModels.Send
 		att.Attach = stickerAttach
 	}
 
-	if request.Poll == nil && request.Location == nil && request.Contact == nil && request.Sticker == nil && att.Attach == nil && len(request.Text) == 0 {
+	if request.Poll == nil && request.Location == nil && request.Contact == nil && request.Sticker == nil && request.Product == nil && request.Catalog == nil && att.Attach == nil && len(request.Text) == 0 {
 		MessageSendErrors.Inc()
 		err = fmt.Errorf("text not found, do not send empty messages")
 		response.ParseError(err)
@@ -333,6 +339,7 @@ func SendRequest(w http.ResponseWriter, r *http.Request, request *apiModels.Send
 	}
 
 	response.Debug = append(response.Debug, att.Debug...)
+	response.Image = att.Image
 	Send(server, response, request, w, att.Attach)
 }
 
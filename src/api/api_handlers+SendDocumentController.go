@@ -81,7 +81,7 @@ func SendDocumentWithServer(w http.ResponseWriter, r *http.Request, server *mode
 	}
 
 	// Getting ChatId parameter
-	err := request.EnsureValidChatId(r)
+	err := request.EnsureValidChatId(r, server.GetRegion())
 	if err != nil {
 		MessageSendErrors.Inc()
 		response.ParseError(err)
@@ -131,7 +131,7 @@ func SendDocumentRequest(w http.ResponseWriter, r *http.Request, request *apiMod
 	response := &apiModels.SendResponse{}
 	var err error
 
-	att := request.ToWhatsappAttachment()
+	att := request.ToWhatsappAttachment(server.GetAttachmentOptions())
 
 	// if not set, try to recover "text"
 	if len(request.Text) == 0 {
@@ -163,6 +163,7 @@ func SendDocumentRequest(w http.ResponseWriter, r *http.Request, request *apiMod
 	}
 
 	response.Debug = append(response.Debug, att.Debug...)
+	response.Image = att.Image
 	SendDocumentToServer(server, response, request, w, att.Attach)
 }
 
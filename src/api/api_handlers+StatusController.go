@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"net/http"
 
+	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
 	models "github.com/nocodeleaks/quepasa/models"
+	runtime "github.com/nocodeleaks/quepasa/runtime"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 )
 
@@ -65,20 +68,142 @@ func PublishStatusController(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conn, err := server.GetValidConnection()
+	msgID, err := server.PublishStatus(request.Text, request.Attachment)
 	if err != nil {
 		response.ParseError(err)
 		RespondInterface(w, response)
 		return
 	}
 
-	msgID, err := conn.PublishStatus(request.Text, request.Attachment)
+	response.ParseSuccess(msgID)
+	RespondInterface(w, response)
+}
+
+// StatusFeedRequest enables or disables the dispatching of contacts statuses.
+type StatusFeedRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// GetStatusViewersController lists the contacts that viewed one of our own status posts.
+//
+//	@Summary		List status viewers
+//	@Description	Returns the contacts that viewed a status published by this session, while it remains on cache. Viewers are collected from read and played receipts.
+//	@Tags			Status
+//	@Produce		json
+//	@Param			id	query		string	true	"Status message id"
+//	@Success		200	{object}	api.StatusViewersResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Failure		404	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/status/viewers [get]
+func GetStatusViewersController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.StatusViewersResponse{}
+
+	server, err := GetServer(r)
 	if err != nil {
 		response.ParseError(err)
 		RespondInterface(w, response)
 		return
 	}
 
-	response.ParseSuccess(msgID)
-	RespondInterface(w, response)
+	id := library.GetRequestParameter(r, "id")
+	if len(id) == 0 {
+		response.ParseError(fmt.Errorf("missing id parameter"))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	msg, err := server.Handler.GetById(id)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusNotFound)
+		return
+	}
+
+	if !msg.FromStatus() || !msg.FromMe {
+		response.ParseError(fmt.Errorf("message is not a status published by this session, id: %s", id))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	response.Id = msg.Id
+	response.Total = len(msg.Viewers)
+	response.Viewers = msg.Viewers
+	RespondSuccess(w, response)
+}
+
+// GetStatusFeedController returns whether contacts statuses are dispatched for this session.
+//
+//	@Summary		Get statuses dispatching
+//	@Description	Returns whether statuses (stories) received from contacts on status@broadcast are dispatched to webhooks and other publishers.
+//	@Tags			Status
+//	@Produce		json
+//	@Success		200	{object}	api.StatusFeedResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/status/feed [get]
+func GetStatusFeedController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.StatusFeedResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	enabled, err := runtime.GetSessionStatuses(server)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Enabled = enabled
+	RespondSuccess(w, response)
+}
+
+// UpdateStatusFeedController enables or disables the dispatching of contacts statuses for this session.
+//
+//	@Summary		Update statuses dispatching
+//	@Description	Enables or disables the dispatching of statuses (stories) received from contacts, even when broadcasts are not handled. Dispatched statuses stay on cache, so their media can be downloaded by id.
+//	@Tags			Status
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		StatusFeedRequest	true	"Statuses dispatching option"
+//	@Success		200		{object}	api.StatusFeedResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/status/feed [put]
+func UpdateStatusFeedController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &apiModels.StatusFeedResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	var request StatusFeedRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ParseError(fmt.Errorf("invalid request body: %w", err))
+		RespondInterfaceCode(w, response, http.StatusBadRequest)
+		return
+	}
+
+	if err = runtime.SetSessionStatuses(server, request.Enabled); err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Enabled = request.Enabled
+	RespondSuccess(w, response)
 }
@@ -92,7 +92,7 @@ func UserInfoController(w http.ResponseWriter, r *http.Request) {
 	// Validate and format JIDs - replace original JIDs with formatted ones
 	for i, jid := range request.JIDs {
 		// Format the JID if it doesn't contain @
-		formattedJID, err := whatsapp.FormatEndpoint(jid)
+		formattedJID, err := server.FormatEndpoint(jid)
 		if err != nil {
 			response.ParseError(fmt.Errorf("invalid JID format for %s: %v", jid, err))
 			RespondInterface(w, response)
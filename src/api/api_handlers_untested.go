@@ -22,7 +22,7 @@ func SendDocumentFromBinary(w http.ResponseWriter, r *http.Request) {
 	request := &apiModels.SendRequest{}
 
 	// Getting ChatId parameter
-	err = request.EnsureValidChatId(r)
+	err = request.EnsureValidChatId(r, server.GetRegion())
 	if err != nil {
 		MessageSendErrors.Inc()
 		response.ParseError(err)
@@ -113,6 +113,8 @@ func registerCanonicalProtectedRoutes(r chi.Router) {
 	registerCanonicalMediaRoutes(r)
 	registerCanonicalLabelRoutes(r)
 	registerCanonicalStatusRoutes(r)
+	registerCanonicalAccountRoutes(r)
+	registerCanonicalBusinessRoutes(r)
 }
 
 // VersionController exposes the current backend version in the canonical system family.
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func registerCanonicalAccountRoutes(r chi.Router) {
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/account/privacy", CanonicalAccountPrivacyGetController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Put("/account/privacy", CanonicalAccountPrivacyUpdateController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Put("/account/profile", CanonicalAccountProfileUpdateController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Put("/account/profile/picture", CanonicalAccountProfilePictureSetController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Delete("/account/profile/picture", CanonicalAccountProfilePictureRemoveController)
+}
+
+func CanonicalAccountPrivacyGetController(w http.ResponseWriter, r *http.Request) {
+	GetPrivacyController(w, r)
+}
+func CanonicalAccountPrivacyUpdateController(w http.ResponseWriter, r *http.Request) {
+	UpdatePrivacyController(w, r)
+}
+func CanonicalAccountProfileUpdateController(w http.ResponseWriter, r *http.Request) {
+	UpdateProfileController(w, r)
+}
+func CanonicalAccountProfilePictureSetController(w http.ResponseWriter, r *http.Request) {
+	SetProfilePictureController(w, r)
+}
+func CanonicalAccountProfilePictureRemoveController(w http.ResponseWriter, r *http.Request) {
+	RemoveProfilePictureController(w, r)
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func registerCanonicalBusinessRoutes(r chi.Router) {
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/business/profile", CanonicalBusinessProfileController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/business/catalog", CanonicalBusinessCatalogController)
+}
+
+func CanonicalBusinessProfileController(w http.ResponseWriter, r *http.Request) {
+	GetBusinessProfileController(w, r)
+}
+func CanonicalBusinessCatalogController(w http.ResponseWriter, r *http.Request) {
+	GetCatalogController(w, r)
+}
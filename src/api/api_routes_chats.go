@@ -7,15 +7,28 @@ import (
 )
 
 func registerCanonicalChatRoutes(r chi.Router) {
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/chats", CanonicalChatsListController)
 	r.With(withCanonicalParams(canonicalTokenParam)).Post("/chats/archive", CanonicalChatArchiveController)
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/chats/read", CanonicalChatReadController)
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/chats/unread", CanonicalChatUnreadController)
 	r.With(withCanonicalParams(canonicalTokenParam)).Post("/chats/presence", CanonicalChatPresenceController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/chats/presence/subscribe", CanonicalChatPresenceSubscribeController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/chats/presence/webhooks", CanonicalChatPresenceWebhooksGetController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Put("/chats/presence/webhooks", CanonicalChatPresenceWebhooksUpdateController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/chats/{chatid}/messages", CanonicalChatMessagesController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/chats/{chatid}/history", CanonicalChatHistoryRequestController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/chats/history/{jobid}", CanonicalChatHistoryJobController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/chats/{chatid}/export", CanonicalChatExportController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/chats/export/{jobid}", CanonicalChatExportJobController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/chats/export/{jobid}/download", CanonicalChatExportDownloadController)
 	r.With(withCanonicalParams(canonicalTokenParam), canonicalMethodOverride(http.MethodGet)).Post("/chats/labels/get", CanonicalChatLabelsGetController)
 	r.With(withCanonicalParams(canonicalTokenParam)).Post("/chats/labels", CanonicalChatLabelsUpsertController)
 	r.With(withCanonicalParams(canonicalTokenParam)).Delete("/chats/labels", CanonicalChatLabelsDeleteController)
 }
 
+func CanonicalChatsListController(w http.ResponseWriter, r *http.Request) {
+	GetChatsController(w, r)
+}
 func CanonicalChatArchiveController(w http.ResponseWriter, r *http.Request) {
 	AuthenticatedServerArchiveChatController(w, r)
 }
@@ -28,6 +41,33 @@ func CanonicalChatUnreadController(w http.ResponseWriter, r *http.Request) {
 func CanonicalChatPresenceController(w http.ResponseWriter, r *http.Request) {
 	AuthenticatedServerPresenceController(w, r)
 }
+func CanonicalChatPresenceSubscribeController(w http.ResponseWriter, r *http.Request) {
+	PresenceSubscribeController(w, r)
+}
+func CanonicalChatPresenceWebhooksGetController(w http.ResponseWriter, r *http.Request) {
+	GetPresenceWebhooksController(w, r)
+}
+func CanonicalChatPresenceWebhooksUpdateController(w http.ResponseWriter, r *http.Request) {
+	UpdatePresenceWebhooksController(w, r)
+}
+func CanonicalChatMessagesController(w http.ResponseWriter, r *http.Request) {
+	GetChatMessagesController(w, r)
+}
+func CanonicalChatHistoryRequestController(w http.ResponseWriter, r *http.Request) {
+	RequestChatHistoryController(w, r)
+}
+func CanonicalChatHistoryJobController(w http.ResponseWriter, r *http.Request) {
+	GetChatHistoryJobController(w, r)
+}
+func CanonicalChatExportController(w http.ResponseWriter, r *http.Request) {
+	ChatExportController(w, r)
+}
+func CanonicalChatExportJobController(w http.ResponseWriter, r *http.Request) {
+	GetChatExportJobController(w, r)
+}
+func CanonicalChatExportDownloadController(w http.ResponseWriter, r *http.Request) {
+	DownloadChatExportController(w, r)
+}
 func CanonicalChatLabelsGetController(w http.ResponseWriter, r *http.Request) {
 	AuthenticatedServerConversationLabelController(w, r)
 }
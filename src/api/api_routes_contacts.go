@@ -15,6 +15,7 @@ func registerCanonicalContactRoutes(r chi.Router) {
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/contacts/block", CanonicalContactBlockController)
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Delete("/contacts/block", CanonicalContactUnblockController)
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/contacts/save", CanonicalContactSaveController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/contacts/purge", CanonicalContactPurgeController)
 }
 
 func CanonicalContactsListController(w http.ResponseWriter, r *http.Request) {
@@ -41,3 +42,6 @@ func CanonicalContactUnblockController(w http.ResponseWriter, r *http.Request) {
 func CanonicalContactSaveController(w http.ResponseWriter, r *http.Request) {
 	ContactSaveController(w, r)
 }
+func CanonicalContactPurgeController(w http.ResponseWriter, r *http.Request) {
+	ContactPurgeController(w, r)
+}
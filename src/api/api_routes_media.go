@@ -12,6 +12,10 @@ func registerCanonicalMediaRoutes(r chi.Router) {
 	r.With(withCanonicalParams(canonicalTokenParam, canonicalChatIDParam, canonicalPictureIDParam)).Post("/media/pictures/get", CanonicalMediaPictureInfoController)
 	r.With(withCanonicalParams(canonicalTokenParam, canonicalChatIDParam, canonicalPictureIDParam)).Post("/media/pictures/info", CanonicalMediaPictureInfoController)
 	r.With(withCanonicalParams(canonicalTokenParam, canonicalMessageIDParam), requireOwnedServerToken()).Post("/media/download", CanonicalMediaDownloadController)
+	r.With(withCanonicalParams(canonicalTokenParam, canonicalMessageIDParam), requireOwnedServerToken()).Get("/media/preview", PreviewController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/media/linkpreview", LinkPreviewController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Put("/media/linkpreview", LinkPreviewController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Delete("/media/linkpreview", LinkPreviewController)
 }
 
 func CanonicalMediaMessageController(w http.ResponseWriter, r *http.Request) {
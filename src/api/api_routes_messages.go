@@ -14,6 +14,8 @@ func registerCanonicalMessageRoutes(r chi.Router) {
 	r.With(withCanonicalParams(canonicalTokenParam, canonicalMessageIDParam), canonicalMethodOverride(http.MethodPut)).Patch("/messages", CanonicalMessageEditController)
 	r.With(withCanonicalParams(canonicalTokenParam, canonicalMessageIDParam), requireOwnedServerToken()).Delete("/messages", CanonicalMessageDeleteController)
 	r.With(withCanonicalParams(canonicalTokenParam, canonicalMessageIDParam), requireOwnedServerToken()).Post("/messages/retry", CanonicalMessageRetryController)
+	r.With(withCanonicalParams(canonicalTokenParam, canonicalMessageIDParam), requireOwnedServerToken()).Get("/messages/receipts", CanonicalMessageReceiptsController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/messages/search", CanonicalMessageSearchController)
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/messages/react", CanonicalMessageReactController)
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Delete("/messages/react", CanonicalMessageUnreactController)
 }
@@ -37,6 +39,12 @@ func CanonicalMessageDeleteController(w http.ResponseWriter, r *http.Request) {
 func CanonicalMessageRetryController(w http.ResponseWriter, r *http.Request) {
 	RedispatchAPIHandler(w, r)
 }
+func CanonicalMessageReceiptsController(w http.ResponseWriter, r *http.Request) {
+	GetMessageReceiptsController(w, r)
+}
+func CanonicalMessageSearchController(w http.ResponseWriter, r *http.Request) {
+	SearchMessagesController(w, r)
+}
 func CanonicalMessageReactController(w http.ResponseWriter, r *http.Request) {
 	SendReactionController(w, r)
 }
@@ -8,8 +8,23 @@ import (
 
 func registerCanonicalStatusRoutes(r chi.Router) {
 	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Post("/status/publish", CanonicalStatusPublishController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/status/viewers", CanonicalStatusViewersController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Get("/status/feed", CanonicalStatusFeedGetController)
+	r.With(withCanonicalParams(canonicalTokenParam), requireOwnedServerToken()).Put("/status/feed", CanonicalStatusFeedUpdateController)
 }
 
 func CanonicalStatusPublishController(w http.ResponseWriter, r *http.Request) {
 	PublishStatusController(w, r)
 }
+
+func CanonicalStatusViewersController(w http.ResponseWriter, r *http.Request) {
+	GetStatusViewersController(w, r)
+}
+
+func CanonicalStatusFeedGetController(w http.ResponseWriter, r *http.Request) {
+	GetStatusFeedController(w, r)
+}
+
+func CanonicalStatusFeedUpdateController(w http.ResponseWriter, r *http.Request) {
+	UpdateStatusFeedController(w, r)
+}
@@ -16,6 +16,10 @@ func buildSessionConfigurationPatch(request interface{}) *runtime.SessionConfigu
 			patch.ReadUpdate = req.ReadUpdate
 			patch.Direct = req.Direct
 			patch.Devel = req.Devel
+			patch.Region = req.Region
+			patch.TranscodeVideo = req.TranscodeVideo
+			patch.Transcribe = req.Transcribe
+			patch.TranscribeLanguage = req.TranscribeLanguage
 		}
 	case *InfoPatchRequest:
 		if req != nil {
@@ -27,6 +31,10 @@ func buildSessionConfigurationPatch(request interface{}) *runtime.SessionConfigu
 			patch.ReadUpdate = req.ReadUpdate
 			patch.Direct = req.Direct
 			patch.Devel = req.Devel
+			patch.Region = req.Region
+			patch.TranscodeVideo = req.TranscodeVideo
+			patch.Transcribe = req.Transcribe
+			patch.TranscribeLanguage = req.TranscribeLanguage
 		}
 	}
 
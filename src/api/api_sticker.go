@@ -1,13 +1,15 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 
 	apiModels "github.com/nocodeleaks/quepasa/api/models"
+	library "github.com/nocodeleaks/quepasa/library"
 	media "github.com/nocodeleaks/quepasa/media"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	log "github.com/nocodeleaks/quepasa/qplog"
@@ -52,15 +54,24 @@ func ResolveStickerAttachment(sticker *apiModels.WhatsappSticker) (*whatsapp.Wha
 	}
 
 	// If already WebP, skip conversion
-	mimeOnly := strings.Split(inputMime, ";")[0]
+	mimeOnly := strings.ToLower(strings.TrimSpace(strings.Split(inputMime, ";")[0]))
 	var webpData []byte
 	var outputMime string
 
-	if mimeOnly == "image/webp" || mimeOnly == "video/webp" {
+	switch mimeOnly {
+	case "image/webp", "video/webp":
 		log.Infof("[ResolveStickerAttachment] content is already WebP (%s), skipping conversion", inputMime)
 		webpData = rawData
 		outputMime = mimeOnly
-	} else {
+		if media.IsAnimatedWebP(rawData) {
+			outputMime = "video/webp"
+		}
+
+	case "application/json", "application/x-tgsticker", "application/x-lottie":
+		// rendering lottie animations requires a vector renderer not available here
+		return nil, fmt.Errorf("lottie stickers are not supported, send a gif, mp4 or webm animation instead")
+
+	default:
 		// Convert to WebP via FFmpeg
 		converted, mime, err := media.ConvertToWebP(rawData, inputMime)
 		if err != nil {
@@ -70,10 +81,19 @@ func ResolveStickerAttachment(sticker *apiModels.WhatsappSticker) (*whatsapp.Wha
 		outputMime = mime
 	}
 
+	if sticker.HasMetadata() {
+		tagged, err := media.SetWebPStickerMetadata(webpData, GetStickerMetadata(sticker))
+		if err != nil {
+			return nil, fmt.Errorf("error embedding sticker metadata: %w", err)
+		}
+		webpData = tagged
+	}
+
 	attach := &whatsapp.WhatsappAttachment{
 		Mimetype:   outputMime,
 		FileLength: uint64(len(webpData)),
 		FileName:   "sticker.webp",
+		Animated:   outputMime == "video/webp",
 	}
 	attach.SetContent(&webpData)
 
@@ -81,29 +101,33 @@ func ResolveStickerAttachment(sticker *apiModels.WhatsappSticker) (*whatsapp.Wha
 	return attach, nil
 }
 
+// GetStickerMetadata returns the EXIF sticker pack metadata of the request,
+// the pack id is derived from name and publisher so that stickers of the same pack are grouped.
+func GetStickerMetadata(sticker *apiModels.WhatsappSticker) media.StickerMetadata {
+	sum := sha256.Sum256([]byte(sticker.Pack + "\n" + sticker.Publisher))
+	return media.StickerMetadata{
+		PackId:    "quepasa." + hex.EncodeToString(sum[:8]),
+		PackName:  sticker.Pack,
+		Publisher: sticker.Publisher,
+		Emojis:    sticker.Emojis,
+	}
+}
+
 // downloadStickerFromURL downloads content from a URL and returns raw bytes and MIME type.
 func downloadStickerFromURL(url string) ([]byte, string, error) {
-	resp, err := http.Get(url)
+	result, err := library.SafeFetch(context.Background(), url, library.FetchOptions{
+		ContentTypes: []string{"image/", "video/"},
+	})
 	if err != nil {
 		return nil, "", fmt.Errorf("error fetching URL %s: %w", url, err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("unexpected status code %d fetching sticker URL", resp.StatusCode)
-	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, "", fmt.Errorf("error reading sticker response body: %w", err)
-	}
 
-	mime := resp.Header.Get("Content-Type")
+	mime := result.ContentType
 	if len(mime) == 0 {
 		mime = "application/octet-stream"
 	}
 
-	return data, mime, nil
+	return result.Content, mime, nil
 }
 
 // decodeStickerContent decodes a plain base64 string or a data URI into raw bytes and MIME type.
@@ -13,4 +13,8 @@ type InfoCreateRequest struct {
 	ReadUpdate   *whatsapp.WhatsappBoolean `json:"readupdate,omitempty"`   // should send markread requests when receiving messages
 	Direct       *whatsapp.WhatsappBoolean `json:"direct,omitempty"`       // should handle direct (individual) messages; default true
 	Devel        *bool                     `json:"devel,omitempty"`        // enable debug mode (devel)
+	Region       *string                   `json:"region,omitempty"`       // default phone region (ISO code, e.g. BR) for numbers without country code
+	TranscodeVideo *bool                   `json:"transcodevideo,omitempty"` // transcode incompatible videos to H.264/AAC MP4, default by environment
+	Transcribe     *bool                   `json:"transcribe,omitempty"`     // transcribe inbound audios, default by environment
+	TranscribeLanguage *string             `json:"transcribelanguage,omitempty"` // language hint of transcriptions (ISO 639-1, e.g. pt)
 }
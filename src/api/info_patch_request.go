@@ -13,4 +13,8 @@ type InfoPatchRequest struct {
 	Direct       *whatsapp.WhatsappBoolean `db:"direct" json:"direct,omitempty"`
 	Username     *string                   `json:"username,omitempty" validate:"max=255"`
 	Devel        *bool                     `json:"devel,omitempty"`
+	Region       *string                   `json:"region,omitempty"`
+	TranscodeVideo *bool                   `json:"transcodevideo,omitempty"`
+	Transcribe     *bool                   `json:"transcribe,omitempty"`
+	TranscribeLanguage *string             `json:"transcribelanguage,omitempty"`
 }
\ No newline at end of file
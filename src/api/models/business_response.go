@@ -0,0 +1,18 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// BusinessProfileResponse is the API transport shape for business profile endpoints.
+type BusinessProfileResponse struct {
+	models.QpResponse
+	Profile *whatsapp.WhatsappBusinessProfile `json:"profile,omitempty"`
+}
+
+// CatalogResponse is the API transport shape for business catalog endpoints.
+type CatalogResponse struct {
+	models.QpResponse
+	Catalog *whatsapp.WhatsappCatalog `json:"catalog,omitempty"`
+}
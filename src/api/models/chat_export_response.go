@@ -0,0 +1,14 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// ChatExportResponse is the API transport shape for a background chat export.
+type ChatExportResponse struct {
+	models.QpResponse
+	Job *models.QpChatExportJob `json:"job,omitempty"`
+
+	// download link, available when the job is completed
+	Url string `json:"url,omitempty"`
+}
@@ -0,0 +1,11 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// ChatHistoryResponse is the API transport shape for an on demand history job of a chat.
+type ChatHistoryResponse struct {
+	models.QpResponse
+	Job *models.QpHistoryJob `json:"job,omitempty"`
+}
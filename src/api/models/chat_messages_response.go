@@ -0,0 +1,16 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// ChatMessagesResponse is the API transport shape for a page of stored messages from a chat.
+type ChatMessagesResponse struct {
+	models.QpResponse
+	ChatId   string                    `json:"chatid,omitempty"`
+	Total    int                       `json:"total"`
+	Messages []*models.QpStoredMessage `json:"messages"`
+
+	// cursor for the next page, empty when there is no more
+	Next string `json:"next,omitempty"`
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// ChatsResponse is the API transport shape for a page of the chat list.
+type ChatsResponse struct {
+	models.QpResponse
+	Total  int                    `json:"total"`
+	Offset int                    `json:"offset"`
+	Limit  int                    `json:"limit"`
+	Chats  []*models.QpStoredChat `json:"chats"`
+}
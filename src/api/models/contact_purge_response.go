@@ -0,0 +1,11 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// ContactPurgeResponse is the API transport shape for the report of a contact data purge.
+type ContactPurgeResponse struct {
+	models.QpResponse
+	Report *models.QpContactPurgeReport `json:"report,omitempty"`
+}
@@ -0,0 +1,12 @@
+package api
+
+import (
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// LinkPreviewResponse is the API transport shape for link preview endpoints.
+type LinkPreviewResponse struct {
+	models.QpResponse
+	Preview *library.LinkPreview `json:"preview,omitempty"`
+}
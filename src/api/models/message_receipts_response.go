@@ -0,0 +1,22 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// MessageReceiptsResponse is the API transport shape for the per participant receipts of a group message.
+type MessageReceiptsResponse struct {
+	models.QpResponse
+	Id     string                         `json:"id,omitempty"`
+	ChatId string                         `json:"chatid,omitempty"`
+	Status whatsapp.WhatsappMessageStatus `json:"status,omitempty"`
+
+	// expected recipients (group members except ourselves), 0 if unknown
+	Recipients int `json:"recipients"`
+	Delivered  int `json:"delivered"`
+	Read       int `json:"read"`
+	Played     int `json:"played"`
+
+	Participants map[string]*whatsapp.WhatsappParticipantReceipt `json:"participants,omitempty"`
+}
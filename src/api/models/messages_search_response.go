@@ -0,0 +1,13 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// MessagesSearchResponse is the API transport shape for a ranked full text search over stored messages.
+type MessagesSearchResponse struct {
+	models.QpResponse
+	Query string                       `json:"query,omitempty"`
+	Total int                          `json:"total"`
+	Hits  []*models.QpMessageSearchHit `json:"hits"`
+}
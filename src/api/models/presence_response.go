@@ -0,0 +1,20 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// PresenceSubscribeResponse is the API transport shape for presence subscriptions.
+type PresenceSubscribeResponse struct {
+	models.QpResponse
+	ChatId string `json:"chatid,omitempty"`
+
+	// without a privacy token WhatsApp ignores the subscription, the contact must message us first
+	PrivacyToken bool `json:"privacytoken"`
+}
+
+// PresenceWebhooksResponse is the API transport shape for the presences dispatching option.
+type PresenceWebhooksResponse struct {
+	models.QpResponse
+	Enabled bool `json:"enabled"`
+}
@@ -0,0 +1,12 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// PrivacyResponse is the API transport shape for account privacy endpoints.
+type PrivacyResponse struct {
+	models.QpResponse
+	Settings *whatsapp.WhatsappPrivacySettings `json:"settings,omitempty"`
+}
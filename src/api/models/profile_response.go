@@ -0,0 +1,13 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// ProfileResponse is the API transport shape for own profile endpoints.
+type ProfileResponse struct {
+	models.QpResponse
+	PushName  string `json:"pushname,omitempty"`
+	About     string `json:"about,omitempty"`
+	PictureId string `json:"pictureid,omitempty"`
+}
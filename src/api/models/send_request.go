@@ -65,6 +65,9 @@ type SendRequest struct {
 	Contact  *whatsapp.WhatsappContact  `json:"contact,omitempty"`  // Contact payload when present.
 	Sticker  *WhatsappSticker           `json:"sticker,omitempty"`  // Sticker payload when present.
 
+	Product *whatsapp.WhatsappProduct      `json:"product,omitempty"` // Catalog product payload when present, attachment becomes the product image.
+	Catalog *whatsapp.WhatsappCatalogShare `json:"catalog,omitempty"` // Catalog link payload when present.
+
 	// LinkPreview is populated internally after Open Graph metadata is fetched.
 	// Not exposed in JSON — set programmatically by the handler.
 	LinkPreview *whatsapp.WhatsappMessageUrl `json:"-"`
@@ -78,6 +81,20 @@ type WhatsappSticker struct {
 
 	// Base64-encoded content or data URI (e.g. data:image/png;base64,...).
 	Content string `json:"content,omitempty"`
+
+	// Sticker pack name shown by WhatsApp apps, embedded as EXIF metadata.
+	Pack string `json:"pack,omitempty"`
+
+	// Sticker pack publisher shown by WhatsApp apps, embedded as EXIF metadata.
+	Publisher string `json:"publisher,omitempty"`
+
+	// Emojis related to the sticker, embedded as EXIF metadata.
+	Emojis []string `json:"emojis,omitempty"`
+}
+
+// HasMetadata reports whether any sticker pack metadata was informed.
+func (source *WhatsappSticker) HasMetadata() bool {
+	return len(source.Pack) > 0 || len(source.Publisher) > 0 || len(source.Emojis) > 0
 }
 
 // SendAnyRequest extends SendRequest with the additional HTTP fields accepted
@@ -91,7 +108,8 @@ type SendAnyRequest struct {
 	// Base64-encoded or data-URI encoded payload.
 	Content string `json:"content,omitempty"`
 
-	// Preview controls whether a link preview should be generated for URLs in Text.
+	// Preview controls whether a link preview should be generated for URLs in Text,
+	// using the preview curated by the session (/media/linkpreview) when there is one.
 	Preview bool `json:"preview,omitempty"`
 
 	// PreviewTitle overrides the fetched Open Graph title.
@@ -125,14 +143,15 @@ func (source *SendRequest) EnsureChatId(r *http.Request) (err error) {
 	return
 }
 
-// EnsureValidChatId resolves and normalizes the final destination JID.
-func (source *SendRequest) EnsureValidChatId(r *http.Request) (err error) {
+// EnsureValidChatId resolves and normalizes the final destination JID,
+// phones on local format are read on the given default region, if any.
+func (source *SendRequest) EnsureValidChatId(r *http.Request, region string) (err error) {
 	err = source.EnsureChatId(r)
 	if err != nil {
 		return
 	}
 
-	chatid, err := whatsapp.FormatEndpoint(source.ChatId)
+	chatid, err := whatsapp.FormatEndpointWithRegion(source.ChatId, region)
 	if err != nil {
 		return
 	}
@@ -190,6 +209,18 @@ func (source *SendRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, e
 		return
 	}
 
+	if source.Product != nil {
+		msg.Type = whatsapp.ProductMessageType
+		msg.Product = source.Product
+		return
+	}
+
+	if source.Catalog != nil {
+		msg.Type = whatsapp.TextMessageType
+		msg.Catalog = source.Catalog
+		return
+	}
+
 	if len(msg.Text) > 0 {
 		msg.Type = whatsapp.TextMessageType
 	} else {
@@ -206,7 +237,7 @@ func (source *SendRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, e
 
 // ToWhatsappAttachment builds and hardens the optional outbound attachment from
 // the resolved binary content.
-func (source *SendRequest) ToWhatsappAttachment() (result media.QpToWhatsappAttachment) {
+func (source *SendRequest) ToWhatsappAttachment(options media.AttachmentOptions) (result media.QpToWhatsappAttachment) {
 	contentLength := len(source.Content)
 	if contentLength == 0 {
 		return
@@ -237,8 +268,10 @@ func (source *SendRequest) ToWhatsappAttachment() (result media.QpToWhatsappAtta
 
 	attach.SetContent(&source.Content)
 	result.Attach = attach
+	result.Options = options
 	result.AttachSecureAndCustomize()
 	result.AttachImageTreatment()
+	result.AttachVideoTreatment()
 	result.AttachAudioTreatment()
 	return
 }
@@ -304,32 +337,20 @@ func (source *SendAnyRequest) GenerateEmbedContent() (err error) {
 
 // GenerateUrlContent downloads attachment content from a public URL.
 func (source *SendAnyRequest) GenerateUrlContent() (err error) {
-	resp, err := http.Get(source.Url)
+	result, err := library.SafeFetch(context.Background(), source.Url, library.FetchOptions{})
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("error on generate url content, unexpected status code: %v", resp.StatusCode)
+		err = fmt.Errorf("error on generate url content: %w", err)
 		logentry := source.GetLogger()
 		logentry.Error(err)
 		return
 	}
 
-	content, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-
+	content := result.Content
 	source.SendRequest.Content = content
-
-	if resp.ContentLength > -1 {
-		source.FileLength = uint64(resp.ContentLength)
-	}
+	source.FileLength = uint64(len(content))
 
 	if len(source.Mimetype) == 0 {
-		source.Mimetype = resp.Header.Get("Content-Type")
+		source.Mimetype = result.Header.Get("Content-Type")
 	}
 
 	if len(source.FileName) == 0 {
@@ -1,6 +1,9 @@
 package api
 
-import models "github.com/nocodeleaks/quepasa/models"
+import (
+	media "github.com/nocodeleaks/quepasa/media"
+	models "github.com/nocodeleaks/quepasa/models"
+)
 
 // SendResponseMessage is the nested payload returned after a successful send.
 type SendResponseMessage struct {
@@ -14,6 +17,9 @@ type SendResponseMessage struct {
 type SendResponse struct {
 	models.QpResponse
 	Message *SendResponseMessage `json:"message,omitempty"`
+
+	// Image lists what the outbound image pipeline changed on the attachment, when any.
+	Image *media.ImageReport `json:"image,omitempty"`
 }
 
 // ParseSuccess fills the send response with the standard QuePasa success message.
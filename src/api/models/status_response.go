@@ -0,0 +1,20 @@
+package api
+
+import (
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// StatusViewersResponse is the API transport shape for the viewers of one of our own status posts.
+type StatusViewersResponse struct {
+	models.QpResponse
+	Id      string                          `json:"id,omitempty"`
+	Total   int                             `json:"total"`
+	Viewers []whatsapp.WhatsappStatusViewer `json:"viewers,omitempty"`
+}
+
+// StatusFeedResponse is the API transport shape for the statuses dispatching option.
+type StatusFeedResponse struct {
+	models.QpResponse
+	Enabled bool `json:"enabled"`
+}
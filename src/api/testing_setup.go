@@ -137,6 +137,12 @@ func SetupTestService(t *testing.T) {
 
 		// Initialize Conversation Labels interface
 		models.WhatsappService.DB.ConversationLabels = models.NewQpDataConversationLabelSql(testDB)
+
+		// Initialize Messages interface
+		models.WhatsappService.DB.Messages = models.NewQpDataMessagesSql(testDB)
+
+		// Initialize Chats interface
+		models.WhatsappService.DB.Chats = models.NewQpDataChatsSql(testDB)
 	}
 }
 
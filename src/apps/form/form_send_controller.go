@@ -56,7 +56,7 @@ func controllerHttpPost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	logentry := server.GetLogger()
-	attachment, err := GetAttachFromUploadedFile(r, logentry)
+	attachment, err := GetAttachFromUploadedFile(r, logentry, server.GetAttachmentOptions())
 	if err != nil {
 		data.ErrorMessage = err.Error()
 		renderSendForm(w, data)
@@ -64,9 +64,15 @@ func controllerHttpPost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	r.ParseForm()
-	recipient := r.Form.Get("recipient")
 	message := r.Form.Get("message")
 
+	// local format phones are read on the session region
+	recipient, err := server.FormatEndpoint(r.Form.Get("recipient"))
+	if err != nil {
+		api.RespondServerError(server, w, err)
+		return
+	}
+
 	msg, err := models.ToWhatsappMessage(recipient, message, attachment)
 	if err != nil {
 		api.RespondServerError(server, w, err)
@@ -88,7 +94,7 @@ func controllerHttpPost(w http.ResponseWriter, r *http.Request) {
 	renderSendForm(w, data)
 }
 
-func GetAttachFromUploadedFile(r *http.Request, logentry log.Logger) (attach *whatsapp.WhatsappAttachment, err error) {
+func GetAttachFromUploadedFile(r *http.Request, logentry log.Logger, options media.AttachmentOptions) (attach *whatsapp.WhatsappAttachment, err error) {
 	logentry.Trace("form post, checking for file")
 
 	// Parse our multipart form, 10 << 20 specifies a maximum
@@ -132,9 +138,10 @@ func GetAttachFromUploadedFile(r *http.Request, logentry log.Logger) (attach *wh
 
 	attach.FileName = reader.Filename
 
-	result := &media.QpToWhatsappAttachment{Attach: attach}
+	result := &media.QpToWhatsappAttachment{Attach: attach, Options: options}
 	result.AttachSecureAndCustomize()
 	result.AttachImageTreatment()
+	result.AttachVideoTreatment()
 	result.AttachAudioTreatment()
 	for _, debug := range result.Debug {
 		logentry.Debug(debug)
@@ -275,7 +275,7 @@ func (hub *Hub) handleChatArchive(client *Client, command ClientCommand) (interf
 		return nil, err
 	}
 
-	chatID, err := normalizeChatID(data.ChatID, data.ChatId)
+	chatID, err := normalizeChatID(server.GetRegion(), data.ChatID, data.ChatId)
 	if err != nil {
 		return nil, err
 	}
@@ -322,7 +322,7 @@ func (hub *Hub) handleChatPresence(client *Client, command ClientCommand) (inter
 		return nil, err
 	}
 
-	chatID, err := normalizeChatID(data.ChatID, data.ChatId)
+	chatID, err := normalizeChatID(server.GetRegion(), data.ChatID, data.ChatId)
 	if err != nil {
 		return nil, err
 	}
@@ -460,6 +460,7 @@ func buildServerResult(server *models.QpWhatsappServer) ServerStatePayload {
 
 func sendMessageThroughServer(server *models.QpWhatsappServer, commandID string, data *sendCommandData) (interface{}, error) {
 	request := newSendMessageRequest(data, commandID)
+	request.Region = server.GetRegion()
 
 	if err := request.EnsureValidChatID(); err != nil {
 		return nil, err
@@ -469,7 +470,7 @@ func sendMessageThroughServer(server *models.QpWhatsappServer, commandID string,
 		return nil, err
 	}
 
-	attachment := request.ToWhatsAppAttachment().Attach
+	attachment := request.ToWhatsAppAttachment(server.GetAttachmentOptions()).Attach
 	message, err := request.ToWhatsAppMessage()
 	if err != nil {
 		return nil, err
@@ -534,11 +535,12 @@ func normalizeMessageID(values ...string) string {
 	return firstNonEmpty(values...)
 }
 
-func normalizeChatID(values ...string) (string, error) {
+// normalizeChatID formats the first informed chat id, local format phones are read on the given region
+func normalizeChatID(region string, values ...string) (string, error) {
 	chatID := firstNonEmpty(values...)
 	if chatID == "" {
 		return "", fmt.Errorf("chatId is required")
 	}
 
-	return whatsapp.FormatEndpoint(chatID)
+	return whatsapp.FormatEndpointWithRegion(chatID, region)
 }
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"path"
 	"strings"
@@ -40,6 +38,9 @@ type sendMessageRequest struct {
 	EmbedContent  string
 	TypingDelayMS int
 	MediaType     string
+
+	// default region of the session, local format phones are read on it
+	Region string
 }
 
 // newSendMessageRequest normalizes the websocket command payload into the
@@ -76,7 +77,7 @@ func (request *sendMessageRequest) EnsureValidChatID() error {
 		return fmt.Errorf("chat id missing")
 	}
 
-	chatID, err := whatsapp.FormatEndpoint(request.ChatID)
+	chatID, err := whatsapp.FormatEndpointWithRegion(request.ChatID, request.Region)
 	if err != nil {
 		return err
 	}
@@ -102,7 +103,7 @@ func (request *sendMessageRequest) BuildContent() error {
 // ToWhatsAppMessage projects the cable request into the internal message model
 // accepted by the WhatsApp service.
 func (request *sendMessageRequest) ToWhatsAppMessage() (*whatsapp.WhatsappMessage, error) {
-	chatID, err := whatsapp.FormatEndpoint(request.ChatID)
+	chatID, err := whatsapp.FormatEndpointWithRegion(request.ChatID, request.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +153,7 @@ func (request *sendMessageRequest) ToWhatsAppMessage() (*whatsapp.WhatsappMessag
 
 // ToWhatsAppAttachment builds the optional attachment payload and reuses the
 // shared attachment hardening helpers already used by the HTTP transport.
-func (request *sendMessageRequest) ToWhatsAppAttachment() media.QpToWhatsappAttachment {
+func (request *sendMessageRequest) ToWhatsAppAttachment(options media.AttachmentOptions) media.QpToWhatsappAttachment {
 	var result media.QpToWhatsappAttachment
 	if len(request.Content) == 0 {
 		return result
@@ -182,8 +183,10 @@ func (request *sendMessageRequest) ToWhatsAppAttachment() media.QpToWhatsappAtta
 
 	attachment.SetContent(&request.Content)
 	result.Attach = attachment
+	result.Options = options
 	result.AttachSecureAndCustomize()
 	result.AttachImageTreatment()
+	result.AttachVideoTreatment()
 	result.AttachAudioTreatment()
 	return result
 }
@@ -224,30 +227,18 @@ func (request *sendMessageRequest) GenerateEmbedContent() error {
 // GenerateURLContent downloads attachment bytes from the informed URL and
 // enriches missing metadata such as content type and filename.
 func (request *sendMessageRequest) GenerateURLContent() error {
-	resp, err := http.Get(request.URL)
+	result, err := library.SafeFetch(context.Background(), request.URL, library.FetchOptions{})
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("error on generate url content, unexpected status code: %v", resp.StatusCode)
+		err = fmt.Errorf("error on generate url content: %w", err)
 		request.logger().Error(err)
 		return err
 	}
 
-	content, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	request.Content = content
-	if resp.ContentLength > -1 {
-		request.FileLength = uint64(resp.ContentLength)
-	}
+	request.Content = result.Content
+	request.FileLength = uint64(len(result.Content))
 
 	if request.MimeType == "" {
-		request.MimeType = resp.Header.Get("Content-Type")
+		request.MimeType = result.Header.Get("Content-Type")
 	}
 
 	if request.FileName == "" {
@@ -4,5 +4,14 @@ type BytesQueueBackend interface {
 	Enqueue(payload []byte) (bool, error)
 	Dequeue() ([]byte, bool, error)
 	Len() (int, error)
+
+	// Remove atomically drops the queued payloads matching the filter, keeping the order of the others
+	Remove(match func(payload []byte) bool) (int, error)
 	Close() error
 }
+
+// PurgeQueue removes the queued payloads matching the filter, keeping the order of the others.
+// Removal is atomic on the backend, so concurrent consumers never see a reordered queue.
+func PurgeQueue(backend BytesQueueBackend, match func(payload []byte) bool) (int, error) {
+	return backend.Remove(match)
+}
@@ -101,6 +101,33 @@ func (backend *BytesQueueBackend) Len() (int, error) {
 	return len(files), nil
 }
 
+func (backend *BytesQueueBackend) Remove(match func(payload []byte) bool) (int, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	files, err := backend.listFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		payload, err := os.ReadFile(file)
+		if err != nil {
+			return removed, err
+		}
+		if !match(payload) {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
 func (backend *BytesQueueBackend) Close() error {
 	return nil
 }
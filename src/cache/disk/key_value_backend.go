@@ -0,0 +1,142 @@
+package disk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expired files are removed on every cleanup interval of sets, not only when read again
+const keyValueCleanupInterval = 1000
+
+type keyValueRecord struct {
+	Key       string    `json:"key"`
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expiresat,omitempty"`
+}
+
+// KeyValueBackend keeps one file per key, named by the key hash, as keys may be long urls
+type KeyValueBackend struct {
+	basePath string
+	mu       sync.Mutex
+	sets     uint64
+}
+
+func NewKeyValueBackend(basePath string) (*KeyValueBackend, error) {
+	if len(strings.TrimSpace(basePath)) == 0 {
+		return nil, errors.New("cache disk path is empty")
+	}
+
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &KeyValueBackend{basePath: basePath}, nil
+}
+
+func (backend *KeyValueBackend) filePath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(backend.basePath, hex.EncodeToString(sum[:])+".json")
+}
+
+func (backend *KeyValueBackend) Get(key string) ([]byte, bool, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	path := backend.filePath(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var record keyValueRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, false, err
+	}
+
+	if !record.ExpiresAt.IsZero() && time.Now().After(record.ExpiresAt) {
+		_ = os.Remove(path)
+		return nil, false, nil
+	}
+
+	return record.Value, true, nil
+}
+
+func (backend *KeyValueBackend) Set(key string, value []byte, ttl time.Duration) error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	record := keyValueRecord{Key: key, Value: value}
+	if ttl > 0 {
+		record.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(backend.filePath(key), data, 0o644); err != nil {
+		return err
+	}
+
+	backend.sets++
+	if backend.sets%keyValueCleanupInterval == 0 {
+		backend.cleanup()
+	}
+	return nil
+}
+
+func (backend *KeyValueBackend) Delete(key string) error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	err := os.Remove(backend.filePath(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// cleanup removes expired files, caller holds the lock
+func (backend *KeyValueBackend) cleanup() {
+	entries, err := os.ReadDir(backend.basePath)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		path := filepath.Join(backend.basePath, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var record keyValueRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			continue
+		}
+
+		if !record.ExpiresAt.IsZero() && now.After(record.ExpiresAt) {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+func (backend *KeyValueBackend) Close() error {
+	return nil
+}
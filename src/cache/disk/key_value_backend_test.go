@@ -0,0 +1,31 @@
+package disk
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestDiskKeyValueBackendCleanup verifies expired files are removed without being read again.
+func TestDiskKeyValueBackendCleanup(t *testing.T) {
+	backend, err := NewKeyValueBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewKeyValueBackend() error: %v", err)
+	}
+	defer backend.Close()
+
+	backend.Set("EXPIRE", []byte("soon"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	for i := 1; i < keyValueCleanupInterval; i++ {
+		backend.Set("KEEP", []byte("forever"), 0)
+	}
+
+	if _, err := os.Stat(backend.filePath("EXPIRE")); !os.IsNotExist(err) {
+		t.Errorf("expired file should be removed, got: %v", err)
+	}
+
+	if _, err := os.Stat(backend.filePath("KEEP")); err != nil {
+		t.Errorf("file without ttl should be kept, got: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package cache
+
+import "time"
+
+// KeyValueBackend stores raw values with expiration, shared by small caches
+// (e.g. uploaded media, link previews) on the configured cache backend
+type KeyValueBackend interface {
+	// Get returns the value of a key, false when missing or expired
+	Get(key string) ([]byte, bool, error)
+
+	// Set stores the value of a key, a zero ttl never expires
+	Set(key string, value []byte, ttl time.Duration) error
+
+	Delete(key string) error
+	Close() error
+}
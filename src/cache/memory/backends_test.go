@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -169,3 +170,46 @@ func TestMemoryQueueBackendClose(t *testing.T) {
 		t.Errorf("Close() should not error: %v", err)
 	}
 }
+
+// TestPurgeQueueKeepsOrder verifies that purging removes matches and keeps the order of the others.
+func TestPurgeQueueKeepsOrder(t *testing.T) {
+	backend := NewBytesQueueBackend(10)
+	defer backend.Close()
+
+	for _, payload := range []string{"a-keep", "b-drop", "c-keep", "d-drop"} {
+		backend.Enqueue([]byte(payload))
+	}
+
+	removed, err := cache.PurgeQueue(backend, func(payload []byte) bool {
+		return strings.HasSuffix(string(payload), "-drop")
+	})
+	if err != nil || removed != 2 {
+		t.Fatalf("PurgeQueue() = %d, %v, expected 2 removed", removed, err)
+	}
+
+	for _, expected := range []string{"a-keep", "c-keep"} {
+		payload, ok, _ := backend.Dequeue()
+		if !ok || string(payload) != expected {
+			t.Fatalf("expected %s, got %s", expected, payload)
+		}
+	}
+}
+
+// TestMemoryKeyValueBackendExpiration verifies values expire after their ttl.
+func TestMemoryKeyValueBackendExpiration(t *testing.T) {
+	backend := NewKeyValueBackend()
+	defer backend.Close()
+
+	backend.Set("KEEP", []byte("forever"), 0)
+	backend.Set("EXPIRE", []byte("soon"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	value, found, err := backend.Get("KEEP")
+	if err != nil || !found || string(value) != "forever" {
+		t.Errorf("Get() should find the key without ttl, got %q, %v, %v", value, found, err)
+	}
+
+	if _, found, _ := backend.Get("EXPIRE"); found {
+		t.Errorf("Key should be expired")
+	}
+}
@@ -45,6 +45,22 @@ func (backend *BytesQueueBackend) Len() (int, error) {
 	return len(backend.items), nil
 }
 
+func (backend *BytesQueueBackend) Remove(match func(payload []byte) bool) (int, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	kept := backend.items[:0]
+	for _, payload := range backend.items {
+		if !match(payload) {
+			kept = append(kept, payload)
+		}
+	}
+
+	removed := len(backend.items) - len(kept)
+	backend.items = kept
+	return removed, nil
+}
+
 func (backend *BytesQueueBackend) Close() error {
 	backend.mu.Lock()
 	defer backend.mu.Unlock()
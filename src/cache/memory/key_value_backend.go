@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// expired items are removed on every cleanup interval of sets
+const keyValueCleanupInterval = 1000
+
+type keyValueItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (item keyValueItem) expired(now time.Time) bool {
+	return !item.expiresAt.IsZero() && now.After(item.expiresAt)
+}
+
+type KeyValueBackend struct {
+	items sync.Map
+	sets  atomic.Uint64
+}
+
+func NewKeyValueBackend() *KeyValueBackend {
+	return &KeyValueBackend{}
+}
+
+func (backend *KeyValueBackend) Get(key string) ([]byte, bool, error) {
+	value, ok := backend.items.Load(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	item := value.(keyValueItem)
+	if item.expired(time.Now()) {
+		backend.items.Delete(key)
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
+func (backend *KeyValueBackend) Set(key string, value []byte, ttl time.Duration) error {
+	item := keyValueItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	backend.items.Store(key, item)
+
+	if backend.sets.Add(1)%keyValueCleanupInterval == 0 {
+		backend.cleanup()
+	}
+	return nil
+}
+
+func (backend *KeyValueBackend) Delete(key string) error {
+	backend.items.Delete(key)
+	return nil
+}
+
+func (backend *KeyValueBackend) cleanup() {
+	now := time.Now()
+	backend.items.Range(func(key, value any) bool {
+		if value.(keyValueItem).expired(now) {
+			backend.items.Delete(key)
+		}
+		return true
+	})
+}
+
+func (backend *KeyValueBackend) Close() error {
+	return nil
+}
@@ -89,6 +89,29 @@ func (backend *BytesQueueBackend) Len() (int, error) {
 	return int(length), nil
 }
 
+// Remove drops each matching payload with LREM, so entries popped meanwhile by consumers are not counted
+func (backend *BytesQueueBackend) Remove(match func(payload []byte) bool) (int, error) {
+	payloads, err := backend.client.LRange(backend.ctx, backend.key, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, payload := range payloads {
+		if !match([]byte(payload)) {
+			continue
+		}
+
+		count, err := backend.client.LRem(backend.ctx, backend.key, 1, payload).Result()
+		if err != nil {
+			return removed, err
+		}
+		removed += int(count)
+	}
+
+	return removed, nil
+}
+
 func (backend *BytesQueueBackend) Close() error {
 	return backend.client.Close()
 }
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cache "github.com/nocodeleaks/quepasa/cache"
+	redis "github.com/redis/go-redis/v9"
+)
+
+type KeyValueBackend struct {
+	client    *redis.Client
+	ctx       context.Context
+	keyPrefix string
+}
+
+// NewKeyValueBackend creates a backend whose keys are grouped by namespace, under the configured prefix
+func NewKeyValueBackend(config cache.RedisConfig, namespace string) (*KeyValueBackend, error) {
+	address := strings.TrimSpace(config.Host)
+	if len(address) == 0 {
+		return nil, fmt.Errorf("redis host is empty")
+	}
+
+	if config.Port > 0 {
+		address = fmt.Sprintf("%s:%d", address, config.Port)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         address,
+		Username:     config.Username,
+		Password:     config.Password,
+		DB:           int(config.Database),
+		PoolSize:     int(config.PoolSize),
+		MaxRetries:   int(config.MaxRetries),
+		DialTimeout:  config.DialTimeout,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	})
+
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	keyPrefix := namespace + ":"
+	if prefix := strings.TrimSpace(config.KeyPrefix); len(prefix) > 0 {
+		keyPrefix = prefix + ":" + keyPrefix
+	}
+
+	return &KeyValueBackend{client: client, ctx: ctx, keyPrefix: keyPrefix}, nil
+}
+
+func (backend *KeyValueBackend) Get(key string) ([]byte, bool, error) {
+	data, err := backend.client.Get(backend.ctx, backend.keyPrefix+key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (backend *KeyValueBackend) Set(key string, value []byte, ttl time.Duration) error {
+	return backend.client.Set(backend.ctx, backend.keyPrefix+key, value, ttl).Err()
+}
+
+func (backend *KeyValueBackend) Delete(key string) error {
+	return backend.client.Del(backend.ctx, backend.keyPrefix+key).Err()
+}
+
+func (backend *KeyValueBackend) Close() error {
+	return backend.client.Close()
+}
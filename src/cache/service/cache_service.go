@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"log"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 type CacheService struct {
 	messagesBackend cache.MessagesBackend
 	queueBackend    cache.BytesQueueBackend
+	keyValues       map[string]cache.KeyValueBackend
 	mu              sync.RWMutex
 }
 
@@ -44,7 +46,7 @@ func GetInstance() *CacheService {
 // It attempts to initialize the configured backend (redis/disk) for both messages and queue.
 // If initialization fails and CACHE_INIT_FALLBACK is true, both fallback to memory.
 func newCacheService() (*CacheService, error) {
-	service := &CacheService{}
+	service := &CacheService{keyValues: map[string]cache.KeyValueBackend{}}
 
 	// Initialize messages backend with fallback
 	messagesBackend, err := initMessagesBackend()
@@ -176,6 +178,72 @@ func initQueueBackend() (cache.BytesQueueBackend, error) {
 	}
 }
 
+// initKeyValueBackend creates a key value backend of the namespace based on environment configuration.
+// Respects CACHE_BACKEND setting, disk values are kept on a namespace sub directory.
+func initKeyValueBackend(namespace string) (cache.KeyValueBackend, error) {
+	switch environment.Settings.Cache.Backend {
+	case cache.BackendMemory:
+		return cache_memory.NewKeyValueBackend(), nil
+
+	case cache.BackendRedis:
+		config := cache.RedisConfig{
+			Host:         environment.Settings.Redis.Host,
+			Port:         environment.Settings.Redis.Port,
+			Username:     environment.Settings.Redis.Username,
+			Password:     environment.Settings.Redis.Password,
+			Database:     environment.Settings.Redis.Database,
+			KeyPrefix:    environment.Settings.Redis.KeyPrefix,
+			MaxRetries:   environment.Settings.Redis.MaxRetries,
+			PoolSize:     environment.Settings.Redis.PoolSize,
+			DialTimeout:  time.Duration(environment.Settings.Redis.DialTimeoutSeconds) * time.Second,
+			ReadTimeout:  time.Duration(environment.Settings.Redis.ReadTimeoutSeconds) * time.Second,
+			WriteTimeout: time.Duration(environment.Settings.Redis.WriteTimeoutSeconds) * time.Second,
+		}
+		backend, err := cache_redis.NewKeyValueBackend(config, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("redis %s backend: %w", namespace, err)
+		}
+		return backend, nil
+
+	case cache.BackendDisk:
+		diskPath := environment.Settings.Cache.DiskPath
+		if len(diskPath) > 0 {
+			diskPath = filepath.Join(diskPath, namespace)
+		}
+		backend, err := cache_disk.NewKeyValueBackend(diskPath)
+		if err != nil {
+			return nil, fmt.Errorf("disk %s backend: %w", namespace, err)
+		}
+		return backend, nil
+
+	default:
+		return nil, fmt.Errorf("unknown cache backend: %s", environment.Settings.Cache.Backend)
+	}
+}
+
+// GetKeyValueBackend returns the key value backend of a namespace, created on first use.
+// If initialization fails and CACHE_INIT_FALLBACK is true, falls back to memory.
+func (cs *CacheService) GetKeyValueBackend(namespace string) (cache.KeyValueBackend, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if backend, ok := cs.keyValues[namespace]; ok {
+		return backend, nil
+	}
+
+	backend, err := initKeyValueBackend(namespace)
+	if err != nil {
+		if !environment.Settings.Cache.InitFallback {
+			return nil, err
+		}
+		log.Printf("Key value backend (%s) initialization failed, falling back to memory: %v", namespace, err)
+		backend = cache_memory.NewKeyValueBackend()
+	}
+
+	cs.keyValues[namespace] = backend
+	return backend, nil
+}
+
 // GetMessagesBackend returns the configured messages backend.
 // This backend is used by QpWhatsappMessages and other message-related cache consumers.
 func (cs *CacheService) GetMessagesBackend() cache.MessagesBackend {
@@ -211,6 +279,12 @@ func (cs *CacheService) Close() error {
 		}
 	}
 
+	for namespace, backend := range cs.keyValues {
+		if err := backend.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("error closing %s backend: %w", namespace, err))
+		}
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("errors closing backends: %v", errs)
 	}
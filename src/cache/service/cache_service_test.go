@@ -56,6 +56,23 @@ func TestCacheServiceGetQueueBackend(t *testing.T) {
 	}
 }
 
+// TestCacheServiceGetKeyValueBackend verifies that namespaces share one backend instance.
+func TestCacheServiceGetKeyValueBackend(t *testing.T) {
+	once = sync.Once{}
+	instance = nil
+
+	service := GetInstance()
+	first, err := service.GetKeyValueBackend("uploads")
+	if err != nil || first == nil {
+		t.Fatalf("GetKeyValueBackend() failed: %v", err)
+	}
+
+	second, _ := service.GetKeyValueBackend("uploads")
+	if first != second {
+		t.Errorf("GetKeyValueBackend() should return the same backend for a namespace")
+	}
+}
+
 // TestCacheServiceClose verifies that Close() properly closes backends.
 func TestCacheServiceClose(t *testing.T) {
 	once = sync.Once{}
@@ -192,6 +209,40 @@ func TestDiskBackendFileCreation(t *testing.T) {
 	}
 }
 
+// TestDiskKeyValueBackend verifies disk key value backend stores long keys and expires values.
+func TestDiskKeyValueBackend(t *testing.T) {
+	tempDir := filepath.Join(t.TempDir(), "uploads")
+
+	backend, err := cache_disk.NewKeyValueBackend(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create disk key value backend: %v", err)
+	}
+	defer backend.Close()
+
+	key := "https://example.com/campaign?utm_source=whatsapp&utm_medium=message"
+	if err := backend.Set(key, []byte("value"), time.Hour); err != nil {
+		t.Errorf("Failed to set value: %v", err)
+	}
+
+	value, found, err := backend.Get(key)
+	if err != nil || !found || string(value) != "value" {
+		t.Errorf("Unexpected value: %q, found: %v, err: %v", value, found, err)
+	}
+
+	backend.Set("expired", []byte("value"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, found, _ := backend.Get("expired"); found {
+		t.Errorf("Value should be expired")
+	}
+
+	if err := backend.Delete(key); err != nil {
+		t.Errorf("Failed to delete value: %v", err)
+	}
+	if _, found, _ := backend.Get(key); found {
+		t.Errorf("Value should be deleted")
+	}
+}
+
 // TestDiskQueueBackendFileCreation verifies disk queue backend creates files.
 func TestDiskQueueBackendFileCreation(t *testing.T) {
 	tempDir := filepath.Join(t.TempDir(), "queue_test")
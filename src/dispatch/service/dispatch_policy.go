@@ -18,7 +18,7 @@ type DispatchPolicy interface {
 type DefaultDispatchPolicy struct{}
 
 func (DefaultDispatchPolicy) ShouldDispatch(target Target, message *whatsapp.WhatsappMessage, logentry log.Logger) bool {
-	if message.Id == "readreceipt" && target.IsSetReadReceipts() && !target.GetReadReceipts() {
+	if (message.Id == "readreceipt" || message.Id == "playedreceipt") && target.IsSetReadReceipts() && !target.GetReadReceipts() {
 		logentry.Debugf("ignoring read receipt message: %s", message.Text)
 		return false
 	}
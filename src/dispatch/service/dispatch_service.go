@@ -114,6 +114,7 @@ func (service *DispatchService) DispatchHandlerFlow(request *HandlerFlowRequest)
 	isEvent := request.Payload.Type == whatsapp.UnhandledMessageType ||
 		request.Payload.Type == whatsapp.SystemMessageType ||
 		request.Payload.Id == "readreceipt" ||
+		request.Payload.Id == "playedreceipt" ||
 		request.Payload.Id == "deliveryreceipt"
 
 	if isEvent {
@@ -0,0 +1,42 @@
+package environment
+
+const (
+	ENV_ANTIVIRUS_PROVIDER        = "ANTIVIRUS_PROVIDER"        // scanner of inbound and outbound attachments: clamav, empty disables
+	ENV_ANTIVIRUS_ADDRESS         = "ANTIVIRUS_ADDRESS"         // clamd address, tcp://host:port or unix:///path/clamd.sock
+	ENV_ANTIVIRUS_POLICY          = "ANTIVIRUS_POLICY"          // infected attachments: block, quarantine or report
+	ENV_ANTIVIRUS_QUARANTINE_PATH = "ANTIVIRUS_QUARANTINE_PATH" // directory of quarantined attachments
+	ENV_ANTIVIRUS_FAIL_CLOSED     = "ANTIVIRUS_FAIL_CLOSED"     // treat attachments that could not be scanned as infected
+	ENV_ANTIVIRUS_TIMEOUT         = "ANTIVIRUS_TIMEOUT"         // seconds waiting for a scan
+)
+
+// infected attachments policies
+const (
+	AntivirusPolicyBlock      = "block"
+	AntivirusPolicyQuarantine = "quarantine"
+	AntivirusPolicyReport     = "report"
+)
+
+type AntivirusSettings struct {
+	Provider       string `json:"provider"`
+	Address        string `json:"address"`
+	Policy         string `json:"policy"`
+	QuarantinePath string `json:"quarantine_path"`
+	FailClosed     bool   `json:"fail_closed"`
+	Timeout        uint32 `json:"timeout"`
+}
+
+func NewAntivirusSettings() AntivirusSettings {
+	return AntivirusSettings{
+		Provider:       getEnvOrDefaultString(ENV_ANTIVIRUS_PROVIDER, ""),
+		Address:        getEnvOrDefaultString(ENV_ANTIVIRUS_ADDRESS, "tcp://127.0.0.1:3310"),
+		Policy:         getEnvOrDefaultString(ENV_ANTIVIRUS_POLICY, AntivirusPolicyBlock),
+		QuarantinePath: getEnvOrDefaultString(ENV_ANTIVIRUS_QUARANTINE_PATH, "./quarantine"),
+		FailClosed:     getEnvOrDefaultBool(ENV_ANTIVIRUS_FAIL_CLOSED, false),
+		Timeout:        getEnvOrDefaultUint32(ENV_ANTIVIRUS_TIMEOUT, 30),
+	}
+}
+
+// Enabled reports whether an antivirus scanner is configured
+func (settings AntivirusSettings) Enabled() bool {
+	return len(settings.Provider) > 0
+}
@@ -1,21 +1,31 @@
 package environment
 
 const (
-	ENV_CACHE_BACKEND       = "CACHE_BACKEND"
-	ENV_CACHE_DISK_PATH     = "CACHE_DISK_PATH"
-	ENV_CACHE_INIT_FALLBACK = "CACHE_INIT_FALLBACK"
+	ENV_CACHE_BACKEND         = "CACHE_BACKEND"
+	ENV_CACHE_DISK_PATH       = "CACHE_DISK_PATH"
+	ENV_CACHE_INIT_FALLBACK   = "CACHE_INIT_FALLBACK"
+	ENV_CACHE_UPLOAD_TTL      = "CACHE_UPLOAD_TTL"
+	ENV_CACHE_LINKPREVIEW_TTL = "CACHE_LINKPREVIEW_TTL"
 )
 
 type CacheSettings struct {
 	Backend      string `json:"backend"`
 	DiskPath     string `json:"disk_path"`
 	InitFallback bool   `json:"init_fallback"`
+
+	// seconds to reuse a media upload of identical content, 0 disables
+	UploadTTL uint32 `json:"upload_ttl"`
+
+	// seconds to reuse fetched link previews, 0 disables; curated previews never expire
+	LinkPreviewTTL uint32 `json:"linkpreview_ttl"`
 }
 
 func NewCacheSettings() CacheSettings {
 	return CacheSettings{
-		Backend:      getEnvOrDefaultString(ENV_CACHE_BACKEND, "memory"),
-		DiskPath:     getEnvOrDefaultString(ENV_CACHE_DISK_PATH, ""),
-		InitFallback: getEnvOrDefaultBool(ENV_CACHE_INIT_FALLBACK, true),
+		Backend:        getEnvOrDefaultString(ENV_CACHE_BACKEND, "memory"),
+		DiskPath:       getEnvOrDefaultString(ENV_CACHE_DISK_PATH, ""),
+		InitFallback:   getEnvOrDefaultBool(ENV_CACHE_INIT_FALLBACK, true),
+		UploadTTL:      getEnvOrDefaultUint32(ENV_CACHE_UPLOAD_TTL, 3600),
+		LinkPreviewTTL: getEnvOrDefaultUint32(ENV_CACHE_LINKPREVIEW_TTL, 3600),
 	}
 }
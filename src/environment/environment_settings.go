@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/joho/godotenv"
+	library "github.com/nocodeleaks/quepasa/library"
 	qplog "github.com/nocodeleaks/quepasa/qplog"
 )
 
@@ -29,6 +30,13 @@ type EnvironmentSettings struct {
 	RabbitMQ  RabbitMQSettings
 	MCP       MCPSettings
 	Branding  BrandingSettings
+	Storage   StorageSettings
+
+	Transcription TranscriptionSettings
+	Image         ImageSettings
+	Preview       PreviewSettings
+	Antivirus     AntivirusSettings
+	Fetch         FetchSettings
 }
 
 // Settings is the global singleton instance for accessing all environment configurations.
@@ -82,8 +90,18 @@ func init() {
 		RabbitMQ:  NewRabbitMQSettings(),
 		MCP:       NewMCPSettings(),
 		Branding:  NewBrandingSettings(),
+		Storage:   NewStorageSettings(),
+
+		Transcription: NewTranscriptionSettings(),
+		Image:         NewImageSettings(),
+		Preview:       NewPreviewSettings(),
+		Antivirus:     NewAntivirusSettings(),
+		Fetch:         NewFetchSettings(),
 	}
 
+	// outbound fetches of user supplied urls, shared by every module through library
+	library.SetFetchPolicy(Settings.Fetch.GetFetchPolicy())
+
 	logentry.Println("Environment Manager ready - All configurations loaded!")
 }
 
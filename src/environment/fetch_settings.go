@@ -0,0 +1,52 @@
+package environment
+
+import (
+	"strings"
+	"time"
+
+	library "github.com/nocodeleaks/quepasa/library"
+)
+
+const (
+	ENV_FETCH_ALLOW_PRIVATE = "FETCH_ALLOW_PRIVATE" // allow fetching user supplied urls of private, loopback and link-local addresses
+	ENV_FETCH_ALLOWLIST     = "FETCH_ALLOWLIST"     // comma separated hosts (*.example.com) and networks (10.0.0.0/8) fetched even when private
+	ENV_FETCH_MAX_SIZE      = "FETCH_MAX_SIZE"      // maximum content fetched from user supplied urls, in bytes, 0 for no limit
+	ENV_FETCH_TIMEOUT       = "FETCH_TIMEOUT"       // seconds of a whole fetch, redirects included
+	ENV_FETCH_MAX_REDIRECTS = "FETCH_MAX_REDIRECTS" // redirects followed by a fetch
+)
+
+type FetchSettings struct {
+	AllowPrivate bool     `json:"allow_private"`
+	Allowlist    []string `json:"allowlist"`
+	MaxSize      uint64   `json:"max_size"`
+	Timeout      uint32   `json:"timeout"`
+	MaxRedirects uint32   `json:"max_redirects"`
+}
+
+func NewFetchSettings() FetchSettings {
+	var allowlist []string
+	for _, entry := range strings.Split(getEnvOrDefaultString(ENV_FETCH_ALLOWLIST, ""), ",") {
+		if entry = strings.TrimSpace(entry); len(entry) > 0 {
+			allowlist = append(allowlist, entry)
+		}
+	}
+
+	return FetchSettings{
+		AllowPrivate: getEnvOrDefaultBool(ENV_FETCH_ALLOW_PRIVATE, false),
+		Allowlist:    allowlist,
+		MaxSize:      getEnvOrDefaultUint64(ENV_FETCH_MAX_SIZE, 100*1024*1024),
+		Timeout:      getEnvOrDefaultUint32(ENV_FETCH_TIMEOUT, 30),
+		MaxRedirects: getEnvOrDefaultUint32(ENV_FETCH_MAX_REDIRECTS, 5),
+	}
+}
+
+// GetFetchPolicy returns the policy applied to every fetch of user supplied urls
+func (settings FetchSettings) GetFetchPolicy() library.FetchPolicy {
+	return library.FetchPolicy{
+		AllowPrivate: settings.AllowPrivate,
+		Allowlist:    settings.Allowlist,
+		MaxSize:      int64(settings.MaxSize),
+		Timeout:      time.Duration(settings.Timeout) * time.Second,
+		MaxRedirects: int(settings.MaxRedirects),
+	}
+}
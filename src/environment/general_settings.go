@@ -10,6 +10,7 @@ const (
 	ENV_SYNOPSISLENGTH           = "SYNOPSISLENGTH"           // synopsis length for messages
 	ENV_CACHELENGTH              = "CACHELENGTH"              // cache max items
 	ENV_CACHEDAYS                = "CACHEDAYS"                // cache max days
+	ENV_MESSAGESTORE             = "MESSAGESTORE"             // persist messages on the main database for history views
 	ENV_CONVERT_WAVE_TO_OGG      = "CONVERT_WAVE_TO_OGG"      // convert wave to OGG
 	ENV_COMPATIBLE_MIME_AS_AUDIO = "COMPATIBLE_MIME_AS_AUDIO" // treat compatible MIME as audio
 	ENV_FORCE_AUDIO_AS_PTT       = "FORCE_AUDIO_AS_PTT"       // force all audio formats to be sent as PTT voice notes
@@ -17,6 +18,9 @@ const (
 	ENV_TESTING                  = "TESTING"                  // testing mode
 	ENV_LOGLEVEL                 = "LOGLEVEL"                 // general log level
 	ENV_CONVERT_PNG_TO_JPG       = "CONVERT_PNG_TO_JPG"       // convert PNG to JPG (not implemented yet)
+	ENV_TRANSCODE_VIDEO          = "TRANSCODE_VIDEO"          // default of sessions, transcode incompatible videos to H.264/AAC MP4
+	ENV_TRANSCODE_VIDEO_MAX_SIZE = "TRANSCODE_VIDEO_MAX_SIZE" // max bytes of transcoded videos
+	ENV_FFMPEG_TIMEOUT           = "FFMPEG_TIMEOUT"           // seconds waiting for a ffmpeg, ffprobe or pdfinfo run on media and documents, 0 for no limit
 
 	// Login customization
 	ENV_LOGIN_LOGO         = "LOGIN_LOGO"         // URL for login page logo/icon
@@ -37,6 +41,7 @@ type GeneralSettings struct {
 	SynopsisLength        uint32 `json:"synopsis_length"`
 	CacheLength           uint64 `json:"cache_length"`
 	CacheDays             uint32 `json:"cache_days"`
+	MessageStore          bool   `json:"message_store"`
 	ConvertWaveToOGG      bool   `json:"convert_wave_to_ogg"`
 	CompatibleMIMEAsAudio bool   `json:"compatible_mime_as_audio"`
 	ForceAudioAsPTT       bool   `json:"force_audio_as_ptt"`
@@ -44,6 +49,9 @@ type GeneralSettings struct {
 	Testing               bool   `json:"testing"`
 	LogLevel              string `json:"log_level"`
 	ConvertPNGToJPG       bool   `json:"convert_png_to_jpg"`
+	TranscodeVideo        bool   `json:"transcode_video"`
+	TranscodeVideoMaxSize uint64 `json:"transcode_video_max_size"`
+	FFmpegTimeout         uint32 `json:"ffmpeg_timeout"`
 
 	// Login customization
 	LoginLogo        string `json:"login_logo"`
@@ -65,6 +73,7 @@ func NewGeneralSettings() GeneralSettings {
 		SynopsisLength:        getEnvOrDefaultUint32(ENV_SYNOPSISLENGTH, 150),
 		CacheLength:           getEnvOrDefaultUint64(ENV_CACHELENGTH, 0),
 		CacheDays:             getEnvOrDefaultUint32(ENV_CACHEDAYS, 0),
+		MessageStore:          getEnvOrDefaultBool(ENV_MESSAGESTORE, false),
 		ConvertWaveToOGG:      getEnvOrDefaultBool(ENV_CONVERT_WAVE_TO_OGG, true),
 		CompatibleMIMEAsAudio: getEnvOrDefaultBool(ENV_COMPATIBLE_MIME_AS_AUDIO, true),
 		ForceAudioAsPTT:       getEnvOrDefaultBool(ENV_FORCE_AUDIO_AS_PTT, true),
@@ -72,6 +81,9 @@ func NewGeneralSettings() GeneralSettings {
 		Testing:               getEnvOrDefaultBool(ENV_TESTING, false),
 		LogLevel:              getEnvOrDefaultString(ENV_LOGLEVEL, ""),
 		ConvertPNGToJPG:       getEnvOrDefaultBool(ENV_CONVERT_PNG_TO_JPG, false),
+		TranscodeVideo:        getEnvOrDefaultBool(ENV_TRANSCODE_VIDEO, false),
+		TranscodeVideoMaxSize: getEnvOrDefaultUint64(ENV_TRANSCODE_VIDEO_MAX_SIZE, 16*1024*1024),
+		FFmpegTimeout:         getEnvOrDefaultUint32(ENV_FFMPEG_TIMEOUT, 300),
 
 		// Login customization
 		LoginLogo:        getEnvOrDefaultString(ENV_LOGIN_LOGO, ""),
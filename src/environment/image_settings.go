@@ -0,0 +1,32 @@
+package environment
+
+const (
+	ENV_IMAGE_STRIP_METADATA = "IMAGE_STRIP_METADATA" // remove EXIF (GPS, camera), XMP, IPTC and comments of outbound images
+	ENV_IMAGE_AUTO_ROTATE    = "IMAGE_AUTO_ROTATE"    // rotate outbound JPEG images by their EXIF orientation
+	ENV_IMAGE_MAX_DIMENSION  = "IMAGE_MAX_DIMENSION"  // downsize outbound images with a larger side, in pixels, 0 disables
+	ENV_IMAGE_MAX_SIZE       = "IMAGE_MAX_SIZE"       // re-encode outbound images larger than bytes, 0 disables
+	ENV_IMAGE_CONVERT        = "IMAGE_CONVERT"        // convert outbound HEIC/HEIF, TIFF and BMP images to JPEG
+)
+
+type ImageSettings struct {
+	StripMetadata bool   `json:"strip_metadata"`
+	AutoRotate    bool   `json:"auto_rotate"`
+	MaxDimension  uint32 `json:"max_dimension"`
+	MaxSize       uint64 `json:"max_size"`
+	Convert       bool   `json:"convert"`
+}
+
+func NewImageSettings() ImageSettings {
+	return ImageSettings{
+		StripMetadata: getEnvOrDefaultBool(ENV_IMAGE_STRIP_METADATA, false),
+		AutoRotate:    getEnvOrDefaultBool(ENV_IMAGE_AUTO_ROTATE, false),
+		MaxDimension:  getEnvOrDefaultUint32(ENV_IMAGE_MAX_DIMENSION, 0),
+		MaxSize:       getEnvOrDefaultUint64(ENV_IMAGE_MAX_SIZE, 0),
+		Convert:       getEnvOrDefaultBool(ENV_IMAGE_CONVERT, false),
+	}
+}
+
+// Enabled reports whether any step of the outbound image pipeline is active
+func (settings ImageSettings) Enabled() bool {
+	return settings.StripMetadata || settings.AutoRotate || settings.MaxDimension > 0 || settings.MaxSize > 0 || settings.Convert
+}
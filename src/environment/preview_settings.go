@@ -0,0 +1,24 @@
+package environment
+
+const (
+	ENV_PREVIEW_OFFICE         = "PREVIEW_OFFICE"         // render office documents (docx, xlsx, pptx, odt, ...) with LibreOffice headless
+	ENV_PREVIEW_OFFICE_COMMAND = "PREVIEW_OFFICE_COMMAND" // LibreOffice executable
+	ENV_PREVIEW_TIMEOUT        = "PREVIEW_TIMEOUT"        // seconds waiting for a document conversion
+	ENV_PREVIEW_MAX_SIZE       = "PREVIEW_MAX_SIZE"       // larger office documents are not converted, in bytes, 0 for no limit
+)
+
+type PreviewSettings struct {
+	Office        bool   `json:"office"`
+	OfficeCommand string `json:"office_command"`
+	Timeout       uint32 `json:"timeout"`
+	MaxSize       uint64 `json:"max_size"`
+}
+
+func NewPreviewSettings() PreviewSettings {
+	return PreviewSettings{
+		Office:        getEnvOrDefaultBool(ENV_PREVIEW_OFFICE, false),
+		OfficeCommand: getEnvOrDefaultString(ENV_PREVIEW_OFFICE_COMMAND, "soffice"),
+		Timeout:       getEnvOrDefaultUint32(ENV_PREVIEW_TIMEOUT, 60),
+		MaxSize:       getEnvOrDefaultUint64(ENV_PREVIEW_MAX_SIZE, 20*1024*1024),
+	}
+}
@@ -0,0 +1,58 @@
+package environment
+
+const (
+	ENV_STORAGE_BACKEND         = "STORAGE_BACKEND"         // object storage for attachments: filesystem or s3, empty disables
+	ENV_STORAGE_PATH            = "STORAGE_PATH"            // filesystem backend root directory
+	ENV_STORAGE_PUBLIC_URL      = "STORAGE_PUBLIC_URL"      // base url where stored objects are publicly served, stable links
+	ENV_STORAGE_PREFIX          = "STORAGE_PREFIX"          // key prefix of stored objects
+	ENV_STORAGE_OUTBOUND        = "STORAGE_OUTBOUND"        // also store attachments of sent messages
+	ENV_STORAGE_RETENTION_DAYS  = "STORAGE_RETENTION_DAYS"  // remove stored objects after days, 0 keeps forever
+	ENV_STORAGE_PRESIGN_SECONDS = "STORAGE_PRESIGN_SECONDS" // pre-signed url validity, when no public url
+	ENV_STORAGE_S3_ENDPOINT     = "STORAGE_S3_ENDPOINT"     // s3 compatible endpoint, e.g. http://minio:9000
+	ENV_STORAGE_S3_REGION       = "STORAGE_S3_REGION"       // s3 signing region
+	ENV_STORAGE_S3_BUCKET       = "STORAGE_S3_BUCKET"       // s3 bucket name
+	ENV_STORAGE_S3_ACCESS_KEY   = "STORAGE_S3_ACCESS_KEY"   // s3 access key id
+	ENV_STORAGE_S3_SECRET_KEY   = "STORAGE_S3_SECRET_KEY"   // s3 secret access key
+	ENV_STORAGE_S3_PATH_STYLE   = "STORAGE_S3_PATH_STYLE"   // path style bucket addressing, required by minio
+	ENV_STORAGE_S3_LIFECYCLE    = "STORAGE_S3_LIFECYCLE"    // apply retention as bucket lifecycle rule, replaces existing rules
+)
+
+type StorageSettings struct {
+	Backend        string `json:"backend"`
+	Path           string `json:"path"`
+	PublicUrl      string `json:"public_url"`
+	Prefix         string `json:"prefix"`
+	Outbound       bool   `json:"outbound"`
+	RetentionDays  uint32 `json:"retention_days"`
+	PresignSeconds uint32 `json:"presign_seconds"`
+	S3Endpoint     string `json:"s3_endpoint"`
+	S3Region       string `json:"s3_region"`
+	S3Bucket       string `json:"s3_bucket"`
+	S3AccessKey    string `json:"s3_access_key"`
+	S3SecretKey    string `json:"-"`
+	S3PathStyle    bool   `json:"s3_path_style"`
+	S3Lifecycle    bool   `json:"s3_lifecycle"`
+}
+
+func NewStorageSettings() StorageSettings {
+	return StorageSettings{
+		Backend:        getEnvOrDefaultString(ENV_STORAGE_BACKEND, ""),
+		Path:           getEnvOrDefaultString(ENV_STORAGE_PATH, "./storage"),
+		PublicUrl:      getEnvOrDefaultString(ENV_STORAGE_PUBLIC_URL, ""),
+		Prefix:         getEnvOrDefaultString(ENV_STORAGE_PREFIX, "quepasa"),
+		Outbound:       getEnvOrDefaultBool(ENV_STORAGE_OUTBOUND, false),
+		RetentionDays:  getEnvOrDefaultUint32(ENV_STORAGE_RETENTION_DAYS, 0),
+		PresignSeconds: getEnvOrDefaultUint32(ENV_STORAGE_PRESIGN_SECONDS, 604800),
+		S3Endpoint:     getEnvOrDefaultString(ENV_STORAGE_S3_ENDPOINT, "https://s3.amazonaws.com"),
+		S3Region:       getEnvOrDefaultString(ENV_STORAGE_S3_REGION, "us-east-1"),
+		S3Bucket:       getEnvOrDefaultString(ENV_STORAGE_S3_BUCKET, ""),
+		S3AccessKey:    getEnvOrDefaultString(ENV_STORAGE_S3_ACCESS_KEY, ""),
+		S3SecretKey:    getEnvOrDefaultString(ENV_STORAGE_S3_SECRET_KEY, ""),
+		S3PathStyle:    getEnvOrDefaultBool(ENV_STORAGE_S3_PATH_STYLE, true),
+		S3Lifecycle:    getEnvOrDefaultBool(ENV_STORAGE_S3_LIFECYCLE, false),
+	}
+}
+
+func (settings StorageSettings) Enabled() bool {
+	return len(settings.Backend) > 0
+}
@@ -0,0 +1,44 @@
+package environment
+
+const (
+	ENV_TRANSCRIPTION_PROVIDER    = "TRANSCRIPTION_PROVIDER"    // speech to text of inbound voice notes: openai or command, empty disables
+	ENV_TRANSCRIPTION             = "TRANSCRIPTION"             // default of sessions for transcribing inbound audios
+	ENV_TRANSCRIPTION_URL         = "TRANSCRIPTION_URL"         // openai compatible api base url, /audio/transcriptions is appended
+	ENV_TRANSCRIPTION_API_KEY     = "TRANSCRIPTION_API_KEY"     // bearer token of the openai compatible api
+	ENV_TRANSCRIPTION_MODEL       = "TRANSCRIPTION_MODEL"       // model of the openai compatible api
+	ENV_TRANSCRIPTION_COMMAND     = "TRANSCRIPTION_COMMAND"     // local command, {file} and {language} are replaced, transcript read from stdout
+	ENV_TRANSCRIPTION_LANGUAGE    = "TRANSCRIPTION_LANGUAGE"    // default language hint, ISO 639-1, empty lets the provider detect
+	ENV_TRANSCRIPTION_MAX_SECONDS = "TRANSCRIPTION_MAX_SECONDS" // longer audios are not transcribed, 0 for no limit
+	ENV_TRANSCRIPTION_TIMEOUT     = "TRANSCRIPTION_TIMEOUT"     // seconds waiting for a transcript
+)
+
+type TranscriptionSettings struct {
+	Provider   string `json:"provider"`
+	Enabled    bool   `json:"enabled"`
+	Url        string `json:"url"`
+	ApiKey     string `json:"-"`
+	Model      string `json:"model"`
+	Command    string `json:"command"`
+	Language   string `json:"language"`
+	MaxSeconds uint32 `json:"max_seconds"`
+	Timeout    uint32 `json:"timeout"`
+}
+
+func NewTranscriptionSettings() TranscriptionSettings {
+	return TranscriptionSettings{
+		Provider:   getEnvOrDefaultString(ENV_TRANSCRIPTION_PROVIDER, ""),
+		Enabled:    getEnvOrDefaultBool(ENV_TRANSCRIPTION, false),
+		Url:        getEnvOrDefaultString(ENV_TRANSCRIPTION_URL, "https://api.openai.com/v1"),
+		ApiKey:     getEnvOrDefaultString(ENV_TRANSCRIPTION_API_KEY, ""),
+		Model:      getEnvOrDefaultString(ENV_TRANSCRIPTION_MODEL, "whisper-1"),
+		Command:    getEnvOrDefaultString(ENV_TRANSCRIPTION_COMMAND, ""),
+		Language:   getEnvOrDefaultString(ENV_TRANSCRIPTION_LANGUAGE, ""),
+		MaxSeconds: getEnvOrDefaultUint32(ENV_TRANSCRIPTION_MAX_SECONDS, 300),
+		Timeout:    getEnvOrDefaultUint32(ENV_TRANSCRIPTION_TIMEOUT, 120),
+	}
+}
+
+// Available reports whether a speech to text provider is configured, sessions still opt in
+func (settings TranscriptionSettings) Available() bool {
+	return len(settings.Provider) > 0
+}
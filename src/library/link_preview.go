@@ -0,0 +1,189 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/nocodeleaks/quepasa/qplog"
+)
+
+// ErrLinkPreviewCacheUnavailable is returned when curating previews before a cache backend is set
+var ErrLinkPreviewCacheUnavailable = errors.New("link preview cache unavailable")
+
+// LinkPreviewCacheBackend stores link previews, satisfied by the cache module key value backends
+type LinkPreviewCacheBackend interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// LinkPreview is the preview sent with messages holding an url, fetched from Open Graph metadata or curated
+type LinkPreview struct {
+	OpenGraphData
+
+	// image bytes, already downloaded from image url or informed when curated
+	Thumbnail []byte `json:"thumbnail,omitempty"`
+
+	// set manually by a session, used instead of fetched metadata on its sends and never expires
+	Curated bool `json:"curated"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+const (
+	linkPreviewCuratedPrefix = "curated:"
+	linkPreviewFetchedPrefix = "fetched:"
+)
+
+var (
+	linkPreviewMutex   sync.RWMutex
+	linkPreviewBackend LinkPreviewCacheBackend
+	linkPreviewTTL     time.Duration
+)
+
+// SetLinkPreviewCache sets the backend of link previews, fetched previews are kept for ttl, zero ttl does not
+// cache them; curated previews are kept until deleted
+func SetLinkPreviewCache(backend LinkPreviewCacheBackend, ttl time.Duration) {
+	linkPreviewMutex.Lock()
+	defer linkPreviewMutex.Unlock()
+
+	linkPreviewBackend = backend
+	linkPreviewTTL = ttl
+}
+
+func getLinkPreviewCache() (LinkPreviewCacheBackend, time.Duration) {
+	linkPreviewMutex.RLock()
+	defer linkPreviewMutex.RUnlock()
+	return linkPreviewBackend, linkPreviewTTL
+}
+
+// NormalizeLinkPreviewURL trims the url used as key of previews
+func NormalizeLinkPreviewURL(url string) string {
+	return strings.TrimSpace(url)
+}
+
+// getCuratedLinkPreviewKey scopes curated previews by owner, the session wid, each one curates its own
+func getCuratedLinkPreviewKey(owner string, url string) string {
+	return linkPreviewCuratedPrefix + owner + ":" + url
+}
+
+func getLinkPreviewEntry(backend LinkPreviewCacheBackend, key string) *LinkPreview {
+	data, found, err := backend.Get(key)
+	if err != nil {
+		log.Warnf("link preview cache get error: %s", err.Error())
+		return nil
+	}
+
+	if !found {
+		return nil
+	}
+
+	preview := &LinkPreview{}
+	if err := json.Unmarshal(data, preview); err != nil {
+		log.Warnf("invalid link preview cache entry: %s", err.Error())
+		return nil
+	}
+	return preview
+}
+
+// GetCuratedLinkPreview returns the preview of an url curated by the owner, nil when not curated
+func GetCuratedLinkPreview(owner string, url string) *LinkPreview {
+	backend, _ := getLinkPreviewCache()
+	if backend == nil || len(owner) == 0 {
+		return nil
+	}
+	return getLinkPreviewEntry(backend, getCuratedLinkPreviewKey(owner, NormalizeLinkPreviewURL(url)))
+}
+
+// GetLinkPreview returns the preview of an url curated by the owner, or the cached one, or fetches it with its image.
+// Refresh ignores the cached preview, fetching again; curated previews are always preferred.
+// Fetched previews are shared by every owner, they only hold public metadata.
+func GetLinkPreview(owner string, url string, refresh bool) (*LinkPreview, error) {
+	url = NormalizeLinkPreviewURL(url)
+	backend, ttl := getLinkPreviewCache()
+
+	if backend != nil {
+		if preview := GetCuratedLinkPreview(owner, url); preview != nil {
+			log.Debugf("curated link preview for: %s", url)
+			return preview, nil
+		}
+
+		if !refresh && ttl > 0 {
+			if preview := getLinkPreviewEntry(backend, linkPreviewFetchedPrefix+url); preview != nil {
+				log.Debugf("link preview cache hit for: %s", url)
+				return preview, nil
+			}
+		}
+	}
+
+	data, err := FetchOpenGraph(url)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &LinkPreview{OpenGraphData: *data, Timestamp: time.Now().UTC()}
+	if len(data.ImageURL) > 0 {
+		thumbnail, err := DownloadImage(data.ImageURL)
+		if err != nil {
+			log.Debugf("link preview without image for: %s, %s", url, err.Error())
+		} else {
+			preview.Thumbnail = thumbnail
+		}
+	}
+
+	if backend != nil && ttl > 0 {
+		if err := setLinkPreviewEntry(backend, linkPreviewFetchedPrefix+url, preview, ttl); err != nil {
+			log.Warnf("link preview cache set error: %s", err.Error())
+		}
+	}
+	return preview, nil
+}
+
+func setLinkPreviewEntry(backend LinkPreviewCacheBackend, key string, preview *LinkPreview, ttl time.Duration) error {
+	data, err := json.Marshal(preview)
+	if err != nil {
+		return err
+	}
+	return backend.Set(key, data, ttl)
+}
+
+// SetCuratedLinkPreview stores a preview used for its url on sends of the owner instead of fetched metadata, until deleted
+func SetCuratedLinkPreview(owner string, preview *LinkPreview) error {
+	backend, _ := getLinkPreviewCache()
+	if backend == nil {
+		return ErrLinkPreviewCacheUnavailable
+	}
+
+	if len(owner) == 0 {
+		return fmt.Errorf("missing link preview owner")
+	}
+
+	if preview == nil || len(NormalizeLinkPreviewURL(preview.URL)) == 0 {
+		return fmt.Errorf("missing link preview url")
+	}
+
+	preview.URL = NormalizeLinkPreviewURL(preview.URL)
+	preview.Curated = true
+	preview.Timestamp = time.Now().UTC()
+	return setLinkPreviewEntry(backend, getCuratedLinkPreviewKey(owner, preview.URL), preview, 0)
+}
+
+// DeleteLinkPreview removes the preview of an url curated by the owner and the cached one, the next use fetches it again
+func DeleteLinkPreview(owner string, url string) error {
+	backend, _ := getLinkPreviewCache()
+	if backend == nil {
+		return nil
+	}
+
+	url = NormalizeLinkPreviewURL(url)
+	if len(owner) > 0 {
+		if err := backend.Delete(getCuratedLinkPreviewKey(owner, url)); err != nil {
+			return err
+		}
+	}
+	return backend.Delete(linkPreviewFetchedPrefix + url)
+}
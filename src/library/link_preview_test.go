@@ -0,0 +1,105 @@
+package library
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mapLinkPreviewBackend is an in memory backend ignoring ttl
+type mapLinkPreviewBackend map[string][]byte
+
+func (backend mapLinkPreviewBackend) Get(key string) ([]byte, bool, error) {
+	value, found := backend[key]
+	return value, found, nil
+}
+
+func (backend mapLinkPreviewBackend) Set(key string, value []byte, ttl time.Duration) error {
+	backend[key] = value
+	return nil
+}
+
+func (backend mapLinkPreviewBackend) Delete(key string) error {
+	delete(backend, key)
+	return nil
+}
+
+func TestGetLinkPreview(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG\x0d\x0a\x1a\x0a"))
+		default:
+			fetches.Add(1)
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><meta property="og:title" content="Fetched"><meta property="og:image" content="http://` + r.Host + `/image"></head></html>`))
+		}
+	}))
+	defer server.Close()
+
+	previous := GetFetchPolicy()
+	defer SetFetchPolicy(previous)
+
+	policy := DefaultFetchPolicy
+	policy.AllowPrivate = true
+	SetFetchPolicy(policy)
+
+	backend := mapLinkPreviewBackend{}
+	SetLinkPreviewCache(backend, time.Hour)
+	defer SetLinkPreviewCache(nil, 0)
+
+	url := server.URL + "/campaign"
+	preview, err := GetLinkPreview("owner", url, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if preview.Title != "Fetched" || len(preview.Thumbnail) == 0 || preview.Curated {
+		t.Errorf("unexpected fetched preview: %+v", preview)
+	}
+
+	if _, err := GetLinkPreview("owner", url, false); err != nil || fetches.Load() != 1 {
+		t.Errorf("expected cached preview, fetches: %d, err: %v", fetches.Load(), err)
+	}
+
+	if _, err := GetLinkPreview("owner", url, true); err != nil || fetches.Load() != 2 {
+		t.Errorf("expected refreshed preview, fetches: %d, err: %v", fetches.Load(), err)
+	}
+
+	curated := &LinkPreview{OpenGraphData: OpenGraphData{URL: " " + url + " ", Title: "Curated"}}
+	if err := SetCuratedLinkPreview("owner", curated); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	preview, err = GetLinkPreview("owner", url, true)
+	if err != nil || preview.Title != "Curated" || !preview.Curated || fetches.Load() != 2 {
+		t.Errorf("expected curated preview: %+v, %v", preview, err)
+	}
+
+	if GetCuratedLinkPreview("owner", url) == nil {
+		t.Errorf("expected curated preview")
+	}
+
+	// curated previews of an owner are never used by others
+	preview, err = GetLinkPreview("other", url, false)
+	if err != nil || preview.Curated || GetCuratedLinkPreview("other", url) != nil {
+		t.Errorf("unexpected curated preview of other owner: %+v, %v", preview, err)
+	}
+
+	if err := DeleteLinkPreview("other", url); err != nil || GetCuratedLinkPreview("owner", url) == nil {
+		t.Errorf("expected curated preview kept on delete by other owner: %v", err)
+	}
+
+	if err := DeleteLinkPreview("owner", url); err != nil || len(backend) != 0 {
+		t.Errorf("expected previews deleted: %v, %d", err, len(backend))
+	}
+
+	SetLinkPreviewCache(nil, 0)
+	if err := SetCuratedLinkPreview("owner", curated); err != ErrLinkPreviewCacheUnavailable {
+		t.Errorf("expected unavailable cache, got: %v", err)
+	}
+}
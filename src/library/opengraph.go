@@ -1,13 +1,13 @@
 package library
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
-	"net/http"
 	"regexp"
 	"strings"
-	"sync"
 	"time"
 
 	log "github.com/nocodeleaks/quepasa/qplog"
@@ -22,35 +22,12 @@ type OpenGraphData struct {
 	ImageURL    string `json:"image_url"`
 }
 
-// ogCacheEntry wraps OpenGraphData with a TTL timestamp
-type ogCacheEntry struct {
-	data      *OpenGraphData
-	timestamp time.Time
-}
-
 var (
-	ogCache    sync.Map
-	ogCacheTTL = 5 * time.Minute
-	ogTimeout  = 5 * time.Second
-	urlRegex   = regexp.MustCompile(`https?://[^\s<>"]+`)
-	httpClient *http.Client
-	clientOnce sync.Once
+	ogTimeout = 5 * time.Second
+	urlRegex  = regexp.MustCompile(`https?://[^\s<>"]+`)
 )
 
-func getHTTPClient() *http.Client {
-	clientOnce.Do(func() {
-		httpClient = &http.Client{
-			Timeout: ogTimeout,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				if len(via) >= 10 {
-					return fmt.Errorf("too many redirects")
-				}
-				return nil
-			},
-		}
-	})
-	return httpClient
-}
+const ogUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
 
 // ExtractURLFromText extracts the first URL found in a text string
 func ExtractURLFromText(text string) string {
@@ -62,54 +39,27 @@ func ExtractAllURLsFromText(text string) []string {
 	return urlRegex.FindAllString(text, -1)
 }
 
-// FetchOpenGraph fetches Open Graph metadata from a URL with in-memory caching
+// FetchOpenGraph fetches Open Graph metadata from a URL, without caching; see GetLinkPreview
 func FetchOpenGraph(url string) (*OpenGraphData, error) {
-	// Check cache first
-	if cached, ok := ogCache.Load(url); ok {
-		entry := cached.(*ogCacheEntry)
-		if time.Since(entry.timestamp) < ogCacheTTL {
-			log.Debugf("opengraph cache hit for: %s", url)
-			return entry.data, nil
-		}
-		ogCache.Delete(url)
-	}
-
 	log.Debugf("fetching opengraph for: %s", url)
 
-	client := getHTTPClient()
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-
-	resp, err := client.Do(req)
+	result, err := SafeFetch(context.Background(), url, FetchOptions{
+		// Limit reading to 1 MB to prevent memory issues
+		MaxSize:      1024 * 1024,
+		Truncate:     true,
+		Timeout:      ogTimeout,
+		ContentTypes: []string{"text/html", "application/xhtml+xml"},
+		Headers: map[string]string{
+			"User-Agent":      ogUserAgent,
+			"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			"Accept-Language": "en-US,en;q=0.5",
+		},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch URL: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Limit reading to 1 MB to prevent memory issues
-	limitedReader := io.LimitReader(resp.Body, 1024*1024)
-
-	data, err := parseOpenGraph(limitedReader, url)
-	if err != nil {
-		return nil, err
-	}
 
-	ogCache.Store(url, &ogCacheEntry{
-		data:      data,
-		timestamp: time.Now(),
-	})
-
-	return data, nil
+	return parseOpenGraph(bytes.NewReader(result.Content), url)
 }
 
 // parseOpenGraph parses HTML and extracts Open Graph meta tags
@@ -207,40 +157,20 @@ func DownloadImage(url string) ([]byte, error) {
 
 	log.Debugf("downloading image from: %s", url)
 
-	client := getHTTPClient()
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create image request: %w", err)
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-
-	resp, err := client.Do(req)
+	result, err := SafeFetch(context.Background(), url, FetchOptions{
+		MaxSize:      5 * 1024 * 1024,
+		Timeout:      ogTimeout,
+		ContentTypes: []string{"image/"},
+		Headers:      map[string]string{"User-Agent": ogUserAgent},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to download image: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected image status code: %d", resp.StatusCode)
-	}
-
-	data, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read image: %w", err)
-	}
-
-	return data, nil
+	return result.Content, nil
 }
 
 // DecodeBase64 decodes a base64-encoded string to bytes
 func DecodeBase64(data string) ([]byte, error) {
 	return base64.StdEncoding.DecodeString(data)
 }
-
-// ClearOpenGraphCache evicts all cached Open Graph entries
-func ClearOpenGraphCache() {
-	ogCache.Range(func(key, value any) bool {
-		ogCache.Delete(key)
-		return true
-	})
-}
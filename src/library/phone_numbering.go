@@ -0,0 +1,237 @@
+package library
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PhoneRegion holds the numbering plan of a region, libphonenumber style, only what is needed
+// to turn local formatted numbers into E164 and validate them
+type PhoneRegion struct {
+	// ISO 3166-1 alpha-2 region code, e.g. BR, US
+	Region string `json:"region"`
+
+	// country calling code, without +
+	CallingCode string `json:"callingcode"`
+
+	// trunk prefix dialed before national numbers, e.g. 0 on most of europe, 1 on NANP
+	NationalPrefix string `json:"nationalprefix,omitempty"`
+
+	// prefix dialed before international numbers, e.g. 00, 011 on NANP
+	InternationalPrefix string `json:"internationalprefix,omitempty"`
+
+	// possible lengths of the national significant number, without trunk prefix
+	Lengths []int `json:"lengths"`
+}
+
+// IsValidLength checks if a national significant number length is possible on this region
+func (source PhoneRegion) IsValidLength(length int) bool {
+	return slices.Contains(source.Lengths, length)
+}
+
+var (
+	phoneRegionsMutex sync.RWMutex
+	phoneRegions      = map[string]*PhoneRegion{}
+	phoneCallingCodes = map[string][]*PhoneRegion{}
+)
+
+// default metadata set, most used regions, others can be added with RegisterPhoneRegion
+var defaultPhoneRegions = []PhoneRegion{
+	{Region: "US", CallingCode: "1", NationalPrefix: "1", InternationalPrefix: "011", Lengths: []int{10}},
+	{Region: "CA", CallingCode: "1", NationalPrefix: "1", InternationalPrefix: "011", Lengths: []int{10}},
+	{Region: "BR", CallingCode: "55", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{10, 11}},
+	{Region: "AR", CallingCode: "54", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{10, 11}},
+	{Region: "BO", CallingCode: "591", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8}},
+	{Region: "CL", CallingCode: "56", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "CO", CallingCode: "57", InternationalPrefix: "00", Lengths: []int{10}},
+	{Region: "EC", CallingCode: "593", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8, 9}},
+	{Region: "MX", CallingCode: "52", InternationalPrefix: "00", Lengths: []int{10, 11}},
+	{Region: "PE", CallingCode: "51", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8, 9}},
+	{Region: "PY", CallingCode: "595", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "UY", CallingCode: "598", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8}},
+	{Region: "VE", CallingCode: "58", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{10}},
+	{Region: "PT", CallingCode: "351", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "ES", CallingCode: "34", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "FR", CallingCode: "33", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "IT", CallingCode: "39", InternationalPrefix: "00", Lengths: []int{6, 7, 8, 9, 10, 11}},
+	{Region: "DE", CallingCode: "49", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{7, 8, 9, 10, 11, 12, 13}},
+	{Region: "GB", CallingCode: "44", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{9, 10}},
+	{Region: "IE", CallingCode: "353", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{7, 8, 9}},
+	{Region: "NL", CallingCode: "31", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "BE", CallingCode: "32", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8, 9}},
+	{Region: "CH", CallingCode: "41", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "AT", CallingCode: "43", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{7, 8, 9, 10, 11, 12, 13}},
+	{Region: "PL", CallingCode: "48", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "RU", CallingCode: "7", NationalPrefix: "8", InternationalPrefix: "810", Lengths: []int{10}},
+	{Region: "TR", CallingCode: "90", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{10}},
+	{Region: "IL", CallingCode: "972", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8, 9}},
+	{Region: "AE", CallingCode: "971", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8, 9}},
+	{Region: "SA", CallingCode: "966", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8, 9}},
+	{Region: "IN", CallingCode: "91", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{10}},
+	{Region: "ID", CallingCode: "62", NationalPrefix: "0", InternationalPrefix: "001", Lengths: []int{9, 10, 11, 12}},
+	{Region: "PH", CallingCode: "63", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{10}},
+	{Region: "CN", CallingCode: "86", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{10, 11}},
+	{Region: "JP", CallingCode: "81", NationalPrefix: "0", InternationalPrefix: "010", Lengths: []int{9, 10}},
+	{Region: "AU", CallingCode: "61", NationalPrefix: "0", InternationalPrefix: "0011", Lengths: []int{9}},
+	{Region: "NZ", CallingCode: "64", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{8, 9, 10}},
+	{Region: "ZA", CallingCode: "27", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "NG", CallingCode: "234", NationalPrefix: "0", InternationalPrefix: "009", Lengths: []int{8, 10}},
+	{Region: "EG", CallingCode: "20", NationalPrefix: "0", InternationalPrefix: "00", Lengths: []int{9, 10}},
+	{Region: "KE", CallingCode: "254", NationalPrefix: "0", InternationalPrefix: "000", Lengths: []int{9}},
+	{Region: "AO", CallingCode: "244", InternationalPrefix: "00", Lengths: []int{9}},
+	{Region: "MZ", CallingCode: "258", InternationalPrefix: "00", Lengths: []int{8, 9}},
+}
+
+func init() {
+	for _, region := range defaultPhoneRegions {
+		RegisterPhoneRegion(region)
+	}
+}
+
+// RegisterPhoneRegion adds or replaces the numbering plan of a region
+func RegisterPhoneRegion(region PhoneRegion) {
+	region.Region = strings.ToUpper(strings.TrimSpace(region.Region))
+	region.CallingCode = strings.TrimPrefix(strings.TrimSpace(region.CallingCode), "+")
+
+	phoneRegionsMutex.Lock()
+	defer phoneRegionsMutex.Unlock()
+
+	if previous, ok := phoneRegions[region.Region]; ok {
+		phoneCallingCodes[previous.CallingCode] = slices.DeleteFunc(phoneCallingCodes[previous.CallingCode], func(item *PhoneRegion) bool {
+			return item == previous
+		})
+	}
+
+	phoneRegions[region.Region] = &region
+	phoneCallingCodes[region.CallingCode] = append(phoneCallingCodes[region.CallingCode], &region)
+}
+
+// GetPhoneRegion returns the numbering plan of a region code, case insensitive
+func GetPhoneRegion(region string) (*PhoneRegion, bool) {
+	phoneRegionsMutex.RLock()
+	defer phoneRegionsMutex.RUnlock()
+
+	found, ok := phoneRegions[strings.ToUpper(strings.TrimSpace(region))]
+	return found, ok
+}
+
+// GetPhoneRegions returns the region codes with known numbering plans, sorted
+func GetPhoneRegions() []string {
+	phoneRegionsMutex.RLock()
+	defer phoneRegionsMutex.RUnlock()
+
+	regions := make([]string, 0, len(phoneRegions))
+	for region := range phoneRegions {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// GetPhoneRegionOrError validates a default region code, empty is allowed and means none
+func GetPhoneRegionOrError(region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if len(region) == 0 {
+		return "", nil
+	}
+
+	if _, ok := GetPhoneRegion(region); !ok {
+		return "", fmt.Errorf("unknown phone region: %s, known: %s", region, strings.Join(GetPhoneRegions(), ", "))
+	}
+	return region, nil
+}
+
+// GetPhoneCallingCode splits the digits of an international number into calling code and
+// national significant number, calling codes are prefix free, so at most one matches
+func GetPhoneCallingCode(digits string) (callingCode string, national string, regions []*PhoneRegion) {
+	digits = strings.TrimPrefix(digits, "+")
+
+	phoneRegionsMutex.RLock()
+	defer phoneRegionsMutex.RUnlock()
+
+	for size := 1; size <= 3 && size < len(digits); size++ {
+		if found, ok := phoneCallingCodes[digits[:size]]; ok && len(found) > 0 {
+			return digits[:size], digits[size:], found
+		}
+	}
+	return "", digits, nil
+}
+
+var regexPhoneSeparators = regexp.MustCompile(`[\s\-\.\(\)/]`)
+var regexPhoneGeneric = regexp.MustCompile(`^[1-9]\d{6,14}$`)
+
+// ParsePhoneNumber converts a phone number, international (+ or dialing prefix) or local to the
+// given default region, into E164 with + prefix.
+// Without region, numbers without + are taken as international, as always did.
+func ParsePhoneNumber(source string, region string) (string, error) {
+	digits := regexPhoneSeparators.ReplaceAllString(strings.TrimSpace(source), "")
+	international := strings.HasPrefix(digits, "+")
+	digits = strings.TrimPrefix(digits, "+")
+
+	if len(digits) == 0 || strings.Trim(digits, "0123456789") != "" {
+		return "", fmt.Errorf("not a valid phone number: %s", source)
+	}
+
+	var plan *PhoneRegion
+	if len(region) > 0 {
+		found, ok := GetPhoneRegion(region)
+		if !ok {
+			return "", fmt.Errorf("unknown phone region: %s", region)
+		}
+		plan = found
+	}
+
+	if !international {
+		if plan != nil && len(plan.InternationalPrefix) > 0 && strings.HasPrefix(digits, plan.InternationalPrefix) {
+			digits = strings.TrimPrefix(digits, plan.InternationalPrefix)
+			international = true
+		} else if plan == nil && strings.HasPrefix(digits, "00") {
+			digits = strings.TrimPrefix(digits, "00")
+			international = true
+		}
+	}
+
+	if international || plan == nil {
+		return validateInternationalPhone(source, digits)
+	}
+
+	national := digits
+	if len(plan.NationalPrefix) > 0 && strings.HasPrefix(national, plan.NationalPrefix) && plan.IsValidLength(len(national)-len(plan.NationalPrefix)) {
+		national = strings.TrimPrefix(national, plan.NationalPrefix)
+	}
+
+	if plan.IsValidLength(len(national)) {
+		return validateInternationalPhone(source, plan.CallingCode+national)
+	}
+
+	// already international, just missing the +
+	if strings.HasPrefix(digits, plan.CallingCode) {
+		return validateInternationalPhone(source, digits)
+	}
+
+	return "", fmt.Errorf("not a valid %s phone number: %s", plan.Region, source)
+}
+
+// IsValidPhoneNumber checks if a E164 number, with or without +, matches a known numbering plan
+func IsValidPhoneNumber(phone string) bool {
+	_, err := validateInternationalPhone(phone, strings.TrimPrefix(strings.TrimSpace(phone), "+"))
+	return err == nil
+}
+
+func validateInternationalPhone(source string, digits string) (string, error) {
+	if !regexPhoneGeneric.MatchString(digits) {
+		return "", fmt.Errorf("not a valid E164 phone number: %s", source)
+	}
+
+	// unknown calling codes only get the generic E164 check
+	_, national, regions := GetPhoneCallingCode(digits)
+	if len(regions) > 0 && !slices.ContainsFunc(regions, func(item *PhoneRegion) bool { return item.IsValidLength(len(national)) }) {
+		return "", fmt.Errorf("not a valid %s phone number: %s", regions[0].Region, source)
+	}
+
+	return "+" + digits, nil
+}
@@ -0,0 +1,88 @@
+package library
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePhoneNumber(t *testing.T) {
+	tests := []struct {
+		source   string
+		region   string
+		expected string
+	}{
+		{"+55 (21) 99999-9999", "", "+5521999999999"},
+		{"5521999999999", "", "+5521999999999"},
+		{"21 99999-9999", "BR", "+5521999999999"},
+		{"021 99999-9999", "BR", "+5521999999999"},
+		{"5521999999999", "BR", "+5521999999999"},
+		{"+1 212 555 1234", "BR", "+12125551234"},
+		{"(212) 555-1234", "US", "+12125551234"},
+		{"1 212 555 1234", "US", "+12125551234"},
+		{"011 44 20 7946 0958", "US", "+442079460958"},
+		{"020 7946 0958", "GB", "+442079460958"},
+		{"06 12 34 56 78", "fr", "+33612345678"},
+		{"55 1234 5678", "MX", "+525512345678"},
+		{"0044 20 7946 0958", "", "+442079460958"},
+	}
+
+	for _, test := range tests {
+		phone, err := ParsePhoneNumber(test.source, test.region)
+		if err != nil {
+			t.Errorf("%s (%s): unexpected error: %v", test.source, test.region, err)
+			continue
+		}
+		if phone != test.expected {
+			t.Errorf("%s (%s): expected %s, got %s", test.source, test.region, test.expected, phone)
+		}
+	}
+}
+
+func TestParsePhoneNumberInvalid(t *testing.T) {
+	tests := []struct {
+		source string
+		region string
+	}{
+		{"", ""},
+		{"abc", ""},
+		{"+55 21 999", ""},
+		{"+55 21 9999 99999 9", ""},
+		{"123", "BR"},
+		{"21999999999", "XX"},
+		{"120363000000000000", ""},
+	}
+
+	for _, test := range tests {
+		if phone, err := ParsePhoneNumber(test.source, test.region); err == nil {
+			t.Errorf("%s (%s): expected error, got %s", test.source, test.region, phone)
+		}
+	}
+}
+
+func TestGetPhoneVariants(t *testing.T) {
+	tests := []struct {
+		phone    string
+		strict   bool
+		expected []string
+	}{
+		// ninth digit only removed on DDDs > 30 when strict
+		{"+5547999999999", true, []string{"+554799999999"}},
+		{"+5521999999999", true, []string{}},
+		{"+5521999999999", false, []string{"+552199999999"}},
+		{"+552199999999", true, []string{"+5521999999999"}},
+		{"+525512345678", true, []string{"+5215512345678"}},
+		{"+5491112345678", true, []string{"+541112345678"}},
+		{"+12125551234", true, []string{}},
+	}
+
+	for _, test := range tests {
+		variants := GetPhoneVariants(test.phone, test.strict)
+		if !slices.Equal(variants, test.expected) {
+			t.Errorf("%s (strict: %v): expected %v, got %v", test.phone, test.strict, test.expected, variants)
+		}
+	}
+
+	if variants := GetPhoneVariants("+5547999999999", true, "MX"); len(variants) != 0 {
+		t.Errorf("expected no variants outside the given regions, got %v", variants)
+	}
+}
@@ -0,0 +1,136 @@
+package library
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// PhoneCountryRule handles countries where the same subscriber may be registered on whatsapp
+// under more than one E164 form, e.g. brazilian mobiles with or without the ninth digit
+type PhoneCountryRule interface {
+	// Region code the rule applies to, e.g. BR
+	Region() string
+
+	// Variants returns the other forms a E164 phone may be registered under.
+	// Strict keeps only changes safe to try as a send destination, otherwise
+	// returns every form worth keeping as a lookup key.
+	Variants(phone string, strict bool) []string
+}
+
+var (
+	phoneRulesMutex sync.RWMutex
+	phoneRules      = map[string]PhoneCountryRule{}
+)
+
+func init() {
+	RegisterPhoneCountryRule(BRNinthDigitRule{})
+	RegisterPhoneCountryRule(MXMobilePrefixRule{})
+	RegisterPhoneCountryRule(ARMobilePrefixRule{})
+}
+
+// RegisterPhoneCountryRule adds or replaces the rule of a region
+func RegisterPhoneCountryRule(rule PhoneCountryRule) {
+	phoneRulesMutex.Lock()
+	defer phoneRulesMutex.Unlock()
+	phoneRules[strings.ToUpper(rule.Region())] = rule
+}
+
+// GetPhoneCountryRule returns the rule of a region, if any
+func GetPhoneCountryRule(region string) (PhoneCountryRule, bool) {
+	phoneRulesMutex.RLock()
+	defer phoneRulesMutex.RUnlock()
+	rule, ok := phoneRules[strings.ToUpper(strings.TrimSpace(region))]
+	return rule, ok
+}
+
+// GetPhoneVariants returns the alternative forms of a E164 phone from the rules of its country,
+// limited to the given regions when informed
+func GetPhoneVariants(phone string, strict bool, regions ...string) []string {
+	_, _, plans := GetPhoneCallingCode(phone)
+
+	variants := []string{}
+	for _, plan := range plans {
+		if len(regions) > 0 && !slices.ContainsFunc(regions, func(region string) bool { return strings.EqualFold(region, plan.Region) }) {
+			continue
+		}
+
+		rule, ok := GetPhoneCountryRule(plan.Region)
+		if !ok {
+			continue
+		}
+
+		for _, variant := range rule.Variants(phone, strict) {
+			if variant != phone && !slices.Contains(variants, variant) {
+				variants = append(variants, variant)
+			}
+		}
+	}
+	return variants
+}
+
+// BRNinthDigitRule handles brazilian mobiles registered before or after the ninth digit migration.
+//
+// Strict follows the send path: removes the digit only for DDDs > 30, adds it for any DDD.
+// Otherwise both directions apply to any DDD, for lookup key augmentation only.
+//
+// TEMPORARY WORKAROUND: remove once WhatsApp enforces a single canonical phone format.
+type BRNinthDigitRule struct{}
+
+func (BRNinthDigitRule) Region() string { return "BR" }
+
+func (BRNinthDigitRule) Variants(phone string, strict bool) []string {
+	if strict {
+		if variant, err := RemoveDigit9IfElegible(phone); err == nil {
+			return []string{variant}
+		}
+	} else if variant, err := RemoveDigit9BRAllDDDs(phone); err == nil {
+		return []string{variant}
+	}
+
+	if variant, err := AddDigit9BRAllDDDs(phone); err == nil {
+		return []string{variant}
+	}
+	return nil
+}
+
+var regexMXMobile = regexp.MustCompile(`^\+52(1?)(\d{10})$`)
+
+// MXMobilePrefixRule handles mexican mobiles, accounts created before the 2019 dialing reform
+// are still registered with the old mobile prefix 1 after the country code (+521)
+type MXMobilePrefixRule struct{}
+
+func (MXMobilePrefixRule) Region() string { return "MX" }
+
+func (MXMobilePrefixRule) Variants(phone string, strict bool) []string {
+	matches := regexMXMobile.FindStringSubmatch(phone)
+	if matches == nil {
+		return nil
+	}
+
+	if len(matches[1]) > 0 {
+		return []string{"+52" + matches[2]}
+	}
+	return []string{"+521" + matches[2]}
+}
+
+var regexARPhone = regexp.MustCompile(`^\+54(9?)([1-9]\d{9})$`)
+
+// ARMobilePrefixRule handles argentinian mobiles, registered internationally with the mobile
+// token 9 after the country code (+549), often written without it
+type ARMobilePrefixRule struct{}
+
+func (ARMobilePrefixRule) Region() string { return "AR" }
+
+func (ARMobilePrefixRule) Variants(phone string, strict bool) []string {
+	matches := regexARPhone.FindStringSubmatch(phone)
+	if matches == nil {
+		return nil
+	}
+
+	if len(matches[1]) > 0 {
+		return []string{"+54" + matches[2]}
+	}
+	return []string{"+549" + matches[2]}
+}
@@ -0,0 +1,321 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/nocodeleaks/quepasa/qplog"
+)
+
+// ErrFetchBlocked is returned when an url resolves to an address not allowed by the fetch policy
+var ErrFetchBlocked = errors.New("fetch blocked")
+
+// FetchPolicy limits every fetch of user supplied urls (attachments, stickers, link previews, pictures)
+type FetchPolicy struct {
+	// disables the block of private, loopback and link-local addresses
+	AllowPrivate bool
+
+	// hosts ("intranet.local", "*.example.com") and networks ("10.1.0.0/16") fetched even when private
+	Allowlist []string
+
+	// default maximum content length, in bytes, 0 for no limit
+	MaxSize int64
+
+	// default time for the whole fetch, redirects and body included
+	Timeout time.Duration
+
+	MaxRedirects int
+}
+
+// DefaultFetchPolicy is used until another one is set by environment
+var DefaultFetchPolicy = FetchPolicy{
+	MaxSize:      100 * 1024 * 1024,
+	Timeout:      30 * time.Second,
+	MaxRedirects: 5,
+}
+
+// FetchOptions are the limits of a single fetch
+type FetchOptions struct {
+	// maximum content length, in bytes, 0 uses the policy
+	MaxSize int64
+
+	// reads up to max size instead of failing on larger contents, as for html pages
+	Truncate bool
+
+	// 0 uses the policy
+	Timeout time.Duration
+
+	// allowed content types, full ("text/html") or prefix ("image/"); empty allows any
+	ContentTypes []string
+
+	Headers map[string]string
+}
+
+// FetchResult is the content of a successful fetch
+type FetchResult struct {
+	Content []byte
+
+	// declared mime, without parameters
+	ContentType string
+
+	// final url, after redirects
+	URL string
+
+	Header http.Header
+}
+
+var (
+	fetchPolicyMutex sync.RWMutex
+	fetchPolicy      = DefaultFetchPolicy
+)
+
+// additional ranges not covered by netip helpers: "this network", carrier grade nat, ietf protocol
+// assignments, benchmarking, reserved and nat64
+var fetchBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// SetFetchPolicy replaces the policy of every following fetch
+func SetFetchPolicy(policy FetchPolicy) {
+	fetchPolicyMutex.Lock()
+	defer fetchPolicyMutex.Unlock()
+	fetchPolicy = policy
+}
+
+// GetFetchPolicy returns the current fetch policy
+func GetFetchPolicy() FetchPolicy {
+	fetchPolicyMutex.RLock()
+	defer fetchPolicyMutex.RUnlock()
+	return fetchPolicy
+}
+
+// IsPublicAddress reports whether the address is routable on the internet,
+// false for private, loopback, link-local, multicast and reserved ranges
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range fetchBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsHostAllowlisted reports whether the host matches a host entry of the allowlist
+func (policy FetchPolicy) IsHostAllowlisted(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, entry := range policy.Allowlist {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if suffix, ok := strings.CutPrefix(entry, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAddressAllowed reports whether the policy lets an address be dialed
+func (policy FetchPolicy) IsAddressAllowed(addr netip.Addr) bool {
+	if policy.AllowPrivate || IsPublicAddress(addr) {
+		return true
+	}
+
+	addr = addr.Unmap()
+	for _, entry := range policy.Allowlist {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(entry)); err == nil && prefix.Contains(addr) {
+			return true
+		}
+		if single, err := netip.ParseAddr(strings.TrimSpace(entry)); err == nil && single.Unmap() == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// dialContext resolves the host itself and dials only allowed addresses, so a name cannot be
+// rebound to an internal address between the check and the connection
+func (policy FetchPolicy) dialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	if policy.IsHostAllowlisted(host) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		resolved, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, err
+		}
+		addrs = resolved
+	}
+
+	var lastErr error = fmt.Errorf("%w: no addresses for host: %s", ErrFetchBlocked, host)
+	for _, addr := range addrs {
+		if !policy.IsAddressAllowed(addr) {
+			lastErr = fmt.Errorf("%w: %s resolves to a non public address: %s", ErrFetchBlocked, host, addr)
+			continue
+		}
+
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// NewSafeHTTPClient returns a client applying the policy to every connection, redirects included;
+// environment proxies are not used, as they would hide the destination address
+func NewSafeHTTPClient(policy FetchPolicy) *http.Client {
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           policy.dialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   policy.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > policy.MaxRedirects {
+				return fmt.Errorf("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to unsupported scheme: %s", ErrFetchBlocked, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// SafeFetch downloads an user supplied url under the fetch policy, enforcing size, time and content type limits
+func SafeFetch(ctx context.Context, url string, options FetchOptions) (*FetchResult, error) {
+	policy := GetFetchPolicy()
+	if options.Timeout > 0 {
+		policy.Timeout = options.Timeout
+	}
+
+	maxSize := policy.MaxSize
+	if options.MaxSize > 0 {
+		maxSize = options.MaxSize
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("%w: unsupported scheme: %s", ErrFetchBlocked, req.URL.Scheme)
+	}
+
+	for key, value := range options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := NewSafeHTTPClient(policy)
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if maxSize > 0 && resp.ContentLength > maxSize && !options.Truncate {
+		return nil, fmt.Errorf("content too large: %d bytes, limit: %d", resp.ContentLength, maxSize)
+	}
+
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0]))
+	if len(contentType) > 0 && contentType != "application/octet-stream" && !IsContentTypeAllowed(contentType, options.ContentTypes) {
+		return nil, fmt.Errorf("content type not allowed: %s", contentType)
+	}
+
+	reader := io.Reader(resp.Body)
+	if maxSize > 0 && options.Truncate {
+		reader = io.LimitReader(resp.Body, maxSize)
+	} else if maxSize > 0 {
+		reader = io.LimitReader(resp.Body, maxSize+1)
+	}
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	if maxSize > 0 && int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("content too large, limit: %d bytes", maxSize)
+	}
+
+	// generic or missing types are checked by the content itself
+	if len(contentType) == 0 || contentType == "application/octet-stream" {
+		sniffed := strings.Split(http.DetectContentType(content), ";")[0]
+		if !IsContentTypeAllowed(sniffed, options.ContentTypes) {
+			return nil, fmt.Errorf("content type not allowed: %s", sniffed)
+		}
+	}
+
+	log.Debugf("fetched %s, type: %s, size: %d", resp.Request.URL.Redacted(), contentType, len(content))
+	return &FetchResult{
+		Content:     content,
+		ContentType: contentType,
+		URL:         resp.Request.URL.String(),
+		Header:      resp.Header,
+	}, nil
+}
+
+// IsContentTypeAllowed reports whether a mime matches the allowlist, full types or prefixes ending with "/";
+// an empty allowlist allows any
+func IsContentTypeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, entry := range allowed {
+		if strings.HasSuffix(entry, "/") {
+			if strings.HasPrefix(contentType, entry) {
+				return true
+			}
+		} else if contentType == entry {
+			return true
+		}
+	}
+	return false
+}
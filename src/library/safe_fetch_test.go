@@ -0,0 +1,105 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestIsPublicAddress(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":         true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"::ffff:10.0.0.1": false,
+	}
+
+	for address, public := range cases {
+		if result := IsPublicAddress(netip.MustParseAddr(address)); result != public {
+			t.Errorf("IsPublicAddress(%s) = %v, expected %v", address, result, public)
+		}
+	}
+}
+
+func TestFetchPolicyAllowlist(t *testing.T) {
+	policy := FetchPolicy{Allowlist: []string{"intranet.local", "*.corp.example", "10.1.0.0/16", "192.168.0.10"}}
+
+	if !policy.IsHostAllowlisted("INTRANET.local.") || !policy.IsHostAllowlisted("files.corp.example") || policy.IsHostAllowlisted("corp.example") {
+		t.Errorf("unexpected host allowlist matching")
+	}
+
+	if !policy.IsAddressAllowed(netip.MustParseAddr("10.1.2.3")) || !policy.IsAddressAllowed(netip.MustParseAddr("192.168.0.10")) {
+		t.Errorf("allowlisted networks should be allowed")
+	}
+
+	if policy.IsAddressAllowed(netip.MustParseAddr("10.2.0.1")) {
+		t.Errorf("private address outside the allowlist should be blocked")
+	}
+}
+
+func TestSafeFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "http://"+r.Host+"/image", http.StatusFound)
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG\x0d\x0a\x1a\x0a"))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(strings.Repeat("<p>brochure</p>", 100)))
+		}
+	}))
+	defer server.Close()
+
+	previous := GetFetchPolicy()
+	defer SetFetchPolicy(previous)
+
+	SetFetchPolicy(DefaultFetchPolicy)
+	if _, err := SafeFetch(context.Background(), server.URL+"/image", FetchOptions{}); !errors.Is(err, ErrFetchBlocked) {
+		t.Fatalf("expected loopback to be blocked, got: %v", err)
+	}
+
+	policy := DefaultFetchPolicy
+	policy.Allowlist = []string{"127.0.0.0/8"}
+	SetFetchPolicy(policy)
+
+	result, err := SafeFetch(context.Background(), server.URL+"/redirect", FetchOptions{ContentTypes: []string{"image/"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result.ContentType != "image/png" || !strings.HasSuffix(result.URL, "/image") {
+		t.Errorf("unexpected result: %s, %s", result.ContentType, result.URL)
+	}
+
+	if _, err := SafeFetch(context.Background(), server.URL+"/page", FetchOptions{ContentTypes: []string{"image/"}}); err == nil {
+		t.Errorf("expected content type error")
+	}
+
+	if _, err := SafeFetch(context.Background(), server.URL+"/page", FetchOptions{MaxSize: 100}); err == nil {
+		t.Errorf("expected size limit error")
+	}
+
+	result, err = SafeFetch(context.Background(), server.URL+"/page", FetchOptions{MaxSize: 100, Truncate: true})
+	if err != nil || len(result.Content) != 100 {
+		t.Errorf("expected truncated content: %v", err)
+	}
+
+	if _, err := SafeFetch(context.Background(), "file:///etc/passwd", FetchOptions{}); !errors.Is(err, ErrFetchBlocked) {
+		t.Errorf("expected unsupported scheme to be blocked, got: %v", err)
+	}
+}
@@ -106,6 +106,7 @@ func main() {
 		RabbitMQMessagePublishErrorsInc: func() {
 			rabbitmq.MessagePublishErrors.Inc()
 		},
+		RabbitMQCacheSizeAdd: rabbitmq.CacheSizeCurrent.Add,
 		RabbitMQClientResolver: func(connectionString string) bool {
 			return rabbitmq.GetRabbitMQClient(connectionString) != nil
 		},
@@ -0,0 +1,41 @@
+package antivirus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+)
+
+// Scanner inspects attachment contents for malware
+type Scanner interface {
+	// Scan returns the verdict of the content, errors when it could not be scanned
+	Scan(ctx context.Context, content []byte) (*Verdict, error)
+
+	// Name of the scanner, reported on verdicts
+	Name() string
+}
+
+// Verdict is the result of a single scan
+type Verdict struct {
+	Infected bool
+
+	// malware name reported by the scanner, when infected
+	Signature string
+}
+
+// New creates the scanner of the given settings, nil when disabled
+func New(settings environment.AntivirusSettings) (Scanner, error) {
+	timeout := time.Duration(settings.Timeout) * time.Second
+
+	switch strings.ToLower(settings.Provider) {
+	case "":
+		return nil, nil
+	case "clamav", "clamd":
+		return NewClamdScanner(settings.Address, timeout)
+	default:
+		return nil, fmt.Errorf("unknown antivirus provider: %s, expected clamav", settings.Provider)
+	}
+}
@@ -0,0 +1,137 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serveFakeClamd answers a single INSTREAM command, flagging contents holding "EICAR"
+func serveFakeClamd(t *testing.T, listener net.Listener) {
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		command, err := reader.ReadString(0)
+		if err != nil || command != "zINSTREAM\x00" {
+			t.Errorf("unexpected command: %q, %v", command, err)
+			return
+		}
+
+		var content []byte
+		size := make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(reader, size); err != nil {
+				t.Errorf("unexpected chunk error: %v", err)
+				return
+			}
+
+			length := binary.BigEndian.Uint32(size)
+			if length == 0 {
+				break
+			}
+
+			chunk := make([]byte, length)
+			io.ReadFull(reader, chunk)
+			content = append(content, chunk...)
+		}
+
+		if bytes.Contains(content, []byte("EICAR")) {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		} else {
+			conn.Write([]byte("stream: OK\x00"))
+		}
+	}()
+}
+
+func TestClamdScannerTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer listener.Close()
+
+	serveFakeClamd(t, listener)
+
+	scanner, err := NewClamdScanner("tcp://"+listener.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// larger than a chunk, the signature on the last one
+	content := append(bytes.Repeat([]byte{'x'}, clamdChunkSize+10), []byte("EICAR")...)
+	verdict, err := scanner.Scan(context.Background(), content)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !verdict.Infected || verdict.Signature != "Eicar-Test-Signature" {
+		t.Errorf("unexpected verdict: %+v", verdict)
+	}
+}
+
+func TestClamdScannerUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clamd.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets not available: %s", err)
+	}
+	defer listener.Close()
+
+	serveFakeClamd(t, listener)
+
+	scanner, err := NewClamdScanner("unix://"+path, 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	verdict, err := scanner.Scan(context.Background(), []byte("brochure"))
+	if err != nil || verdict.Infected {
+		t.Errorf("unexpected verdict: %+v, %v", verdict, err)
+	}
+}
+
+func TestParseClamdAddress(t *testing.T) {
+	cases := []struct {
+		address string
+		network string
+		target  string
+	}{
+		{"tcp://clamav:3310", "tcp", "clamav:3310"},
+		{"clamav:3310", "tcp", "clamav:3310"},
+		{"unix:///var/run/clamav/clamd.ctl", "unix", "/var/run/clamav/clamd.ctl"},
+		{"/var/run/clamav/clamd.ctl", "unix", "/var/run/clamav/clamd.ctl"},
+	}
+
+	for _, c := range cases {
+		network, target, err := ParseClamdAddress(c.address)
+		if err != nil || network != c.network || target != c.target {
+			t.Errorf("ParseClamdAddress(%q) = %q, %q, %v", c.address, network, target, err)
+		}
+	}
+
+	if _, _, err := ParseClamdAddress("http://clamav"); err == nil {
+		t.Errorf("expected error for unknown scheme")
+	}
+}
+
+func TestParseClamdReply(t *testing.T) {
+	if _, err := ParseClamdReply("INSTREAM size limit exceeded. ERROR\x00"); err == nil {
+		t.Errorf("expected error reply")
+	}
+
+	verdict, err := ParseClamdReply("1: stream: Win.Test.EICAR_HDB-1 FOUND")
+	if err != nil || !verdict.Infected || verdict.Signature != "Win.Test.EICAR_HDB-1" {
+		t.Errorf("unexpected verdict: %+v, %v", verdict, err)
+	}
+}
@@ -0,0 +1,146 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// size of each INSTREAM chunk, below the clamd StreamMaxLength default
+const clamdChunkSize = 64 * 1024
+
+// ClamdScanner scans contents with the INSTREAM command of a ClamAV daemon
+type ClamdScanner struct {
+	Network string
+	Address string
+	Timeout time.Duration
+}
+
+// NewClamdScanner creates a scanner of the clamd at address, tcp://host:port or unix:///path/clamd.sock;
+// a bare host:port is taken as tcp and a bare path as unix socket
+func NewClamdScanner(address string, timeout time.Duration) (*ClamdScanner, error) {
+	network, location, err := ParseClamdAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClamdScanner{Network: network, Address: location, Timeout: timeout}, nil
+}
+
+// ParseClamdAddress returns the network and address to dial of a clamd address
+func ParseClamdAddress(address string) (string, string, error) {
+	address = strings.TrimSpace(address)
+	if len(address) == 0 {
+		return "", "", fmt.Errorf("empty clamd address")
+	}
+
+	if strings.HasPrefix(address, "/") {
+		return "unix", address, nil
+	}
+
+	if !strings.Contains(address, "://") {
+		return "tcp", address, nil
+	}
+
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid clamd address: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "tcp":
+		if len(parsed.Host) == 0 {
+			return "", "", fmt.Errorf("invalid clamd address, missing host: %s", address)
+		}
+		return "tcp", parsed.Host, nil
+	case "unix":
+		path := parsed.Path
+		if len(parsed.Host) > 0 {
+			path = parsed.Host + path
+		}
+		return "unix", path, nil
+	default:
+		return "", "", fmt.Errorf("invalid clamd address scheme: %s, expected tcp or unix", parsed.Scheme)
+	}
+}
+
+func (source *ClamdScanner) Name() string {
+	return "clamav"
+}
+
+// Scan streams the content to clamd, replies are "stream: OK", "stream: <signature> FOUND" or "<reason> ERROR"
+func (source *ClamdScanner) Scan(ctx context.Context, content []byte) (*Verdict, error) {
+	if source.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, source.Timeout)
+		defer cancel()
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, source.Network, source.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return nil, fmt.Errorf("failed to send clamd command: %w", err)
+	}
+
+	size := make([]byte, 4)
+	for offset := 0; offset < len(content); offset += clamdChunkSize {
+		end := min(offset+clamdChunkSize, len(content))
+
+		binary.BigEndian.PutUint32(size, uint32(end-offset))
+		writer.Write(size)
+		if _, err := writer.Write(content[offset:end]); err != nil {
+			return nil, fmt.Errorf("failed to stream content to clamd: %w", err)
+		}
+	}
+
+	// zero length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	writer.Write(size)
+	if err := writer.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to stream content to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && len(reply) == 0 {
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+
+	return ParseClamdReply(reply)
+}
+
+// ParseClamdReply returns the verdict of a clamd scan reply
+func ParseClamdReply(reply string) (*Verdict, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+
+	// drop the "stream:" or "1: stream:" prefix
+	result := reply
+	if index := strings.LastIndex(reply, ": "); index >= 0 {
+		result = reply[index+2:]
+	}
+
+	switch {
+	case result == "OK":
+		return &Verdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &Verdict{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	case strings.HasSuffix(reply, " ERROR"):
+		return nil, fmt.Errorf("clamd error: %s", strings.TrimSuffix(reply, " ERROR"))
+	default:
+		return nil, fmt.Errorf("unexpected clamd reply: %s", reply)
+	}
+}
@@ -12,8 +12,18 @@ import (
 )
 
 type QpToWhatsappAttachment struct {
-	Attach *whatsapp.WhatsappAttachment
-	Debug  []string `json:"debug,omitempty"`
+	Attach  *whatsapp.WhatsappAttachment
+	Options AttachmentOptions `json:"-"`
+	Debug   []string          `json:"debug,omitempty"`
+
+	// changes of the image pipeline, when any
+	Image *ImageReport `json:"image,omitempty"`
+}
+
+// AttachmentOptions holds the per session choices of the outbound attachment pipeline
+type AttachmentOptions struct {
+	// transcode videos that WhatsApp would not play inline into H.264/AAC MP4
+	TranscodeVideo bool
 }
 
 func IsValidExtensionFor(request string, content string) bool {
@@ -239,6 +249,8 @@ func (source *QpToWhatsappAttachment) AttachImageTreatment() {
 		return
 	}
 
+	source.AttachImageSafetyTreatment()
+
 	if !environment.Settings.General.ConvertPNGToJPG {
 		source.Debug = append(source.Debug, "[trace][AttachImageTreatment] PNG to JPG conversion is disabled in settings, returning without image validation")
 		return
@@ -281,3 +293,139 @@ func (source *QpToWhatsappAttachment) AttachImageTreatment() {
 
 	source.Debug = append(source.Debug, fmt.Sprintf("[success][AttachImageTreatment] PNG successfully converted to JPG. Original size: %d bytes, new size: %d bytes, new mime: %s, new filename: %s", originalSize, newSize, newMime, attach.FileName))
 }
+
+// AttachImageSafetyTreatment strips metadata, rotates, downsizes and converts images
+// as set by environment, keeping a report of the changes
+func (source *QpToWhatsappAttachment) AttachImageSafetyTreatment() {
+	attach := source.Attach
+	settings := environment.Settings.Image
+	if !settings.Enabled() {
+		return
+	}
+
+	mimeOnly := strings.ToLower(strings.Split(attach.Mimetype, ";")[0])
+	if len(mimeOnly) > 0 && !strings.HasPrefix(mimeOnly, "image/") && mimeOnly != "application/octet-stream" {
+		return
+	}
+
+	content := attach.GetContent()
+	if content == nil || len(*content) == 0 {
+		return
+	}
+
+	processed, report, err := ProcessImage(*content, attach.Mimetype, attach.FileName, settings)
+	if err != nil {
+		source.Debug = append(source.Debug, fmt.Sprintf("[error][AttachImageSafetyTreatment] failed to process image: %v", err))
+		log.Errorf("Failed to process image: %v", err)
+		return
+	}
+
+	if !report.Changed() {
+		return
+	}
+
+	attach.SetContent(&processed)
+	attach.Mimetype = report.Mime
+	attach.FileLength = report.Size
+
+	if report.Converted && len(attach.FileName) > 0 {
+		ext := filepath.Ext(attach.FileName)
+		attach.FileName = attach.FileName[:len(attach.FileName)-len(ext)] + ".jpg"
+	}
+
+	source.Image = report
+	source.Debug = append(source.Debug, fmt.Sprintf("[success][AttachImageSafetyTreatment] image processed, metadata stripped: %v, orientation: %d, resized: %v, converted: %v. Original: %d bytes (%s), New: %d bytes (%s)",
+		report.StrippedMetadata, report.Orientation, report.Resized, report.Converted, report.OriginalSize, report.OriginalMime, report.Size, report.Mime))
+}
+
+// IsVideoAttachment reports whether the attachment is a video to be treated, animated stickers excluded
+func IsVideoAttachment(mime string, filename string) bool {
+	mimeOnly := strings.TrimSpace(strings.Split(mime, ";")[0])
+	if mimeOnly == "video/webp" {
+		return false
+	}
+
+	if strings.HasPrefix(mimeOnly, "video/") {
+		return true
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".mov", ".avi", ".mkv", ".webm", ".m4v", ".3gp", ".wmv", ".flv", ".mpeg", ".mpg", ".ts":
+		return true
+	}
+	return false
+}
+
+// AttachVideoTreatment transcodes videos with incompatible codecs or containers into H.264/AAC MP4,
+// when enabled for the session, filling duration and thumbnail
+func (source *QpToWhatsappAttachment) AttachVideoTreatment() {
+	attach := source.Attach
+	if attach == nil {
+		source.Debug = append(source.Debug, "[warn][AttachVideoTreatment] nil attach")
+		return
+	}
+
+	if !source.Options.TranscodeVideo {
+		source.Debug = append(source.Debug, "[trace][AttachVideoTreatment] video transcoding is disabled for this session")
+		return
+	}
+
+	if !IsVideoAttachment(attach.Mimetype, attach.FileName) {
+		return
+	}
+
+	content := attach.GetContent()
+	if content == nil || len(*content) == 0 {
+		source.Debug = append(source.Debug, "[warn][AttachVideoTreatment] no content available for video treatment")
+		return
+	}
+
+	info, err := GetVideoInfoFromBytes(*content)
+	if err != nil {
+		source.Debug = append(source.Debug, fmt.Sprintf("[error][AttachVideoTreatment] failed to probe video: %v", err))
+		log.Errorf("Failed to probe video: %v", err)
+		return
+	}
+
+	source.Debug = append(source.Debug, fmt.Sprintf("[debug][AttachVideoTreatment] probed video, format: %s, brand: %s, video: %s (%s %dx%d), audio: %s, duration: %s",
+		info.FormatName, info.MajorBrand, info.VideoCodec, info.PixelFormat, info.Width, info.Height, info.AudioCodec, info.Duration))
+
+	if !info.IsWhatsappCompatible() {
+		maxBytes := environment.Settings.General.TranscodeVideoMaxSize
+		converted, err := TranscodeToWhatsappVideo(*content, info.Duration, maxBytes)
+		if err != nil {
+			source.Debug = append(source.Debug, fmt.Sprintf("[error][AttachVideoTreatment] failed to transcode video: %v", err))
+			log.Errorf("Failed to transcode video: %v", err)
+			return
+		}
+
+		originalSize := len(*content)
+		originalMime := attach.Mimetype
+		content = &converted
+		attach.SetContent(content)
+		attach.Mimetype = WhatsappVideoMime
+		attach.FileLength = uint64(len(converted))
+
+		if len(attach.FileName) > 0 {
+			ext := filepath.Ext(attach.FileName)
+			attach.FileName = attach.FileName[:len(attach.FileName)-len(ext)] + ".mp4"
+		}
+
+		source.Debug = append(source.Debug, fmt.Sprintf("[success][AttachVideoTreatment] transcoded to H.264/AAC MP4. Original: %d bytes (%s), New: %d bytes (%s)", originalSize, originalMime, len(converted), attach.Mimetype))
+	} else if strings.Split(attach.Mimetype, ";")[0] != WhatsappVideoMime {
+		// compatible content, declared with another mime, e.g. application/octet-stream
+		attach.Mimetype = WhatsappVideoMime
+	}
+
+	if attach.Seconds == 0 && info.Duration > 0 {
+		attach.Seconds = uint32(info.Duration.Seconds())
+	}
+
+	if attach.Thumbnail == nil {
+		if thumbnail, err := GenerateVideoThumbnail(*content, DefaultThumbnailConfig()); err != nil {
+			source.Debug = append(source.Debug, fmt.Sprintf("[warn][AttachVideoTreatment] failed to generate thumbnail: %v", err))
+		} else {
+			attach.SetThumbnail(thumbnail)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"strings"
 
 	log "github.com/nocodeleaks/quepasa/qplog"
 )
@@ -42,8 +41,11 @@ func ConvertToOggOpus(audioData []byte) ([]byte, error) {
 	defer os.Remove(outputFile.Name())
 	outputFile.Close()
 
+	ctx, cancel := GetFFmpegContext()
+	defer cancel()
+
 	// Convert using ffmpeg: input -> OGG with Opus codec, mono, 48kHz (WhatsApp standard)
-	cmd := exec.Command("ffmpeg",
+	cmd := exec.CommandContext(ctx, "ffmpeg",
 		"-i", inputFile.Name(),
 		"-c:a", "libopus",
 		"-b:a", "64k",
@@ -60,6 +62,9 @@ func ConvertToOggOpus(audioData []byte) ([]byte, error) {
 
 	log.Infof("Executing ffmpeg audio conversion to OGG Opus: %s", cmd.String())
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffmpeg audio conversion to OGG Opus timed out: %w", ctx.Err())
+		}
 		return nil, fmt.Errorf("error converting audio to OGG Opus with ffmpeg: %w\nstderr: %s", err, stderr.String())
 	}
 
@@ -79,7 +84,8 @@ func ConvertToOggOpus(audioData []byte) ([]byte, error) {
 
 // ConvertToWebP converts image or video data to WebP format using FFmpeg,
 // conforming to WhatsApp sticker requirements (512x512, transparent padding,
-// max 10 seconds at 15fps for animated stickers).
+// max 10 seconds for animated stickers, within the sticker size limits).
+// Quality and frame rate are lowered step by step until the output fits.
 //
 // Returns converted data, output MIME type ("image/webp" or "video/webp"), and any error.
 func ConvertToWebP(data []byte, inputMime string) ([]byte, string, error) {
@@ -87,19 +93,11 @@ func ConvertToWebP(data []byte, inputMime string) ([]byte, string, error) {
 		return nil, "", fmt.Errorf("ffmpeg is not available for WebP conversion: %w", GetInitError())
 	}
 
-	inputFile, err := os.CreateTemp("", "sticker-input-*")
+	inputFile, err := writeTempFile("sticker-input-*", data)
 	if err != nil {
-		return nil, "", fmt.Errorf("error creating temporary input file: %w", err)
-	}
-	defer os.Remove(inputFile.Name())
-
-	if _, err := inputFile.Write(data); err != nil {
-		inputFile.Close()
-		return nil, "", fmt.Errorf("error writing data to temporary input file: %w", err)
-	}
-	if err := inputFile.Close(); err != nil {
-		return nil, "", fmt.Errorf("error closing temporary input file: %w", err)
+		return nil, "", err
 	}
+	defer os.Remove(inputFile)
 
 	outputFile, err := os.CreateTemp("", "sticker-output-*.webp")
 	if err != nil {
@@ -109,59 +107,52 @@ func ConvertToWebP(data []byte, inputMime string) ([]byte, string, error) {
 	outputFile.Close()
 
 	// Determine if this is a video (animated) or image (static) sticker
-	isVideo := strings.HasPrefix(inputMime, "video/") ||
-		inputMime == "image/gif" ||
-		inputMime == "image/apng"
-
-	var cmd *exec.Cmd
-	var outputMime string
+	isVideo := IsAnimatedStickerSource(data, inputMime)
 
+	outputMime := "image/webp"
+	maxSize := WhatsappStickerMaxStaticSize
 	if isVideo {
-		// Animated sticker: scale to 512x512 with transparent padding,
-		// limit to 10 seconds at 15fps, output as animated WebP.
-		cmd = exec.Command("ffmpeg",
-			"-i", inputFile.Name(),
-			"-t", "10", // max 10 seconds
-			"-vf", "fps=15,scale=512:512:force_original_aspect_ratio=decrease,pad=512:512:(ow-iw)/2:(oh-ih)/2:color=0x00000000",
-			"-loop", "0",
-			"-f", "webp",
-			"-y",
-			outputFile.Name(),
-		)
 		outputMime = "video/webp"
-	} else {
-		// Static sticker: scale to 512x512 with transparent padding,
-		// output as static WebP.
-		cmd = exec.Command("ffmpeg",
-			"-i", inputFile.Name(),
-			"-vf", "scale=512:512:force_original_aspect_ratio=decrease,pad=512:512:(ow-iw)/2:(oh-ih)/2:color=0x00000000",
-			"-vframes", "1",
-			"-f", "webp",
-			"-y",
-			outputFile.Name(),
-		)
-		outputMime = "image/webp"
+		maxSize = WhatsappStickerMaxAnimatedSize
 	}
 
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+	// retries of lower quality share the bound of the whole conversion
+	ctx, cancel := GetFFmpegContext()
+	defer cancel()
 
-	log.Infof("Executing ffmpeg WebP conversion (animated=%v): %s", isVideo, cmd.String())
-	if err := cmd.Run(); err != nil {
-		return nil, "", fmt.Errorf("error converting to WebP with ffmpeg: %w\nstderr: %s", err, stderr.String())
-	}
+	var convertedData []byte
+	for _, attempt := range GetStickerAttempts(isVideo) {
+		cmd := exec.CommandContext(ctx, "ffmpeg", GetStickerConvertArgs(inputFile, outputFile.Name(), isVideo, attempt)...)
 
-	convertedData, err := os.ReadFile(outputFile.Name())
-	if err != nil {
-		return nil, "", fmt.Errorf("error reading converted WebP file: %w", err)
-	}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
 
-	if len(convertedData) == 0 {
-		return nil, "", fmt.Errorf("converted WebP file is empty")
+		log.Infof("Executing ffmpeg WebP conversion (animated=%v): %s", isVideo, cmd.String())
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return nil, "", fmt.Errorf("ffmpeg WebP conversion timed out: %w", ctx.Err())
+			}
+			return nil, "", fmt.Errorf("error converting to WebP with ffmpeg: %w\nstderr: %s", err, stderr.String())
+		}
+
+		convertedData, err = os.ReadFile(outputFile.Name())
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading converted WebP file: %w", err)
+		}
+
+		if len(convertedData) == 0 {
+			return nil, "", fmt.Errorf("converted WebP file is empty")
+		}
+
+		if len(convertedData) <= maxSize {
+			log.Infof("Media successfully converted to WebP (%s): %d bytes input -> %d bytes output", outputMime, len(data), len(convertedData))
+			return convertedData, outputMime, nil
+		}
+
+		log.Debugf("WebP sticker exceeds size limit (%d > %d bytes) at quality %d, retrying", len(convertedData), maxSize, attempt.Quality)
 	}
 
-	log.Infof("Media successfully converted to WebP (%s): %d bytes input -> %d bytes output", outputMime, len(data), len(convertedData))
-	return convertedData, outputMime, nil
+	return nil, "", fmt.Errorf("converted WebP sticker exceeds size limit: %d > %d bytes", len(convertedData), maxSize)
 }
 
 // ShouldConvertToPTT checks if the given MIME type is an audio format that should be
@@ -0,0 +1,266 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	log "github.com/nocodeleaks/quepasa/qplog"
+)
+
+// office document mime types and the extension LibreOffice expects on the input file
+var officeDocumentExtensions = map[string]string{
+	"application/msword": ".doc",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": ".docx",
+	"application/vnd.ms-excel": ".xls",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.ms-powerpoint":                                             ".ppt",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	"application/vnd.oasis.opendocument.text":                                   ".odt",
+	"application/vnd.oasis.opendocument.spreadsheet":                            ".ods",
+	"application/vnd.oasis.opendocument.presentation":                           ".odp",
+	"application/rtf": ".rtf",
+	"text/rtf":        ".rtf",
+}
+
+// DocumentPreview is a first page (or frame) thumbnail of a document, image or video
+type DocumentPreview struct {
+	// JPEG image
+	Thumbnail []byte `json:"-"`
+
+	// pages of pdf and office documents, 0 when unknown
+	PageCount uint32 `json:"pagecount,omitempty"`
+}
+
+var libreOfficeAvailable bool
+var libreOfficeOnce sync.Once
+
+var pdfinfoAvailable bool
+var pdfinfoOnce sync.Once
+
+// IsLibreOfficeAvailable checks if office previews are enabled and LibreOffice is installed
+func IsLibreOfficeAvailable() bool {
+	libreOfficeOnce.Do(func() {
+		settings := environment.Settings.Preview
+		if !settings.Office {
+			return
+		}
+
+		_, err := exec.LookPath(settings.OfficeCommand)
+		libreOfficeAvailable = (err == nil)
+		if !libreOfficeAvailable {
+			log.Warnf("LibreOffice (%s) not available for office document previews", settings.OfficeCommand)
+		}
+	})
+	return libreOfficeAvailable
+}
+
+// IsPdfinfoAvailable checks if Poppler (pdfinfo) is available for PDF page counts
+func IsPdfinfoAvailable() bool {
+	pdfinfoOnce.Do(func() {
+		_, err := exec.LookPath("pdfinfo")
+		pdfinfoAvailable = (err == nil)
+	})
+	return pdfinfoAvailable
+}
+
+// GetOfficeDocumentExtension returns the extension of an office document, by mime or file name;
+// empty for any other content
+func GetOfficeDocumentExtension(mimeType string, filename string) string {
+	mimeOnly := strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	if extension, ok := officeDocumentExtensions[mimeOnly]; ok {
+		return extension
+	}
+
+	// generic mimes are common on documents sent from desktops
+	if len(mimeOnly) == 0 || mimeOnly == "application/octet-stream" || mimeOnly == "application/zip" {
+		extension := strings.ToLower(filepath.Ext(filename))
+		for _, known := range officeDocumentExtensions {
+			if known == extension {
+				return extension
+			}
+		}
+	}
+	return ""
+}
+
+// IsOfficeDocument reports whether the content is an office document, by mime or file name
+func IsOfficeDocument(mimeType string, filename string) bool {
+	return len(GetOfficeDocumentExtension(mimeType, filename)) > 0
+}
+
+// CanGeneratePreview checks if a preview is supported for a mime type and file name
+func CanGeneratePreview(mimeType string, filename string) bool {
+	if IsOfficeDocument(mimeType, filename) {
+		return IsLibreOfficeAvailable() && IsPopplerAvailable()
+	}
+	return CanGenerateThumbnail(mimeType)
+}
+
+// GeneratePreview renders the thumbnail of the first page of pdf and office documents, with their
+// page count, or of images and videos
+func GeneratePreview(data []byte, mimeType string, filename string, config ThumbnailConfig) (*DocumentPreview, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty document data")
+	}
+
+	mimeOnly := strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	if extension := GetOfficeDocumentExtension(mimeOnly, filename); len(extension) > 0 {
+		if !IsLibreOfficeAvailable() {
+			return nil, fmt.Errorf("LibreOffice not available for office document previews")
+		}
+
+		if maxSize := environment.Settings.Preview.MaxSize; maxSize > 0 && uint64(len(data)) > maxSize {
+			return nil, fmt.Errorf("document too large for preview: %d bytes, limit: %d", len(data), maxSize)
+		}
+
+		pdf, err := ConvertOfficeToPDF(data, extension)
+		if err != nil {
+			return nil, err
+		}
+		data, mimeOnly = pdf, "application/pdf"
+	}
+
+	switch {
+	case mimeOnly == "application/pdf":
+		thumbnail, err := GeneratePDFThumbnail(data, config)
+		if err != nil {
+			return nil, err
+		}
+		return &DocumentPreview{Thumbnail: thumbnail, PageCount: GetPDFPageCount(data)}, nil
+	case strings.HasPrefix(mimeOnly, "image/"):
+		thumbnail, err := GenerateImageThumbnail(data, config)
+		if err != nil {
+			return nil, err
+		}
+		return &DocumentPreview{Thumbnail: thumbnail}, nil
+	case strings.HasPrefix(mimeOnly, "video/"):
+		thumbnail, err := GenerateVideoThumbnail(data, config)
+		if err != nil {
+			return nil, err
+		}
+		return &DocumentPreview{Thumbnail: thumbnail}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported MIME type for preview generation: %s", mimeType)
+}
+
+// GetLibreOfficeConvertArgs returns the arguments converting input to pdf into outdir, with a
+// dedicated profile so concurrent conversions do not lock each other
+func GetLibreOfficeConvertArgs(profile string, outdir string, input string) []string {
+	return []string{
+		"--headless",
+		"--norestore",
+		"--nolockcheck",
+		"-env:UserInstallation=file://" + filepath.ToSlash(profile),
+		"--convert-to", "pdf",
+		"--outdir", outdir,
+		input,
+	}
+}
+
+// ConvertOfficeToPDF converts an office document to PDF with LibreOffice headless,
+// extension of the document (".docx", ".xlsx", ...) tells the import filter
+func ConvertOfficeToPDF(data []byte, extension string) ([]byte, error) {
+	settings := environment.Settings.Preview
+
+	workdir, err := os.MkdirTemp("", "office-preview-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(workdir)
+
+	input := filepath.Join(workdir, "document"+extension)
+	if err := os.WriteFile(input, data, 0600); err != nil {
+		return nil, fmt.Errorf("error writing document data: %w", err)
+	}
+
+	outdir := filepath.Join(workdir, "output")
+	profile := filepath.Join(workdir, "profile")
+
+	ctx := context.Background()
+	if settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(settings.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, settings.OfficeCommand, GetLibreOfficeConvertArgs(profile, outdir, input)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	log.Tracef("Executing LibreOffice conversion: %s", cmd.String())
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("LibreOffice conversion timed out after %d seconds", settings.Timeout)
+		}
+		return nil, fmt.Errorf("LibreOffice conversion failed: %w\nstderr: %s", err, stderr.String())
+	}
+
+	pdf, err := os.ReadFile(filepath.Join(outdir, "document.pdf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LibreOffice conversion output: %w, stderr: %s", err, stderr.String())
+	}
+
+	log.Debugf("Office document converted to PDF: %d bytes input -> %d bytes output", len(data), len(pdf))
+	return pdf, nil
+}
+
+var pdfinfoPagesRegex = regexp.MustCompile(`(?m)^Pages:\s+(\d+)`)
+var pdfPageObjectRegex = regexp.MustCompile(`/Type\s*/Page\b`)
+
+// GetPDFPageCount returns the number of pages of a PDF, by Poppler (pdfinfo) when available
+// or by counting page objects, 0 when unknown
+func GetPDFPageCount(data []byte) uint32 {
+	if IsPdfinfoAvailable() {
+		count, err := getPDFPageCountWithPoppler(data)
+		if err == nil {
+			return count
+		}
+		log.Debugf("pdfinfo page count failed, counting page objects: %v", err)
+	}
+	return CountPDFPageObjects(data)
+}
+
+// CountPDFPageObjects counts the uncompressed page objects of a PDF;
+// pages inside compressed object streams are not found
+func CountPDFPageObjects(data []byte) uint32 {
+	return uint32(len(pdfPageObjectRegex.FindAllIndex(data, -1)))
+}
+
+func getPDFPageCountWithPoppler(data []byte) (uint32, error) {
+	input, err := writeTempFile("pdfinfo-input-*.pdf", data)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(input)
+
+	ctx, cancel := GetFFmpegContext()
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "pdfinfo", input).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("pdfinfo timed out: %w", ctx.Err())
+		}
+		return 0, err
+	}
+
+	matches := pdfinfoPagesRegex.FindSubmatch(output)
+	if matches == nil {
+		return 0, fmt.Errorf("pages not found on pdfinfo output")
+	}
+
+	count, err := strconv.ParseUint(string(matches[1]), 10, 32)
+	return uint32(count), err
+}
@@ -0,0 +1,50 @@
+package media
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetOfficeDocumentExtension(t *testing.T) {
+	cases := []struct {
+		mime      string
+		filename  string
+		extension string
+	}{
+		{"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "", ".docx"},
+		{"application/vnd.ms-excel; charset=binary", "", ".xls"},
+		{"application/octet-stream", "Brochure.PPTX", ".pptx"},
+		{"application/zip", "sheet.ods", ".ods"},
+		{"application/pdf", "brochure.docx", ""},
+		{"image/png", "", ""},
+	}
+
+	for _, c := range cases {
+		if extension := GetOfficeDocumentExtension(c.mime, c.filename); extension != c.extension {
+			t.Errorf("GetOfficeDocumentExtension(%q, %q) = %q, expected %q", c.mime, c.filename, extension, c.extension)
+		}
+	}
+}
+
+func TestCountPDFPageObjects(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n" +
+		"1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n" +
+		"2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >> endobj\n" +
+		"3 0 obj << /Type /Page /Parent 2 0 R >> endobj\n" +
+		"4 0 obj <</Type/Page/Parent 2 0 R>> endobj\n")
+
+	if count := CountPDFPageObjects(pdf); count != 2 {
+		t.Errorf("unexpected page count: %d", count)
+	}
+}
+
+func TestGetLibreOfficeConvertArgs(t *testing.T) {
+	args := GetLibreOfficeConvertArgs("/tmp/work/profile", "/tmp/work/output", "/tmp/work/document.docx")
+	if !slices.Contains(args, "--headless") || !slices.Contains(args, "-env:UserInstallation=file:///tmp/work/profile") {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if args[len(args)-1] != "/tmp/work/document.docx" {
+		t.Errorf("input should be the last argument: %v", args)
+	}
+}
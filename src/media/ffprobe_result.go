@@ -8,11 +8,17 @@ type FFProbeResult struct {
 		Duration   string `json:"duration"`
 		FormatName string `json:"format_name"`
 		BitRate    string `json:"bit_rate"`
+
+		// container tags, major_brand distinguishes quicktime from mp4
+		Tags map[string]string `json:"tags"`
 	} `json:"format"`
 	Streams []struct {
 		CodecType  string `json:"codec_type"`
 		CodecName  string `json:"codec_name"`
 		Channels   int    `json:"channels"`
 		SampleRate string `json:"sample_rate"`
+		Width      int    `json:"width"`
+		Height     int    `json:"height"`
+		PixFmt     string `json:"pix_fmt"`
 	} `json:"streams"`
 }
@@ -0,0 +1,199 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a}
+
+// png chunks carrying metadata, removed when stripping
+var pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "iTXt": true, "zTXt": true, "tIME": true}
+
+// GetJpegOrientation returns the EXIF orientation (1 to 8) of a JPEG image, 1 when not informed
+func GetJpegOrientation(data []byte) int {
+	orientation := 1
+	_ = walkJpegSegments(data, func(marker byte, segment []byte) bool {
+		if marker != 0xE1 || len(segment) < 10 || !bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")) {
+			return true
+		}
+
+		if value := getExifOrientation(segment[10:]); value >= 1 && value <= 8 {
+			orientation = value
+		}
+		return false
+	})
+	return orientation
+}
+
+// getExifOrientation reads the orientation tag (0x0112) of the first IFD of a TIFF structure
+func getExifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 0
+}
+
+// StripJpegMetadata removes EXIF, XMP, IPTC, comments and data appended after the image,
+// without re-encoding; JFIF, ICC profile and Adobe color segments are kept.
+// Returns whether anything was removed.
+func StripJpegMetadata(data []byte) ([]byte, bool, error) {
+	result := make([]byte, 0, len(data))
+	result = append(result, 0xFF, 0xD8)
+
+	err := walkJpegSegments(data, func(marker byte, segment []byte) bool {
+		if isJpegMetadataSegment(marker, segment) {
+			return true
+		}
+		result = append(result, segment...)
+		return true
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, len(result) != len(data), nil
+}
+
+func isJpegMetadataSegment(marker byte, segment []byte) bool {
+	switch {
+	case marker == 0xE0, marker == 0xEE:
+		// JFIF and Adobe, required to decode colors correctly
+		return false
+	case marker == 0xE2:
+		// ICC profile kept, multi picture (MPF) entries point to images appended after the end
+		return !(len(segment) > 16 && bytes.HasPrefix(segment[4:], []byte("ICC_PROFILE\x00")))
+	case marker >= 0xE1 && marker <= 0xEF, marker == 0xFE:
+		return true
+	}
+	return false
+}
+
+// walkJpegSegments calls visit with every segment after SOI, markers included, until EOI;
+// scan segments include their entropy coded data. Data after EOI is ignored.
+func walkJpegSegments(data []byte, visit func(marker byte, segment []byte) bool) error {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return fmt.Errorf("not a jpeg image")
+	}
+
+	for offset := 2; offset < len(data); {
+		if data[offset] != 0xFF {
+			return fmt.Errorf("invalid jpeg marker at %d", offset)
+		}
+
+		// fill bytes
+		start := offset
+		for offset < len(data) && data[offset] == 0xFF {
+			offset++
+		}
+		if offset >= len(data) {
+			return fmt.Errorf("truncated jpeg marker")
+		}
+
+		marker := data[offset]
+		offset++
+
+		switch {
+		case marker == 0xD9:
+			visit(marker, data[start:offset])
+			return nil
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			if !visit(marker, data[start:offset]) {
+				return nil
+			}
+			continue
+		}
+
+		if offset+2 > len(data) {
+			return fmt.Errorf("truncated jpeg segment")
+		}
+
+		end := offset + int(binary.BigEndian.Uint16(data[offset:offset+2]))
+		if end > len(data) || end < offset+2 {
+			return fmt.Errorf("invalid jpeg segment length at %d", start)
+		}
+
+		if marker == 0xDA {
+			// entropy coded data, until a marker other than stuffing or restart
+			for end < len(data)-1 {
+				if data[end] == 0xFF && data[end+1] != 0x00 && !(data[end+1] >= 0xD0 && data[end+1] <= 0xD7) && data[end+1] != 0xFF {
+					break
+				}
+				end++
+			}
+			if end >= len(data)-1 {
+				end = len(data)
+			}
+		}
+
+		if !visit(marker, data[start:end]) {
+			return nil
+		}
+		offset = end
+	}
+
+	return nil
+}
+
+// StripPngMetadata removes text, time and EXIF chunks of a PNG image, without re-encoding.
+// Returns whether anything was removed.
+func StripPngMetadata(data []byte) ([]byte, bool, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, false, fmt.Errorf("not a png image")
+	}
+
+	result := make([]byte, 0, len(data))
+	result = append(result, pngSignature...)
+
+	for offset := len(pngSignature); offset < len(data); {
+		if offset+12 > len(data) {
+			return nil, false, fmt.Errorf("truncated png chunk at %d", offset)
+		}
+
+		length := int(binary.BigEndian.Uint32(data[offset : offset+4]))
+		end := offset + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, false, fmt.Errorf("invalid png chunk length at %d", offset)
+		}
+
+		name := string(data[offset+4 : offset+8])
+		if !pngMetadataChunks[name] {
+			result = append(result, data[offset:end]...)
+		}
+
+		offset = end
+		if name == "IEND" {
+			break
+		}
+	}
+
+	return result, len(result) != len(data), nil
+}
@@ -0,0 +1,299 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	log "github.com/nocodeleaks/quepasa/qplog"
+)
+
+// ImageReport describes what the outbound image pipeline changed, returned on send responses
+type ImageReport struct {
+	StrippedMetadata bool `json:"strippedmetadata,omitempty"`
+
+	// EXIF orientation applied to the pixels, 0 when not rotated
+	Orientation int `json:"orientation,omitempty"`
+
+	Resized   bool `json:"resized,omitempty"`
+	Converted bool `json:"converted,omitempty"`
+
+	OriginalMime   string `json:"originalmime"`
+	OriginalSize   uint64 `json:"originalsize"`
+	OriginalWidth  int    `json:"originalwidth,omitempty"`
+	OriginalHeight int    `json:"originalheight,omitempty"`
+
+	Mime   string `json:"mime"`
+	Size   uint64 `json:"size"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Changed reports whether the image content was modified
+func (source *ImageReport) Changed() bool {
+	return source.StrippedMetadata || source.Orientation > 0 || source.Resized || source.Converted || source.Size != source.OriginalSize
+}
+
+// ImageEncodeAttempt is a set of JPEG encoding parameters, tried in order until the size limit fits
+type ImageEncodeAttempt struct {
+	// ffmpeg mjpeg quality scale, 2 (best) to 31
+	Quality int
+
+	// factor applied to the dimensions
+	Scale float64
+}
+
+var imageEncodeAttempts = []ImageEncodeAttempt{
+	{Quality: 2, Scale: 1},
+	{Quality: 5, Scale: 1},
+	{Quality: 8, Scale: 0.75},
+	{Quality: 12, Scale: 0.5},
+}
+
+// GetImageFormat returns the format treated by the image pipeline: jpeg, png, heic, tiff or bmp,
+// by content signature, mime or file extension; empty for any other format
+func GetImageFormat(content []byte, mime string, filename string) string {
+	switch {
+	case bytes.HasPrefix(content, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case bytes.HasPrefix(content, pngSignature):
+		return "png"
+	case len(content) > 12 && string(content[4:8]) == "ftyp":
+		switch string(content[8:12]) {
+		case "heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1":
+			return "heic"
+		}
+	case bytes.HasPrefix(content, []byte("II*\x00")), bytes.HasPrefix(content, []byte("MM\x00*")):
+		return "tiff"
+	case bytes.HasPrefix(content, []byte("BM")) && len(content) > 14:
+		return "bmp"
+	}
+
+	mimeOnly := strings.ToLower(strings.TrimSpace(strings.Split(mime, ";")[0]))
+	switch mimeOnly {
+	case "image/heic", "image/heif", "image/heic-sequence", "image/heif-sequence":
+		return "heic"
+	case "image/tiff", "image/tiff-fx":
+		return "tiff"
+	case "image/bmp", "image/x-bmp", "image/x-ms-bmp":
+		return "bmp"
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".heic", ".heif":
+		return "heic"
+	case ".tif", ".tiff":
+		return "tiff"
+	case ".bmp":
+		return "bmp"
+	}
+	return ""
+}
+
+// ProcessImage applies the outbound image pipeline of settings: metadata stripping, rotation
+// by EXIF orientation, downsizing and conversion of HEIC/TIFF/BMP to JPEG.
+// Formats not handled (gif, webp, svg, ...) are returned untouched.
+func ProcessImage(content []byte, mime string, filename string, settings environment.ImageSettings) ([]byte, *ImageReport, error) {
+	report := &ImageReport{
+		OriginalMime: mime,
+		OriginalSize: uint64(len(content)),
+		Mime:         mime,
+		Size:         uint64(len(content)),
+	}
+
+	format := GetImageFormat(content, mime, filename)
+	if len(format) == 0 {
+		return content, report, nil
+	}
+
+	if config, _, err := image.DecodeConfig(bytes.NewReader(content)); err == nil {
+		report.OriginalWidth, report.OriginalHeight = config.Width, config.Height
+		report.Width, report.Height = config.Width, config.Height
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = GetJpegOrientation(content)
+	}
+
+	// stripping metadata of a rotated photo would lose its orientation, so it is also applied
+	rotate := orientation > 1 && (settings.AutoRotate || settings.StripMetadata)
+	convert := settings.Convert && (format == "heic" || format == "tiff" || format == "bmp")
+
+	maxDimension := int(settings.MaxDimension)
+	oversized := maxDimension > 0 && (report.OriginalWidth > maxDimension || report.OriginalHeight > maxDimension)
+	heavy := settings.MaxSize > 0 && report.OriginalSize > settings.MaxSize
+	encodable := format == "jpeg" || format == "png" || convert
+
+	if !encodable || !(rotate || convert || oversized || heavy) {
+		if !settings.StripMetadata {
+			return content, report, nil
+		}
+
+		var stripped []byte
+		var changed bool
+		var err error
+		switch format {
+		case "jpeg":
+			stripped, changed, err = StripJpegMetadata(content)
+		case "png":
+			stripped, changed, err = StripPngMetadata(content)
+		default:
+			return content, report, nil
+		}
+
+		if err != nil || !changed {
+			return content, report, err
+		}
+
+		report.StrippedMetadata = true
+		report.Size = uint64(len(stripped))
+		return stripped, report, nil
+	}
+
+	if !rotate {
+		orientation = 1
+	}
+
+	encoded, err := EncodeWhatsappJpeg(content, orientation, settings.MaxDimension, settings.MaxSize)
+	if err != nil {
+		return content, report, err
+	}
+
+	report.StrippedMetadata = true
+	report.Converted = format != "jpeg"
+	report.Mime = "image/jpeg"
+	report.Size = uint64(len(encoded))
+	if orientation > 1 {
+		report.Orientation = orientation
+	}
+
+	if config, _, err := image.DecodeConfig(bytes.NewReader(encoded)); err == nil {
+		report.Width, report.Height = config.Width, config.Height
+
+		// orientations 5 to 8 swap width and height
+		width, height := report.OriginalWidth, report.OriginalHeight
+		if orientation >= 5 {
+			width, height = height, width
+		}
+		report.Resized = width > 0 && (config.Width < width || config.Height < height)
+	}
+
+	return encoded, report, nil
+}
+
+// GetImageOrientationFilters returns the ffmpeg filters that apply an EXIF orientation to the pixels
+func GetImageOrientationFilters(orientation int) []string {
+	switch orientation {
+	case 2:
+		return []string{"hflip"}
+	case 3:
+		return []string{"hflip", "vflip"}
+	case 4:
+		return []string{"vflip"}
+	case 5:
+		return []string{"transpose=0"}
+	case 6:
+		return []string{"transpose=1"}
+	case 7:
+		return []string{"transpose=3"}
+	case 8:
+		return []string{"transpose=2"}
+	}
+	return nil
+}
+
+// GetImageEncodeArgs returns the ffmpeg arguments to encode a JPEG without metadata,
+// rotated by orientation and fitting the max dimension scaled by the attempt
+func GetImageEncodeArgs(input string, output string, orientation int, maxDimension uint32, attempt ImageEncodeAttempt) []string {
+	filters := GetImageOrientationFilters(orientation)
+	if maxDimension > 0 {
+		filters = append(filters, fmt.Sprintf("scale=w='min(iw*%g,%d)':h='min(ih*%g,%d)':force_original_aspect_ratio=decrease", attempt.Scale, maxDimension, attempt.Scale, maxDimension))
+	} else if attempt.Scale < 1 {
+		filters = append(filters, fmt.Sprintf("scale=w='iw*%g':h='ih*%g'", attempt.Scale, attempt.Scale))
+	}
+
+	// exif orientation is applied by the filters above, never by the decoder
+	args := []string{"-noautorotate", "-i", input}
+	if len(filters) > 0 {
+		args = append(args, "-vf", strings.Join(filters, ","))
+	}
+
+	return append(args,
+		"-map_metadata", "-1",
+		"-frames:v", "1",
+		"-f", "image2",
+		"-vcodec", "mjpeg",
+		"-pix_fmt", "yuvj420p",
+		"-q:v", fmt.Sprint(attempt.Quality),
+		"-y", output,
+	)
+}
+
+// EncodeWhatsappJpeg re-encodes an image as JPEG with FFmpeg, dropping metadata.
+// When maxSize is set, quality and dimensions are lowered step by step until it fits,
+// the smallest result is returned if none fits.
+func EncodeWhatsappJpeg(data []byte, orientation int, maxDimension uint32, maxSize uint64) ([]byte, error) {
+	if !IsFFmpegImageAvailable() {
+		return nil, fmt.Errorf("ffmpeg is not available for image conversion: %w", GetFFmpegImageError())
+	}
+
+	input, err := writeTempFile("image-input-*", data)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(input)
+
+	outputFile, err := os.CreateTemp("", "image-output-*.jpg")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary output file: %w", err)
+	}
+	output := outputFile.Name()
+	outputFile.Close()
+	defer os.Remove(output)
+
+	// one deadline for every attempt, the encoding as a whole is bounded
+	ctx, cancel := GetFFmpegContext()
+	defer cancel()
+
+	var encoded []byte
+	for _, attempt := range imageEncodeAttempts {
+		cmd := exec.CommandContext(ctx, "ffmpeg", GetImageEncodeArgs(input, output, orientation, maxDimension, attempt)...)
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		log.Tracef("Executing FFmpeg image encoding: %s", cmd.String())
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("ffmpeg image encoding timed out: %w", ctx.Err())
+			}
+			return nil, fmt.Errorf("error encoding image with ffmpeg: %w\nstderr: %s", err, stderr.String())
+		}
+
+		encoded, err = os.ReadFile(output)
+		if err != nil {
+			return nil, fmt.Errorf("error reading encoded image: %w", err)
+		}
+
+		if len(encoded) == 0 {
+			return nil, fmt.Errorf("encoded image is empty")
+		}
+
+		if maxSize == 0 || uint64(len(encoded)) <= maxSize {
+			break
+		}
+
+		log.Debugf("Encoded image exceeds size limit (%d > %d bytes) at quality %d, scale %g, retrying", len(encoded), maxSize, attempt.Quality, attempt.Scale)
+	}
+
+	log.Debugf("Image successfully encoded to JPEG: %d bytes input -> %d bytes output", len(data), len(encoded))
+	return encoded, nil
+}
@@ -0,0 +1,169 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"slices"
+	"strings"
+	"testing"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+)
+
+// exifSegment builds an APP1 segment with a big endian TIFF holding only the orientation tag
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("MM\x00*\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func testJpeg(t *testing.T, orientation uint16) []byte {
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, image.NewGray(image.Rect(0, 0, 8, 4)), nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data := buffer.Bytes()
+	result := append([]byte{}, data[:2]...)
+	result = append(result, exifSegment(orientation)...)
+	result = append(result, data[2:]...)
+
+	// multi picture style content appended after the end of image
+	return append(result, []byte("trailing GPS data")...)
+}
+
+func TestGetJpegOrientation(t *testing.T) {
+	if orientation := GetJpegOrientation(testJpeg(t, 6)); orientation != 6 {
+		t.Errorf("unexpected orientation: %d", orientation)
+	}
+
+	stripped, _, _ := StripJpegMetadata(testJpeg(t, 6))
+	if orientation := GetJpegOrientation(stripped); orientation != 1 {
+		t.Errorf("unexpected orientation without exif: %d", orientation)
+	}
+}
+
+func TestStripJpegMetadata(t *testing.T) {
+	data := testJpeg(t, 1)
+
+	stripped, changed, err := StripJpegMetadata(data)
+	if err != nil || !changed {
+		t.Fatalf("expected stripped content: %v, %v", changed, err)
+	}
+
+	if bytes.Contains(stripped, []byte("Exif")) || bytes.Contains(stripped, []byte("trailing")) {
+		t.Errorf("metadata not removed")
+	}
+
+	if !bytes.HasSuffix(stripped, []byte{0xFF, 0xD9}) {
+		t.Errorf("missing end of image")
+	}
+
+	config, err := jpeg.DecodeConfig(bytes.NewReader(stripped))
+	if err != nil || config.Width != 8 || config.Height != 4 {
+		t.Errorf("invalid stripped image: %+v, %v", config, err)
+	}
+
+	if _, changed, _ := StripJpegMetadata(stripped); changed {
+		t.Errorf("expected no changes on a clean image")
+	}
+}
+
+func TestStripPngMetadata(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// text chunk inserted after IHDR, crc is not verified by the stripping
+	data := buffer.Bytes()
+	ihdrEnd := len(pngSignature) + 12 + 13
+	text := binary.BigEndian.AppendUint32(nil, 11)
+	text = append(text, []byte("tEXtComment\x00GPS")...)
+	text = append(text, 0, 0, 0, 0)
+
+	tagged := append([]byte{}, data[:ihdrEnd]...)
+	tagged = append(tagged, text...)
+	tagged = append(tagged, data[ihdrEnd:]...)
+
+	stripped, changed, err := StripPngMetadata(tagged)
+	if err != nil || !changed || !bytes.Equal(stripped, data) {
+		t.Errorf("unexpected stripped content: %v, %v", changed, err)
+	}
+}
+
+func TestGetImageFormat(t *testing.T) {
+	cases := []struct {
+		content  []byte
+		mime     string
+		filename string
+		format   string
+	}{
+		{[]byte{0xFF, 0xD8, 0xFF, 0xE0}, "application/octet-stream", "", "jpeg"},
+		{[]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), "", "", "heic"},
+		{[]byte("II*\x00...."), "", "", "tiff"},
+		{nil, "image/x-ms-bmp", "", "bmp"},
+		{nil, "application/octet-stream", "IMG_0001.HEIC", "heic"},
+		{[]byte("GIF89a"), "image/gif", "anim.gif", ""},
+	}
+
+	for _, c := range cases {
+		if format := GetImageFormat(c.content, c.mime, c.filename); format != c.format {
+			t.Errorf("GetImageFormat(%q, %q) = %q, expected %q", c.mime, c.filename, format, c.format)
+		}
+	}
+}
+
+func TestGetImageEncodeArgs(t *testing.T) {
+	args := GetImageEncodeArgs("in", "out.jpg", 6, 1600, ImageEncodeAttempt{Quality: 8, Scale: 0.75})
+	index := slices.Index(args, "-vf")
+	if index < 0 || !strings.HasPrefix(args[index+1], "transpose=1,scale=w='min(iw*0.75,1600)'") {
+		t.Errorf("unexpected filters: %v", args)
+	}
+
+	if args[0] != "-noautorotate" || !slices.Contains(args, "-map_metadata") {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	args = GetImageEncodeArgs("in", "out.jpg", 1, 0, ImageEncodeAttempt{Quality: 2, Scale: 1})
+	if slices.Contains(args, "-vf") {
+		t.Errorf("unexpected filters without changes: %v", args)
+	}
+}
+
+func TestProcessImageStripOnly(t *testing.T) {
+	data := testJpeg(t, 1)
+	settings := environment.ImageSettings{StripMetadata: true}
+
+	processed, report, err := ProcessImage(data, "image/jpeg", "photo.jpg", settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !report.Changed() || !report.StrippedMetadata || report.Converted || report.Size != uint64(len(processed)) {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if report.Width != 8 || report.Height != 4 || report.Mime != "image/jpeg" {
+		t.Errorf("unexpected dimensions or mime: %+v", report)
+	}
+
+	// untouched formats
+	gif := []byte("GIF89a\x01\x00\x01\x00")
+	processed, report, _ = ProcessImage(gif, "image/gif", "", settings)
+	if report.Changed() || !bytes.Equal(processed, gif) {
+		t.Errorf("unexpected changes on gif: %+v", report)
+	}
+}
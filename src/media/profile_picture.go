@@ -0,0 +1,94 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/nfnt/resize"
+	log "github.com/nocodeleaks/quepasa/qplog"
+)
+
+// ProfilePictureSize is the side, in pixels, of the square photo sent to WhatsApp
+const ProfilePictureSize = 640
+
+// ProfilePictureMaxBytes is the largest JPEG accepted for profile and group photos
+const ProfilePictureMaxBytes = 500000
+
+// PrepareProfilePicture turns any image into a square JPEG suitable for profile photos.
+// The largest centered square is kept, then scaled to ProfilePictureSize.
+// Formats not decoded natively (webp, heic, ...) go through FFmpeg first.
+func PrepareProfilePicture(imageData []byte) ([]byte, error) {
+	if len(imageData) == 0 {
+		return nil, fmt.Errorf("empty image data")
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		log.Debugf("failed to decode profile picture natively (format: %s): %v, trying FFmpeg", format, err)
+		converted, ffmpegErr := ConvertToJpeg(imageData)
+		if ffmpegErr != nil {
+			return nil, fmt.Errorf("unsupported image format: %w", ffmpegErr)
+		}
+
+		img, err = jpeg.Decode(bytes.NewReader(converted))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode converted image: %w", err)
+		}
+	}
+
+	square := CropToSquare(img)
+	scaled := resize.Resize(ProfilePictureSize, ProfilePictureSize, square, resize.Lanczos3)
+
+	var buf bytes.Buffer
+	for quality := 90; quality >= 40; quality -= 10 {
+		buf.Reset()
+		if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("failed to encode profile picture as JPEG: %w", err)
+		}
+
+		if buf.Len() <= ProfilePictureMaxBytes {
+			log.Debugf("profile picture prepared, original size: %d bytes, final size: %d bytes, quality: %d", len(imageData), buf.Len(), quality)
+			return buf.Bytes(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("profile picture still above %d bytes after compression", ProfilePictureMaxBytes)
+}
+
+// CropToSquare returns the largest centered square region of the image
+func CropToSquare(img image.Image) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == height {
+		return img
+	}
+
+	side := width
+	if height < side {
+		side = height
+	}
+
+	x0 := bounds.Min.X + (width-side)/2
+	y0 := bounds.Min.Y + (height-side)/2
+	rect := image.Rect(x0, y0, x0+side, y0+side)
+
+	type subImager interface {
+		SubImage(r image.Rectangle) image.Image
+	}
+	if sub, ok := img.(subImager); ok {
+		return sub.SubImage(rect)
+	}
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			square.Set(x, y, img.At(x0+x, y0+y))
+		}
+	}
+	return square
+}
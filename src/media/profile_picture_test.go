@@ -0,0 +1,49 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestCropToSquareKeepsCenter(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	img.Set(150, 50, color.RGBA{R: 255, A: 255})
+
+	square := CropToSquare(img)
+	bounds := square.Bounds()
+	if bounds.Dx() != 100 || bounds.Dy() != 100 {
+		t.Fatalf("expected 100x100 square, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+
+	r, _, _, _ := square.At(150, 50).RGBA()
+	if r == 0 {
+		t.Fatalf("expected center pixel to be kept")
+	}
+}
+
+func TestPrepareProfilePictureReturnsSquareJpeg(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	var source bytes.Buffer
+	if err := png.Encode(&source, img); err != nil {
+		t.Fatalf("failed to encode source image: %v", err)
+	}
+
+	result, err := PrepareProfilePicture(source.Bytes())
+	if err != nil {
+		t.Fatalf("PrepareProfilePicture returned error: %v", err)
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(result))
+	if err != nil {
+		t.Fatalf("result is not a jpeg: %v", err)
+	}
+
+	bounds := decoded.Bounds()
+	if bounds.Dx() != ProfilePictureSize || bounds.Dy() != ProfilePictureSize {
+		t.Fatalf("expected %dx%d, got %dx%d", ProfilePictureSize, ProfilePictureSize, bounds.Dx(), bounds.Dy())
+	}
+}
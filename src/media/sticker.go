@@ -0,0 +1,310 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WhatsApp sticker constraints, larger stickers are rejected or not rendered by the apps
+const (
+	WhatsappStickerDimension = 512
+
+	// size limits of the webp file, in bytes
+	WhatsappStickerMaxStaticSize   = 100 * 1024
+	WhatsappStickerMaxAnimatedSize = 500 * 1024
+
+	// longest animation played, in seconds
+	WhatsappStickerMaxSeconds = 10
+)
+
+// StickerAttempt is a set of encoding parameters tried when converting a sticker,
+// from the best looking to the smallest output
+type StickerAttempt struct {
+	Quality   int
+	FrameRate int
+}
+
+var stickerStaticAttempts = []StickerAttempt{{Quality: 80}, {Quality: 60}, {Quality: 40}, {Quality: 20}}
+
+var stickerAnimatedAttempts = []StickerAttempt{
+	{Quality: 75, FrameRate: 15},
+	{Quality: 50, FrameRate: 12},
+	{Quality: 35, FrameRate: 10},
+	{Quality: 20, FrameRate: 8},
+}
+
+// GetStickerAttempts returns the encoding parameters to try, in order, for static or animated stickers
+func GetStickerAttempts(animated bool) []StickerAttempt {
+	if animated {
+		return stickerAnimatedAttempts
+	}
+	return stickerStaticAttempts
+}
+
+// GetStickerConvertArgs returns the ffmpeg arguments to convert into a 512x512 webp sticker,
+// keeping aspect ratio with transparent padding
+func GetStickerConvertArgs(input string, output string, animated bool, attempt StickerAttempt) []string {
+	filter := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,format=rgba,pad=%d:%d:(ow-iw)/2:(oh-ih)/2:color=0x00000000",
+		WhatsappStickerDimension, WhatsappStickerDimension, WhatsappStickerDimension, WhatsappStickerDimension)
+
+	args := []string{"-i", input}
+	if animated {
+		args = append(args,
+			"-t", fmt.Sprint(WhatsappStickerMaxSeconds),
+			"-vf", fmt.Sprintf("fps=%d,%s", attempt.FrameRate, filter),
+			"-an",
+			"-loop", "0",
+		)
+	} else {
+		args = append(args,
+			"-vf", filter,
+			"-frames:v", "1",
+		)
+	}
+
+	return append(args,
+		"-q:v", fmt.Sprint(attempt.Quality),
+		"-compression_level", "6",
+		"-f", "webp",
+		"-y", output,
+	)
+}
+
+// IsAnimatedStickerSource reports whether the content should become an animated sticker,
+// by informed mime or, when it is missing or generic, by the content itself
+func IsAnimatedStickerSource(data []byte, mime string) bool {
+	mime = strings.ToLower(strings.TrimSpace(strings.Split(mime, ";")[0]))
+	if len(mime) == 0 || mime == "application/octet-stream" {
+		mime = http.DetectContentType(data)
+	}
+
+	return strings.HasPrefix(mime, "video/") ||
+		mime == "image/gif" ||
+		mime == "image/apng"
+}
+
+// IsAnimatedWebP reports whether the webp content has the animation flag set
+func IsAnimatedWebP(data []byte) bool {
+	chunks, err := readWebPChunks(data)
+	if err != nil {
+		return false
+	}
+
+	for _, chunk := range chunks {
+		if chunk.id == "VP8X" && len(chunk.data) > 0 {
+			return chunk.data[0]&webpFlagAnimation != 0
+		}
+	}
+	return false
+}
+
+// StickerMetadata is the sticker pack information shown by WhatsApp apps, stored as EXIF
+type StickerMetadata struct {
+	PackId    string   `json:"sticker-pack-id,omitempty"`
+	PackName  string   `json:"sticker-pack-name,omitempty"`
+	Publisher string   `json:"sticker-pack-publisher,omitempty"`
+	Emojis    []string `json:"emojis,omitempty"`
+}
+
+const (
+	webpFlagAnimation = 0x02
+	webpFlagEXIF      = 0x08
+	webpFlagAlpha     = 0x10
+)
+
+// little endian tiff header with a single undefined (type 7) entry, tag 0x5741, pointing
+// to the json at offset 22, layout read by WhatsApp apps; bytes 14 to 18 hold the json length
+var stickerExifHeader = []byte{
+	0x49, 0x49, 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x41, 0x57, 0x07, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x16, 0x00, 0x00, 0x00,
+}
+
+type webpChunk struct {
+	id   string
+	data []byte
+}
+
+// SetWebPStickerMetadata embeds the sticker pack metadata as EXIF on a webp file,
+// replacing any previous EXIF and extending simple files into the VP8X format
+func SetWebPStickerMetadata(data []byte, metadata StickerMetadata) ([]byte, error) {
+	chunks, err := readWebPChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	exif := make([]byte, len(stickerExifHeader), len(stickerExifHeader)+len(content))
+	copy(exif, stickerExifHeader)
+	binary.LittleEndian.PutUint32(exif[14:18], uint32(len(content)))
+	exif = append(exif, content...)
+
+	result := make([]webpChunk, 0, len(chunks)+2)
+	extended := false
+	for _, chunk := range chunks {
+		switch chunk.id {
+		case "EXIF":
+			continue
+		case "VP8X":
+			extended = true
+			if len(chunk.data) < 10 {
+				return nil, fmt.Errorf("invalid webp VP8X chunk")
+			}
+			header := append([]byte{}, chunk.data...)
+			header[0] |= webpFlagEXIF
+			chunk.data = header
+		case "XMP ":
+			// EXIF goes before XMP metadata
+			result = append(result, webpChunk{id: "EXIF", data: exif})
+			exif = nil
+		}
+		result = append(result, chunk)
+	}
+
+	if exif != nil {
+		result = append(result, webpChunk{id: "EXIF", data: exif})
+	}
+
+	if !extended {
+		header, err := getWebPExtendedHeader(chunks)
+		if err != nil {
+			return nil, err
+		}
+		result = append([]webpChunk{{id: "VP8X", data: header}}, result...)
+	}
+
+	return writeWebPChunks(result), nil
+}
+
+// GetWebPStickerMetadata reads the sticker pack metadata of a webp file, nil when not present
+func GetWebPStickerMetadata(data []byte) (*StickerMetadata, error) {
+	chunks, err := readWebPChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chunk := range chunks {
+		if chunk.id != "EXIF" {
+			continue
+		}
+
+		if len(chunk.data) < len(stickerExifHeader) {
+			return nil, fmt.Errorf("invalid sticker exif")
+		}
+
+		length := int(binary.LittleEndian.Uint32(chunk.data[14:18]))
+		content := chunk.data[len(stickerExifHeader):]
+		if length > len(content) {
+			return nil, fmt.Errorf("invalid sticker exif length: %d", length)
+		}
+
+		metadata := &StickerMetadata{}
+		if err := json.Unmarshal(content[:length], metadata); err != nil {
+			return nil, fmt.Errorf("invalid sticker exif content: %w", err)
+		}
+		return metadata, nil
+	}
+
+	return nil, nil
+}
+
+// getWebPExtendedHeader builds the VP8X chunk of a simple (lossy or lossless) webp file,
+// canvas size taken from the bitstream
+func getWebPExtendedHeader(chunks []webpChunk) ([]byte, error) {
+	var width, height uint32
+	var flags byte = webpFlagEXIF
+
+	found := false
+	for _, chunk := range chunks {
+		switch chunk.id {
+		case "VP8 ":
+			if len(chunk.data) < 10 || !bytes.Equal(chunk.data[3:6], []byte{0x9d, 0x01, 0x2a}) {
+				return nil, fmt.Errorf("invalid webp VP8 bitstream")
+			}
+			width = uint32(binary.LittleEndian.Uint16(chunk.data[6:8]) & 0x3fff)
+			height = uint32(binary.LittleEndian.Uint16(chunk.data[8:10]) & 0x3fff)
+			found = true
+		case "VP8L":
+			if len(chunk.data) < 5 || chunk.data[0] != 0x2f {
+				return nil, fmt.Errorf("invalid webp VP8L bitstream")
+			}
+			bits := binary.LittleEndian.Uint32(chunk.data[1:5])
+			width = bits&0x3fff + 1
+			height = (bits>>14)&0x3fff + 1
+			if (bits>>28)&1 == 1 {
+				flags |= webpFlagAlpha
+			}
+			found = true
+		}
+	}
+
+	if !found || width == 0 || height == 0 {
+		return nil, fmt.Errorf("webp image data not found")
+	}
+
+	header := make([]byte, 10)
+	header[0] = flags
+	putUint24(header[4:7], width-1)
+	putUint24(header[7:10], height-1)
+	return header, nil
+}
+
+func readWebPChunks(data []byte) ([]webpChunk, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("not a webp file")
+	}
+
+	chunks := []webpChunk{}
+	for offset := 12; offset < len(data); {
+		if offset+8 > len(data) {
+			return nil, fmt.Errorf("truncated webp chunk header at %d", offset)
+		}
+
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		start := offset + 8
+		if size < 0 || start+size > len(data) {
+			return nil, fmt.Errorf("truncated webp chunk %q at %d", id, offset)
+		}
+
+		chunks = append(chunks, webpChunk{id: id, data: data[start : start+size]})
+
+		// chunks are padded to even sizes
+		offset = start + size + size%2
+	}
+	return chunks, nil
+}
+
+func writeWebPChunks(chunks []webpChunk) []byte {
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+	for _, chunk := range chunks {
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(len(chunk.data)))
+		body.WriteString(chunk.id)
+		body.Write(size[:])
+		body.Write(chunk.data)
+		if len(chunk.data)%2 == 1 {
+			body.WriteByte(0)
+		}
+	}
+
+	result := make([]byte, 8, 8+body.Len())
+	copy(result, "RIFF")
+	binary.LittleEndian.PutUint32(result[4:8], uint32(body.Len()))
+	return append(result, body.Bytes()...)
+}
+
+func putUint24(buffer []byte, value uint32) {
+	buffer[0] = byte(value)
+	buffer[1] = byte(value >> 8)
+	buffer[2] = byte(value >> 16)
+}
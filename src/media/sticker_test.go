@@ -0,0 +1,120 @@
+package media
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+// minimal lossless webp header, bitstream is not decoded by the metadata functions
+func newTestLosslessWebP(width, height uint32, alpha bool) []byte {
+	bits := (width - 1) | (height-1)<<14
+	if alpha {
+		bits |= 1 << 28
+	}
+
+	bitstream := make([]byte, 5)
+	bitstream[0] = 0x2f
+	binary.LittleEndian.PutUint32(bitstream[1:], bits)
+	return writeWebPChunks([]webpChunk{{id: "VP8L", data: bitstream}})
+}
+
+func TestSetWebPStickerMetadata(t *testing.T) {
+	data := newTestLosslessWebP(512, 256, true)
+	metadata := StickerMetadata{PackId: "pack-1", PackName: "Quepasa", Publisher: "nocodeleaks", Emojis: []string{"😀"}}
+
+	result, err := SetWebPStickerMetadata(data, metadata)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	chunks, err := readWebPChunks(result)
+	if err != nil {
+		t.Fatalf("invalid webp result: %s", err)
+	}
+
+	ids := []string{}
+	for _, chunk := range chunks {
+		ids = append(ids, chunk.id)
+	}
+	if !slices.Equal(ids, []string{"VP8X", "VP8L", "EXIF"}) {
+		t.Fatalf("unexpected chunks: %v", ids)
+	}
+
+	header := chunks[0].data
+	if header[0] != webpFlagEXIF|webpFlagAlpha {
+		t.Errorf("unexpected VP8X flags: %#x", header[0])
+	}
+
+	width := uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16
+	height := uint32(header[7]) | uint32(header[8])<<8 | uint32(header[9])<<16
+	if width+1 != 512 || height+1 != 256 {
+		t.Errorf("unexpected canvas: %dx%d", width+1, height+1)
+	}
+
+	// replacing keeps a single EXIF chunk
+	metadata.PackName = "Other"
+	result, err = SetWebPStickerMetadata(result, metadata)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	chunks, _ = readWebPChunks(result)
+	if len(chunks) != 3 {
+		t.Errorf("unexpected chunk count: %d", len(chunks))
+	}
+
+	stored, err := GetWebPStickerMetadata(result)
+	if err != nil || stored == nil {
+		t.Fatalf("metadata not found: %v", err)
+	}
+
+	if stored.PackName != "Other" || stored.Publisher != "nocodeleaks" || stored.PackId != "pack-1" || !slices.Equal(stored.Emojis, metadata.Emojis) {
+		t.Errorf("unexpected metadata: %+v", stored)
+	}
+}
+
+func TestSetWebPStickerMetadataInvalid(t *testing.T) {
+	if _, err := SetWebPStickerMetadata([]byte("GIF89a"), StickerMetadata{}); err == nil {
+		t.Errorf("expected error for non webp content")
+	}
+}
+
+func TestIsAnimatedStickerSource(t *testing.T) {
+	gif := []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00")
+	cases := []struct {
+		mime     string
+		data     []byte
+		animated bool
+	}{
+		{"video/mp4", nil, true},
+		{"video/webm; codecs=vp9", nil, true},
+		{"image/gif", nil, true},
+		{"image/png", nil, false},
+		{"application/octet-stream", gif, true},
+		{"", []byte("\x89PNG\x0d\x0a\x1a\x0a"), false},
+	}
+
+	for _, c := range cases {
+		if got := IsAnimatedStickerSource(c.data, c.mime); got != c.animated {
+			t.Errorf("IsAnimatedStickerSource(%q) = %v, expected %v", c.mime, got, c.animated)
+		}
+	}
+}
+
+func TestGetStickerConvertArgs(t *testing.T) {
+	args := GetStickerConvertArgs("in", "out", true, StickerAttempt{Quality: 50, FrameRate: 12})
+	if !slices.Contains(args, "-an") || !slices.Contains(args, "10") || !slices.Contains(args, "50") {
+		t.Errorf("unexpected animated args: %v", args)
+	}
+
+	index := slices.Index(args, "-vf")
+	if index < 0 || args[index+1][:7] != "fps=12," {
+		t.Errorf("unexpected animated filter: %v", args)
+	}
+
+	args = GetStickerConvertArgs("in", "out", false, StickerAttempt{Quality: 80})
+	if !slices.Contains(args, "-frames:v") || slices.Contains(args, "-loop") {
+		t.Errorf("unexpected static args: %v", args)
+	}
+}
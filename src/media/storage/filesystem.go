@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FilesystemStorage keeps objects on a local directory, usually a shared volume
+// or a path exposed by a reverse proxy on the public url
+type FilesystemStorage struct {
+	Root      string
+	PublicUrl string
+
+	// key prefix of stored objects, cleanup never walks outside of it
+	Prefix string
+}
+
+// NewFilesystemStorage requires the public url, local paths are useless as links for integrations
+func NewFilesystemStorage(root string, publicUrl string, prefix string) (*FilesystemStorage, error) {
+	publicUrl = strings.TrimRight(strings.TrimSpace(publicUrl), "/")
+	if len(publicUrl) == 0 {
+		return nil, fmt.Errorf("filesystem storage requires a public url, serving the storage path")
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("storage path not writable: %s", err.Error())
+	}
+
+	return &FilesystemStorage{Root: root, PublicUrl: publicUrl, Prefix: strings.Trim(prefix, "/")}, nil
+}
+
+func (source *FilesystemStorage) getPath(key string) (string, error) {
+	filename := filepath.Join(source.Root, filepath.FromSlash(key))
+	if !strings.HasPrefix(filename, source.Root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+	return filename, nil
+}
+
+func (source *FilesystemStorage) Put(ctx context.Context, key string, content []byte, mimetype string) error {
+	filename, err := source.getPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	// writing aside and renaming, readers never see partial files,
+	// a unique temporary name keeps concurrent puts of the same key apart
+	temp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	// created private, objects are served on the public url
+	if err := os.Chmod(temp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), filename)
+}
+
+// GetUrl returns a stable link under the public url
+func (source *FilesystemStorage) GetUrl(key string) (string, *time.Time, error) {
+	if _, err := source.getPath(key); err != nil {
+		return "", nil, err
+	}
+	return source.PublicUrl + "/" + strings.TrimLeft(key, "/"), nil, nil
+}
+
+func (source *FilesystemStorage) Delete(ctx context.Context, key string) error {
+	filename, err := source.getPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Cleanup removes objects under the prefix only, the root may be shared with other files
+func (source *FilesystemStorage) Cleanup(ctx context.Context, before time.Time) (int, error) {
+	directory := source.Root
+	if len(source.Prefix) > 0 {
+		prefixed, err := source.getPath(source.Prefix)
+		if err != nil {
+			return 0, err
+		}
+		directory = prefixed
+	}
+
+	if _, err := os.Stat(directory); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	removed := 0
+	err := filepath.WalkDir(directory, func(filename string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		if info.ModTime().Before(before) {
+			if err := os.Remove(filename); err == nil {
+				removed++
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
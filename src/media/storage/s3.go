@@ -0,0 +1,290 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+
+	// maximum validity of a signature version 4 pre-signed url
+	s3MaxPresignSeconds = 604800
+)
+
+// S3Config describes a bucket on Amazon S3 or any compatible service, e.g. MinIO
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+
+	// path style addressing (endpoint/bucket/key), otherwise virtual hosted (bucket.endpoint/key)
+	PathStyle bool
+
+	// when set, objects are publicly served under this url and links do not expire
+	PublicUrl string
+
+	// validity of pre-signed links
+	PresignSeconds uint32
+}
+
+// S3Storage uploads objects with signature version 4 requests, no sdk needed
+type S3Storage struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+
+	// signing clock, replaced on tests
+	now func() time.Time
+}
+
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	if len(config.Bucket) == 0 {
+		return nil, fmt.Errorf("missing s3 bucket")
+	}
+
+	if len(config.AccessKey) == 0 || len(config.SecretKey) == 0 {
+		return nil, fmt.Errorf("missing s3 credentials")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || len(endpoint.Host) == 0 {
+		return nil, fmt.Errorf("invalid s3 endpoint: %s", config.Endpoint)
+	}
+
+	if len(config.Region) == 0 {
+		config.Region = "us-east-1"
+	}
+
+	if config.PresignSeconds == 0 || config.PresignSeconds > s3MaxPresignSeconds {
+		config.PresignSeconds = s3MaxPresignSeconds
+	}
+
+	config.PublicUrl = strings.TrimRight(config.PublicUrl, "/")
+	return &S3Storage{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		now:      time.Now,
+	}, nil
+}
+
+// getLocation returns host and escaped path of an object, empty key for the bucket itself
+func (source *S3Storage) getLocation(key string) (host string, path string) {
+	escaped := s3EscapePath(strings.TrimLeft(key, "/"))
+	if source.config.PathStyle {
+		return source.endpoint.Host, "/" + source.config.Bucket + "/" + escaped
+	}
+	return source.config.Bucket + "." + source.endpoint.Host, "/" + escaped
+}
+
+func (source *S3Storage) Put(ctx context.Context, key string, content []byte, mimetype string) error {
+	headers := http.Header{}
+	if len(mimetype) > 0 {
+		headers.Set("Content-Type", mimetype)
+	}
+	return source.do(ctx, http.MethodPut, key, nil, content, headers)
+}
+
+func (source *S3Storage) Delete(ctx context.Context, key string) error {
+	return source.do(ctx, http.MethodDelete, key, nil, nil, http.Header{})
+}
+
+// Cleanup is left to the bucket lifecycle rules, see ApplyRetention
+func (source *S3Storage) Cleanup(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+// ApplyRetention sets a bucket lifecycle rule expiring objects under the prefix after days.
+// Replaces any lifecycle configuration already set on the bucket.
+func (source *S3Storage) ApplyRetention(ctx context.Context, prefix string, days uint32) error {
+	body := fmt.Sprintf(`<LifecycleConfiguration><Rule><ID>quepasa-retention</ID><Filter><Prefix>%s</Prefix></Filter><Status>Enabled</Status><Expiration><Days>%d</Days></Expiration></Rule></LifecycleConfiguration>`,
+		strings.Trim(prefix, "/"), days)
+
+	sum := md5.Sum([]byte(body))
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/xml")
+	headers.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	return source.do(ctx, http.MethodPut, "", url.Values{"lifecycle": {""}}, []byte(body), headers)
+}
+
+// GetUrl returns a public link when configured, otherwise a pre-signed one
+func (source *S3Storage) GetUrl(key string) (string, *time.Time, error) {
+	if len(source.config.PublicUrl) > 0 {
+		return source.config.PublicUrl + "/" + s3EscapePath(strings.TrimLeft(key, "/")), nil, nil
+	}
+
+	now := source.now().UTC()
+	host, path := source.getLocation(key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", source.config.AccessKey+"/"+source.getScope(now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", fmt.Sprint(source.config.PresignSeconds))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	headers := http.Header{}
+	headers.Set("Host", host)
+
+	signature := source.sign(now, http.MethodGet, path, query, headers, s3UnsignedPayload)
+	query.Set("X-Amz-Signature", signature)
+
+	expires := now.Add(time.Duration(source.config.PresignSeconds) * time.Second)
+	return source.endpoint.Scheme + "://" + host + path + "?" + s3CanonicalQuery(query), &expires, nil
+}
+
+func (source *S3Storage) do(ctx context.Context, method string, key string, query url.Values, content []byte, headers http.Header) error {
+	now := source.now().UTC()
+	host, path := source.getLocation(key)
+
+	sum := sha256.Sum256(content)
+	payload := hex.EncodeToString(sum[:])
+
+	headers.Set("Host", host)
+	headers.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	headers.Set("X-Amz-Content-Sha256", payload)
+
+	signature := source.sign(now, method, path, query, headers, payload)
+	headers.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, source.config.AccessKey, source.getScope(now), s3SignedHeaders(headers), signature))
+
+	address := source.endpoint.Scheme + "://" + host + path
+	if len(query) > 0 {
+		address += "?" + s3CanonicalQuery(query)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, address, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	for name, values := range headers {
+		if strings.EqualFold(name, "Host") {
+			continue
+		}
+		request.Header[name] = values
+	}
+	request.Host = host
+	request.ContentLength = int64(len(content))
+
+	response, err := source.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("s3 %s %s failed, status: %d, response: %s", method, key, response.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (source *S3Storage) getScope(now time.Time) string {
+	return now.Format(s3DateFormat) + "/" + source.config.Region + "/s3/aws4_request"
+}
+
+// sign returns the signature version 4 of a request, headers must contain every signed header
+func (source *S3Storage) sign(now time.Time, method string, path string, query url.Values, headers http.Header, payload string) string {
+	names := strings.Split(s3SignedHeaders(headers), ";")
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + strings.TrimSpace(headers.Get(name)) + "\n"
+	}
+
+	canonical := strings.Join([]string{
+		method,
+		path,
+		s3CanonicalQuery(query),
+		canonicalHeaders,
+		strings.Join(names, ";"),
+		payload,
+	}, "\n")
+
+	hashed := sha256.Sum256([]byte(canonical))
+	toSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		source.getScope(now),
+		hex.EncodeToString(hashed[:]),
+	}, "\n")
+
+	key := s3Hmac([]byte("AWS4"+source.config.SecretKey), now.Format(s3DateFormat))
+	key = s3Hmac(key, source.config.Region)
+	key = s3Hmac(key, "s3")
+	key = s3Hmac(key, "aws4_request")
+	return hex.EncodeToString(s3Hmac(key, toSign))
+}
+
+func s3Hmac(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3SignedHeaders(headers http.Header) string {
+	names := []string{}
+	for name := range headers {
+		lower := strings.ToLower(name)
+		if lower == "host" || lower == "content-type" || lower == "content-md5" || strings.HasPrefix(lower, "x-amz-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ";")
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape encodes every byte except the unreserved characters, slashes kept on paths
+func s3Escape(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+)
+
+// ObjectStorage keeps attachment contents outside of the messages cache,
+// so integrations can fetch them after the cache expiration
+type ObjectStorage interface {
+	// Put writes the content under the key, replacing any previous object
+	Put(ctx context.Context, key string, content []byte, mimetype string) error
+
+	// GetUrl returns the link of a stored object, expires is nil for stable links
+	GetUrl(key string) (url string, expires *time.Time, err error)
+
+	// Delete removes a stored object, missing objects are not an error
+	Delete(ctx context.Context, key string) error
+
+	// Cleanup removes objects stored before the given time, returns how many were removed.
+	// Backends with native lifecycle rules may return zero and leave it to the provider.
+	Cleanup(ctx context.Context, before time.Time) (int, error)
+}
+
+// StoredObject describes an attachment uploaded to the object storage
+type StoredObject struct {
+	Key      string     `json:"key"`
+	Url      string     `json:"url"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Checksum string     `json:"checksum"`
+	Size     uint64     `json:"size"`
+}
+
+// New creates the object storage of the given settings, nil when disabled
+func New(settings environment.StorageSettings) (ObjectStorage, error) {
+	switch strings.ToLower(settings.Backend) {
+	case "":
+		return nil, nil
+	case "filesystem", "disk":
+		return NewFilesystemStorage(settings.Path, settings.PublicUrl, settings.Prefix)
+	case "s3", "minio":
+		return NewS3Storage(S3Config{
+			Endpoint:       settings.S3Endpoint,
+			Region:         settings.S3Region,
+			Bucket:         settings.S3Bucket,
+			AccessKey:      settings.S3AccessKey,
+			SecretKey:      settings.S3SecretKey,
+			PathStyle:      settings.S3PathStyle,
+			PublicUrl:      settings.PublicUrl,
+			PresignSeconds: settings.PresignSeconds,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s, expected filesystem or s3", settings.Backend)
+	}
+}
+
+// GetChecksum returns the sha256 hex digest of a content, also used as object name
+func GetChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetKey builds a content addressed key, grouped by day: prefix/yyyy/mm/dd/checksum.ext
+func GetKey(prefix string, checksum string, mimetype string, filename string, timestamp time.Time) string {
+	extension := strings.ToLower(filepath.Ext(filename))
+	if len(extension) == 0 {
+		mimeOnly := strings.Split(mimetype, ";")[0]
+		if extensions, err := mime.ExtensionsByType(mimeOnly); err == nil && len(extensions) > 0 {
+			extension = extensions[0]
+		}
+	}
+
+	return path.Join(strings.Trim(prefix, "/"), timestamp.UTC().Format("2006/01/02"), checksum+extension)
+}
+
+// Store uploads a content and returns its link, checksum and size
+func Store(ctx context.Context, storage ObjectStorage, prefix string, content []byte, mimetype string, filename string, timestamp time.Time) (*StoredObject, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("object storage not enabled")
+	}
+
+	checksum := GetChecksum(content)
+	key := GetKey(prefix, checksum, mimetype, filename, timestamp)
+	if err := storage.Put(ctx, key, content, mimetype); err != nil {
+		return nil, err
+	}
+
+	url, expires, err := storage.GetUrl(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StoredObject{
+		Key:      key,
+		Url:      url,
+		Expires:  expires,
+		Checksum: checksum,
+		Size:     uint64(len(content)),
+	}, nil
+}
@@ -310,6 +310,14 @@ func GenerateThumbnailByMime(data []byte, mimeType string) ([]byte, error) {
 		return GeneratePDFThumbnail(data, config)
 	}
 
+	if IsOfficeDocument(mimeType, "") {
+		preview, err := GeneratePreview(data, mimeType, "", config)
+		if err != nil {
+			return nil, err
+		}
+		return preview.Thumbnail, nil
+	}
+
 	return nil, fmt.Errorf("unsupported MIME type for thumbnail generation: %s", mimeType)
 }
 
@@ -329,5 +337,9 @@ func CanGenerateThumbnail(mimeType string) bool {
 		return IsPopplerAvailable()
 	}
 
+	if IsOfficeDocument(mimeType, "") {
+		return IsLibreOfficeAvailable() && IsPopplerAvailable()
+	}
+
 	return false
 }
@@ -0,0 +1,72 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandTranscriber runs a local speech to text command, such as whisper.cpp,
+// with the audio written to a temporary file; the transcript is read from stdout.
+//
+// Arguments are split on spaces, {file} and {language} placeholders are replaced,
+// e.g. "whisper-cli -m /models/ggml-base.bin -l {language} -nt -np -f {file}".
+// Inputs not supported by the command should be converted by a wrapper script.
+type CommandTranscriber struct {
+	args []string
+}
+
+func NewCommandTranscriber(command string) (*CommandTranscriber, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing transcription command")
+	}
+
+	return &CommandTranscriber{args: args}, nil
+}
+
+// GetArgs returns the command arguments with placeholders replaced
+func (source *CommandTranscriber) GetArgs(file string, language string) []string {
+	language = NormalizeLanguage(language)
+	if len(language) == 0 {
+		language = "auto"
+	}
+
+	args := make([]string, len(source.args))
+	for i, arg := range source.args {
+		arg = strings.ReplaceAll(arg, "{file}", file)
+		args[i] = strings.ReplaceAll(arg, "{language}", language)
+	}
+	return args
+}
+
+func (source *CommandTranscriber) Transcribe(ctx context.Context, content []byte, mimetype string, options Options) (string, error) {
+	file, err := os.CreateTemp("", "transcription-*"+getAudioExtension(mimetype))
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return "", fmt.Errorf("error writing temporary file: %w", err)
+	}
+	file.Close()
+
+	args := source.GetArgs(file.Name(), options.Language)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("error executing transcription command: %w\nstderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,116 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OpenAITranscriber posts audios to an OpenAI compatible /audio/transcriptions endpoint,
+// e.g. OpenAI, Groq, LocalAI or faster-whisper-server
+type OpenAITranscriber struct {
+	endpoint string
+	apikey   string
+	model    string
+	client   *http.Client
+}
+
+func NewOpenAITranscriber(baseUrl string, apikey string, model string, timeout time.Duration) (*OpenAITranscriber, error) {
+	endpoint, err := url.Parse(strings.TrimRight(baseUrl, "/") + "/audio/transcriptions")
+	if err != nil || len(endpoint.Host) == 0 {
+		return nil, fmt.Errorf("invalid transcription url: %s", baseUrl)
+	}
+
+	if len(model) == 0 {
+		return nil, fmt.Errorf("missing transcription model")
+	}
+
+	return &OpenAITranscriber{
+		endpoint: endpoint.String(),
+		apikey:   apikey,
+		model:    model,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (source *OpenAITranscriber) Transcribe(ctx context.Context, content []byte, mimetype string, options Options) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", "audio"+getAudioExtension(mimetype))
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+
+	_ = writer.WriteField("model", source.model)
+	_ = writer.WriteField("response_format", "json")
+	if language := NormalizeLanguage(options.Language); len(language) > 0 {
+		_ = writer.WriteField("language", language)
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, source.endpoint, &body)
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	if len(source.apikey) > 0 {
+		request.Header.Set("Authorization", "Bearer "+source.apikey)
+	}
+
+	response, err := source.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return "", fmt.Errorf("transcription failed, status: %d, response: %s", response.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	result := struct {
+		Text string `json:"text"`
+	}{}
+
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid transcription response: %w", err)
+	}
+
+	return strings.TrimSpace(result.Text), nil
+}
+
+// getAudioExtension returns the file extension used by providers to detect the audio format,
+// whatsapp voice notes are "audio/ogg; codecs=opus"
+func getAudioExtension(mimetype string) string {
+	mimeOnly := strings.TrimSpace(strings.Split(mimetype, ";")[0])
+	switch mimeOnly {
+	case "audio/ogg", "audio/opus":
+		return ".ogg"
+	case "audio/mpeg", "audio/mp3":
+		return ".mp3"
+	case "audio/mp4", "audio/aac", "audio/x-m4a":
+		return ".m4a"
+	}
+
+	if extensions, err := mime.ExtensionsByType(mimeOnly); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ".ogg"
+}
@@ -0,0 +1,43 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+)
+
+// Transcriber converts speech of an audio content into text
+type Transcriber interface {
+	// Transcribe returns the text spoken on the audio, empty when nothing was recognized
+	Transcribe(ctx context.Context, content []byte, mimetype string, options Options) (string, error)
+}
+
+// Options are hints of a single transcription
+type Options struct {
+	// ISO 639-1 language of the audio, empty lets the provider detect it
+	Language string
+}
+
+// New creates the transcriber of the given settings, nil when disabled
+func New(settings environment.TranscriptionSettings) (Transcriber, error) {
+	timeout := time.Duration(settings.Timeout) * time.Second
+
+	switch strings.ToLower(settings.Provider) {
+	case "":
+		return nil, nil
+	case "openai", "whisper-api":
+		return NewOpenAITranscriber(settings.Url, settings.ApiKey, settings.Model, timeout)
+	case "command", "local":
+		return NewCommandTranscriber(settings.Command)
+	default:
+		return nil, fmt.Errorf("unknown transcription provider: %s, expected openai or command", settings.Provider)
+	}
+}
+
+// NormalizeLanguage returns the language hint in the lower case form expected by providers
+func NormalizeLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
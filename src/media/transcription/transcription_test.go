@@ -0,0 +1,71 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOpenAITranscriber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		file.Close()
+
+		if header.Filename != "audio.ogg" || r.FormValue("model") != "whisper-1" || r.FormValue("language") != "pt" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"text": " olá mundo \n"})
+	}))
+	defer server.Close()
+
+	transcriber, err := NewOpenAITranscriber(server.URL+"/v1/", "secret", "whisper-1", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	text, err := transcriber.Transcribe(context.Background(), []byte("OggS"), "audio/ogg; codecs=opus", Options{Language: " PT "})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if text != "olá mundo" {
+		t.Errorf("unexpected transcript: %q", text)
+	}
+
+	transcriber.apikey = "wrong"
+	if _, err := transcriber.Transcribe(context.Background(), []byte("OggS"), "audio/ogg", Options{}); err == nil {
+		t.Errorf("expected error for unauthorized request")
+	}
+}
+
+func TestCommandTranscriberGetArgs(t *testing.T) {
+	transcriber, err := NewCommandTranscriber("whisper-cli -m model.bin -l {language} -f {file}")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	args := transcriber.GetArgs("/tmp/audio.ogg", "")
+	expected := []string{"whisper-cli", "-m", "model.bin", "-l", "auto", "-f", "/tmp/audio.ogg"}
+	if !slices.Equal(args, expected) {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if _, err := NewCommandTranscriber("  "); err == nil {
+		t.Errorf("expected error for empty command")
+	}
+}
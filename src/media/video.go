@@ -0,0 +1,256 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	log "github.com/nocodeleaks/quepasa/qplog"
+)
+
+// WhatsApp plays inline only H.264 (yuv420p) video with AAC audio on a MP4 container
+const (
+	WhatsappVideoCodec       = "h264"
+	WhatsappVideoAudioCodec  = "aac"
+	WhatsappVideoPixelFormat = "yuv420p"
+	WhatsappVideoMime        = "video/mp4"
+
+	// longest side of transcoded videos, keeps size and decoding cost acceptable on phones
+	WhatsappVideoMaxDimension = 1280
+
+	// audio bitrate of transcoded videos, in bits per second
+	WhatsappVideoAudioBitrate = 128000
+)
+
+// VideoInfo holds the container and codec information of a video, from ffprobe
+type VideoInfo struct {
+	FormatName  string
+	MajorBrand  string
+	VideoCodec  string
+	AudioCodec  string
+	PixelFormat string
+	Width       int
+	Height      int
+	Duration    time.Duration
+}
+
+// IsMP4 reports whether the container is mp4, ffprobe names quicktime and mp4 the same
+// way, the major brand sets them apart
+func (info *VideoInfo) IsMP4() bool {
+	if !strings.Contains(info.FormatName, "mp4") {
+		return false
+	}
+	return strings.TrimSpace(info.MajorBrand) != "qt"
+}
+
+// IsWhatsappCompatible reports whether the video can be sent as is
+func (info *VideoInfo) IsWhatsappCompatible() bool {
+	if !info.IsMP4() || info.VideoCodec != WhatsappVideoCodec {
+		return false
+	}
+
+	if len(info.PixelFormat) > 0 && info.PixelFormat != WhatsappVideoPixelFormat {
+		return false
+	}
+
+	return len(info.AudioCodec) == 0 || info.AudioCodec == WhatsappVideoAudioCodec
+}
+
+// GetVideoInfoFromBytes retrieves video information from a byte slice, using ffprobe and a temporary file
+func GetVideoInfoFromBytes(data []byte) (*VideoInfo, error) {
+	if !IsFFProbeAvailable() {
+		return nil, fmt.Errorf("ffprobe is not available: %w", GetInitError())
+	}
+
+	tmpfile, err := writeTempFile("video-probe-*.tmp", data)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpfile)
+
+	ctx, cancel := GetFFmpegContext()
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration,format_name:format_tags=major_brand:stream=codec_type,codec_name,width,height,pix_fmt",
+		"-of", "json",
+		tmpfile,
+	)
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	log.Debugf("Executing ffprobe video command: %s", cmd.String())
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffprobe timed out: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("error executing ffprobe: %w\nstderr: %s", err, stderr.String())
+	}
+
+	var result FFProbeResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling ffprobe JSON output: %w\nOutput: %s", err, out.String())
+	}
+
+	return GetVideoInfoFromProbe(&result)
+}
+
+// GetVideoInfoFromProbe extracts the video information of a ffprobe result
+func GetVideoInfoFromProbe(result *FFProbeResult) (*VideoInfo, error) {
+	info := &VideoInfo{
+		FormatName: result.Format.FormatName,
+		MajorBrand: result.Format.Tags["major_brand"],
+	}
+
+	if seconds, err := strconv.ParseFloat(result.Format.Duration, 64); err == nil {
+		info.Duration = time.Duration(seconds * float64(time.Second))
+	}
+
+	for _, stream := range result.Streams {
+		switch stream.CodecType {
+		case "video":
+			if len(info.VideoCodec) == 0 {
+				info.VideoCodec = stream.CodecName
+				info.PixelFormat = stream.PixFmt
+				info.Width = stream.Width
+				info.Height = stream.Height
+			}
+		case "audio":
+			if len(info.AudioCodec) == 0 {
+				info.AudioCodec = stream.CodecName
+			}
+		}
+	}
+
+	if len(info.VideoCodec) == 0 {
+		return nil, fmt.Errorf("no video stream found in the file")
+	}
+
+	return info, nil
+}
+
+// GetVideoTranscodeArgs returns the ffmpeg arguments to transcode into a WhatsApp compatible MP4.
+// With a known duration and size limit, the video bitrate is capped to fit the limit,
+// otherwise a constant quality is used.
+func GetVideoTranscodeArgs(input string, output string, duration time.Duration, maxBytes uint64) []string {
+	args := []string{
+		"-i", input,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+		"-pix_fmt", WhatsappVideoPixelFormat,
+		// fits the longest side, keeping aspect and even dimensions required by yuv420p
+		"-vf", fmt.Sprintf("scale='if(gt(iw,ih),min(%d,iw),-2)':'if(gt(iw,ih),-2,min(%d,ih))'", WhatsappVideoMaxDimension, WhatsappVideoMaxDimension),
+	}
+
+	if seconds := duration.Seconds(); seconds > 0 && maxBytes > 0 {
+		// 5% reserved for container overhead
+		total := float64(maxBytes) * 8 * 0.95 / seconds
+		video := int64(total) - WhatsappVideoAudioBitrate
+		if video < 100000 {
+			video = 100000
+		}
+		args = append(args, "-b:v", fmt.Sprint(video), "-maxrate", fmt.Sprint(video), "-bufsize", fmt.Sprint(video*2))
+	} else {
+		args = append(args, "-crf", "28")
+	}
+
+	return append(args,
+		"-c:a", "aac", "-b:a", fmt.Sprint(WhatsappVideoAudioBitrate), "-ac", "2",
+		"-movflags", "+faststart",
+		"-f", "mp4",
+		"-y", output,
+	)
+}
+
+// TranscodeToWhatsappVideo converts a video into H.264/AAC MP4, within the size limit when informed
+func TranscodeToWhatsappVideo(data []byte, duration time.Duration, maxBytes uint64) ([]byte, error) {
+	if !IsFFMpegAvailable() {
+		return nil, fmt.Errorf("ffmpeg is not available for video transcoding: %w", GetInitError())
+	}
+
+	input, err := writeTempFile("video-input-*.tmp", data)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(input)
+
+	outputFile, err := os.CreateTemp("", "video-output-*.mp4")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary output file: %w", err)
+	}
+	output := outputFile.Name()
+	outputFile.Close()
+	defer os.Remove(output)
+
+	ctx, cancel := GetFFmpegContext()
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", GetVideoTranscodeArgs(input, output, duration, maxBytes)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	log.Infof("Executing ffmpeg video transcoding: %s", cmd.String())
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffmpeg video transcoding timed out: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("error transcoding video with ffmpeg: %w\nstderr: %s", err, stderr.String())
+	}
+
+	converted, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("error reading transcoded video: %w", err)
+	}
+
+	if len(converted) == 0 {
+		return nil, fmt.Errorf("transcoded video is empty")
+	}
+
+	if maxBytes > 0 && uint64(len(converted)) > maxBytes {
+		return nil, fmt.Errorf("transcoded video exceeds size limit: %d > %d bytes", len(converted), maxBytes)
+	}
+
+	log.Infof("Video successfully transcoded to MP4: %d bytes input -> %d bytes output", len(data), len(converted))
+	return converted, nil
+}
+
+// GetFFmpegContext bounds a ffmpeg, ffprobe or pdfinfo run by FFMPEG_TIMEOUT, killing stuck processes
+func GetFFmpegContext() (context.Context, context.CancelFunc) {
+	timeout := environment.Settings.General.FFmpegTimeout
+	if timeout == 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+}
+
+func writeTempFile(pattern string, data []byte) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary file: %w", err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", fmt.Errorf("error writing data to temporary file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("error closing temporary file: %w", err)
+	}
+
+	return file.Name(), nil
+}
@@ -0,0 +1,79 @@
+package media
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func probeFromJSON(t *testing.T, data string) *FFProbeResult {
+	var result FFProbeResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("invalid probe json: %v", err)
+	}
+	return &result
+}
+
+func TestGetVideoInfoFromProbeCompatibility(t *testing.T) {
+	cases := []struct {
+		name       string
+		probe      string
+		compatible bool
+	}{
+		{
+			name:       "h264 aac mp4",
+			probe:      `{"format":{"duration":"12.5","format_name":"mov,mp4,m4a,3gp,3g2,mj2","tags":{"major_brand":"isom"}},"streams":[{"codec_type":"video","codec_name":"h264","pix_fmt":"yuv420p","width":1280,"height":720},{"codec_type":"audio","codec_name":"aac"}]}`,
+			compatible: true,
+		},
+		{
+			name:       "quicktime container",
+			probe:      `{"format":{"duration":"3","format_name":"mov,mp4,m4a,3gp,3g2,mj2","tags":{"major_brand":"qt  "}},"streams":[{"codec_type":"video","codec_name":"h264","pix_fmt":"yuv420p"}]}`,
+			compatible: false,
+		},
+		{
+			name:       "hevc",
+			probe:      `{"format":{"duration":"3","format_name":"mov,mp4,m4a,3gp,3g2,mj2","tags":{"major_brand":"mp42"}},"streams":[{"codec_type":"video","codec_name":"hevc","pix_fmt":"yuv420p"}]}`,
+			compatible: false,
+		},
+		{
+			name:       "avi",
+			probe:      `{"format":{"duration":"3","format_name":"avi"},"streams":[{"codec_type":"video","codec_name":"mpeg4"},{"codec_type":"audio","codec_name":"mp3"}]}`,
+			compatible: false,
+		},
+	}
+
+	for _, item := range cases {
+		info, err := GetVideoInfoFromProbe(probeFromJSON(t, item.probe))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", item.name, err)
+		}
+
+		if info.IsWhatsappCompatible() != item.compatible {
+			t.Errorf("%s: expected compatible %v, info: %+v", item.name, item.compatible, info)
+		}
+	}
+
+	if _, err := GetVideoInfoFromProbe(probeFromJSON(t, `{"format":{"format_name":"mp3"},"streams":[{"codec_type":"audio","codec_name":"mp3"}]}`)); err == nil {
+		t.Errorf("expected error for audio only file")
+	}
+}
+
+func TestGetVideoTranscodeArgsFitsSizeLimit(t *testing.T) {
+	args := GetVideoTranscodeArgs("in", "out", 80*time.Second, 16*1024*1024)
+
+	index := slices.Index(args, "-b:v")
+	if index < 0 {
+		t.Fatalf("expected video bitrate cap, args: %v", args)
+	}
+
+	// 16 MiB * 8 * 0.95 / 80s - 128k audio
+	if args[index+1] != "1465835" {
+		t.Errorf("unexpected video bitrate: %s", args[index+1])
+	}
+
+	args = GetVideoTranscodeArgs("in", "out", 0, 16*1024*1024)
+	if !slices.Contains(args, "-crf") || slices.Contains(args, "-b:v") {
+		t.Errorf("expected constant quality without duration, args: %v", args)
+	}
+}
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS "messages" (
+	"server_token" CHAR (100) NOT NULL,
+	"id" VARCHAR (255) NOT NULL,
+	"chat_id" VARCHAR (255) NOT NULL,
+	"participant_id" VARCHAR (255) NOT NULL DEFAULT '',
+	"participant_title" VARCHAR (255) NOT NULL DEFAULT '',
+	"type" VARCHAR (50) NOT NULL DEFAULT '',
+	"text" TEXT NOT NULL DEFAULT '',
+	"fromme" BOOLEAN NOT NULL DEFAULT FALSE,
+	"fromhistory" BOOLEAN NOT NULL DEFAULT FALSE,
+	"inreply" VARCHAR (255) NOT NULL DEFAULT '',
+	"status" VARCHAR (50) NOT NULL DEFAULT '',
+	"edited" BOOLEAN NOT NULL DEFAULT FALSE,
+	"revoked" BOOLEAN NOT NULL DEFAULT FALSE,
+	"sent_at" BIGINT NOT NULL DEFAULT 0,
+	"timestamp" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT "messages_pkey" PRIMARY KEY ("server_token", "id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_messages_server_chat_sent" ON "messages" ("server_token", "chat_id", "sent_at", "id");
+
+CREATE TABLE IF NOT EXISTS "message_attachments" (
+	"server_token" CHAR (100) NOT NULL,
+	"message_id" VARCHAR (255) NOT NULL,
+	"mime" VARCHAR (255) NOT NULL DEFAULT '',
+	"filename" VARCHAR (255) NOT NULL DEFAULT '',
+	"filelength" BIGINT NOT NULL DEFAULT 0,
+	"seconds" INTEGER NOT NULL DEFAULT 0,
+	"checksum" VARCHAR (255) NOT NULL DEFAULT '',
+	"url" TEXT NOT NULL DEFAULT '',
+	CONSTRAINT "message_attachments_pkey" PRIMARY KEY ("server_token", "message_id")
+);
+
+CREATE TABLE IF NOT EXISTS "message_reactions" (
+	"server_token" CHAR (100) NOT NULL,
+	"message_id" VARCHAR (255) NOT NULL,
+	"participant_id" VARCHAR (255) NOT NULL,
+	"reaction" VARCHAR (50) NOT NULL DEFAULT '',
+	"sent_at" BIGINT NOT NULL DEFAULT 0,
+	CONSTRAINT "message_reactions_pkey" PRIMARY KEY ("server_token", "message_id", "participant_id")
+);
+
+CREATE TABLE IF NOT EXISTS "message_edits" (
+	"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+	"server_token" CHAR (100) NOT NULL,
+	"message_id" VARCHAR (255) NOT NULL,
+	"text" TEXT NOT NULL DEFAULT '',
+	"sent_at" BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS "idx_message_edits_server_message" ON "message_edits" ("server_token", "message_id");
@@ -0,0 +1,14 @@
+ALTER TABLE "messages" ADD COLUMN "chat_title" VARCHAR (255) NOT NULL DEFAULT '';
+
+CREATE VIRTUAL TABLE IF NOT EXISTS "messages_fts" USING fts4 (
+	"text",
+	"filename",
+	"contact",
+	tokenize=unicode61 "remove_diacritics=1"
+);
+
+INSERT INTO "messages_fts" ("docid", "text", "filename", "contact")
+SELECT m."rowid", m."text", COALESCE(a."filename", ''), TRIM(m."participant_title" || ' ' || m."chat_title")
+FROM "messages" m
+LEFT JOIN "message_attachments" a ON a."server_token" = m."server_token" AND a."message_id" = m."id"
+WHERE m."revoked" = FALSE;
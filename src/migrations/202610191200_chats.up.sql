@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS "chats" (
+	"server_token" CHAR (100) NOT NULL,
+	"chat_id" VARCHAR (255) NOT NULL,
+	"title" VARCHAR (255) NOT NULL DEFAULT '',
+	"last_message_id" VARCHAR (255) NOT NULL DEFAULT '',
+	"last_message_type" VARCHAR (50) NOT NULL DEFAULT '',
+	"last_message_text" TEXT NOT NULL DEFAULT '',
+	"last_message_fromme" BOOLEAN NOT NULL DEFAULT FALSE,
+	"last_message_at" BIGINT NOT NULL DEFAULT 0,
+	"unread" INTEGER NOT NULL DEFAULT 0,
+	"markedunread" BOOLEAN NOT NULL DEFAULT FALSE,
+	"archived" BOOLEAN NOT NULL DEFAULT FALSE,
+	"pinned" BOOLEAN NOT NULL DEFAULT FALSE,
+	"muted_until" BIGINT NOT NULL DEFAULT 0,
+	"ephemeral" INTEGER NOT NULL DEFAULT 0,
+	"timestamp" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT "chats_pkey" PRIMARY KEY ("server_token", "chat_id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_chats_server_last_message" ON "chats" ("server_token", "last_message_at");
+
+INSERT OR IGNORE INTO "chats" ("server_token", "chat_id", "title", "last_message_id", "last_message_type", "last_message_text", "last_message_fromme", "last_message_at")
+SELECT m."server_token", m."chat_id", m."chat_title", m."id", m."type", m."text", m."fromme", m."sent_at"
+FROM "messages" m
+WHERE m."sent_at" = (SELECT MAX(l."sent_at") FROM "messages" l WHERE l."server_token" = m."server_token" AND l."chat_id" = m."chat_id");
@@ -0,0 +1,3 @@
+ALTER TABLE "message_attachments" ADD COLUMN "storage_key" VARCHAR (255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS "idx_message_attachments_storage_key" ON "message_attachments" ("storage_key");
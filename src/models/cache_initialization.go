@@ -2,8 +2,12 @@ package models
 
 import (
 	"log"
+	"time"
 
 	cacheservice "github.com/nocodeleaks/quepasa/cache/service"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
 )
 
 // InitializeCacheService initializes the global cache service and injects backends
@@ -25,9 +29,45 @@ func InitializeCacheService() error {
 	log.Println("Injecting queue backend into RabbitMQ client...")
 	InjectRabbitMQQueueBackend()
 
+	InjectUploadCacheBackend()
+	InjectLinkPreviewCacheBackend()
+
 	return nil
 }
 
+// InjectUploadCacheBackend enables the reuse of WhatsApp media uploads with identical content,
+// stored on the configured cache backend for CACHE_UPLOAD_TTL seconds.
+func InjectUploadCacheBackend() {
+	ttl := environment.Settings.Cache.UploadTTL
+	if ttl == 0 {
+		log.Println("Upload cache: disabled")
+		return
+	}
+
+	backend, err := cacheservice.GetInstance().GetKeyValueBackend("uploads")
+	if err != nil {
+		log.Printf("WARNING: Upload cache backend initialization failed, uploads will not be reused: %v", err)
+		return
+	}
+
+	whatsmeow.SetUploadCache(backend, time.Duration(ttl)*time.Second)
+	log.Printf("Upload cache: initialized, ttl: %ds", ttl)
+}
+
+// InjectLinkPreviewCacheBackend keeps link previews on the configured cache backend, fetched previews
+// for CACHE_LINKPREVIEW_TTL seconds and curated ones until deleted.
+func InjectLinkPreviewCacheBackend() {
+	backend, err := cacheservice.GetInstance().GetKeyValueBackend("linkpreviews")
+	if err != nil {
+		log.Printf("WARNING: Link preview cache backend initialization failed, previews will not be cached or curated: %v", err)
+		return
+	}
+
+	ttl := environment.Settings.Cache.LinkPreviewTTL
+	library.SetLinkPreviewCache(backend, time.Duration(ttl)*time.Second)
+	log.Printf("Link preview cache: initialized, ttl: %ds", ttl)
+}
+
 // InjectCacheBackendIntoHandler injects the cache backend into a DispatchingHandler
 // and sets a per-server key prefix to isolate messages across servers sharing the same backend.
 func InjectCacheBackendIntoHandler(handler *DispatchingHandler) {
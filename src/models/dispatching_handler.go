@@ -143,6 +143,23 @@ func (source *DispatchingHandler) HandleBroadcasts() bool {
 	return global.HandleBroadcasts(local)
 }
 
+// HandleStatuses returns true when contacts statuses (stories) should be
+// dispatched, regardless of broadcasts being handled
+func (source *DispatchingHandler) HandleStatuses() bool {
+	if source.server == nil {
+		return false
+	}
+	return source.server.GetStatuses()
+}
+
+// HandlePresences returns true when presence updates should reach webhooks
+func (source *DispatchingHandler) HandlePresences() bool {
+	if source.server == nil {
+		return false
+	}
+	return source.server.GetPresences()
+}
+
 func (source *DispatchingHandler) HandleDirect() bool {
 	global := whatsapp.Options
 
@@ -163,9 +180,11 @@ func (source *DispatchingHandler) Message(msg *whatsapp.WhatsappMessage, from st
 		return
 	}
 
-	// should skip broadcast ?
+	// should skip broadcast ? statuses have their own opt-in
 	if !source.HandleBroadcasts() && msg.FromBroadcast() {
-		return
+		if !msg.FromStatus() || !source.HandleStatuses() {
+			return
+		}
 	}
 
 	// should skip direct (individual) messages ?
@@ -222,6 +241,20 @@ func (source *DispatchingHandler) Receipt(msg *whatsapp.WhatsappMessage) {
 	source.Trigger(msg)
 }
 
+// does not cache msg, realtime subscribers always receive it, webhooks only when opted in
+func (source *DispatchingHandler) Presence(msg *whatsapp.WhatsappMessage) {
+	if msg == nil {
+		return
+	}
+
+	if source.HandlePresences() {
+		source.Trigger(msg)
+		return
+	}
+
+	source.GetMessageDispatcher().PublishRealtime(msg)
+}
+
 //endregion
 
 //#endregion
@@ -235,6 +268,22 @@ func (source *DispatchingHandler) appendMsgToCache(msg *whatsapp.WhatsappMessage
 		msg.Wid = source.server.GetWId()
 	}
 
+	// content downloaded by the steps below is shared by them and released before caching
+	downloaded := msg != nil && msg.Attachment != nil && !msg.Attachment.HasContent()
+
+	// antivirus verdict first, blocked attachments are neither stored nor transcribed
+	source.scanAttachment(msg)
+
+	// uploading attachments before caching, so every backend keeps the storage link
+	source.offloadAttachment(msg)
+
+	// speech to text of inbound audios, also before caching
+	source.transcribeAttachment(msg)
+
+	if downloaded {
+		msg.Attachment.SetContent(nil)
+	}
+
 	// saving on local normalized cache, do not affect remote msgs
 	valid := source.QpWhatsappMessages.Append(msg, from)
 
@@ -245,6 +294,9 @@ func (source *DispatchingHandler) appendMsgToCache(msg *whatsapp.WhatsappMessage
 		length := ENV.CacheLength()
 		source.QpWhatsappMessages.CleanUp(length)
 
+		// durable history, beyond cache expiration
+		source.storeMessage(msg)
+
 		source.Trigger(msg)
 	}
 }
@@ -72,25 +72,30 @@ func (md *MessageDispatcher) Trigger(payload *whatsapp.WhatsappMessage) {
 			}
 			message.Wid = md.dispatcher.GetWId()
 		},
-		PublishRealtime: func(message *whatsapp.WhatsappMessage) {
-			if md.dispatcher.Server() == nil {
-				return
-			}
-			enriched := CloneAndEnrichMessageForServer(md.dispatcher.Server(), message)
-			dispatchservice.PublishRealtimeMessage(&dispatchservice.RealtimeServerMessage{
-				Token:   md.dispatcher.Server().Token,
-				User:    md.dispatcher.Server().GetUser(),
-				WID:     md.dispatcher.Server().GetWId(),
-				State:   md.dispatcher.Server().GetState().String(),
-				Message: enriched,
-			})
-		},
+		PublishRealtime:  md.PublishRealtime,
 		HandlerCallbacks: handlerCallbacks,
 	}
 
 	dispatchservice.GetInstance().DispatchHandlerFlow(request)
 }
 
+// PublishRealtime sends a message only to realtime subscribers (cable/SignalR), skipping webhooks and other subscribers.
+func (md *MessageDispatcher) PublishRealtime(message *whatsapp.WhatsappMessage) {
+	if md.dispatcher == nil || md.dispatcher.Server() == nil || message == nil {
+		return
+	}
+
+	server := md.dispatcher.Server()
+	enriched := CloneAndEnrichMessageForServer(server, message)
+	dispatchservice.PublishRealtimeMessage(&dispatchservice.RealtimeServerMessage{
+		Token:   server.Token,
+		User:    server.GetUser(),
+		WID:     server.GetWId(),
+		State:   server.GetState().String(),
+		Message: enriched,
+	})
+}
+
 // Register adds a new event handler to be triggered on message dispatch.
 func (md *MessageDispatcher) Register(evt QpDispatchingHandlerInterface) {
 	md.syncRegister.Lock()
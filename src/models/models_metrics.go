@@ -16,4 +16,6 @@ var (
 	WebhookTimeouts           = metrics.CreateCounterRecorder("quepasa_webhook_timeouts_total", "Total webhook timeout errors")
 	WebhookHTTPErrors         = metrics.CreateCounterVecRecorder("quepasa_webhook_http_errors_total", "Total webhook HTTP errors by status code", []string{"status_code"})
 	WebhookSuccess            = metrics.CreateCounterRecorder("quepasa_webhook_success_total", "Total successful webhooks (HTTP 200)")
+	AntivirusScans            = metrics.CreateCounterVecRecorder("quepasa_antivirus_scans_total", "Total attachments scanned by direction (inbound, outbound) and status (clean, infected, error)", []string{"direction", "status"})
+	AntivirusBlocked          = metrics.CreateCounterVecRecorder("quepasa_antivirus_blocked_total", "Total attachments withheld by direction and action (blocked, quarantined)", []string{"direction", "action"})
 )
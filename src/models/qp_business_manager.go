@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Compile-time check to ensure QpBusinessManager implements whatsapp.WhatsappBusinessManagerInterface
+var _ whatsapp.WhatsappBusinessManagerInterface = (*QpBusinessManager)(nil)
+
+// QpBusinessManager handles business profile and catalog operations for QpWhatsappServer
+// Implements whatsapp.WhatsappBusinessManagerInterface interface
+type QpBusinessManager struct {
+	*QpWhatsappServer // embedded server for direct access
+}
+
+// NewQpBusinessManager creates a new QpBusinessManager instance
+func NewQpBusinessManager(server *QpWhatsappServer) *QpBusinessManager {
+	return &QpBusinessManager{
+		QpWhatsappServer: server,
+	}
+}
+
+// getBusinessManager is a helper function to get the business manager from connection
+func (bm *QpBusinessManager) getBusinessManager() (whatsapp.WhatsappBusinessManagerInterface, error) {
+	conn, err := bm.GetValidConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	businessManager := conn.GetBusinessManager()
+	if businessManager == nil {
+		return nil, fmt.Errorf("business manager not available")
+	}
+
+	return businessManager, nil
+}
+
+// GetBusinessProfile returns the business profile of a contact, empty wid means this session
+func (bm *QpBusinessManager) GetBusinessProfile(wid string) (*whatsapp.WhatsappBusinessProfile, error) {
+	businessManager, err := bm.getBusinessManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return businessManager.GetBusinessProfile(wid)
+}
+
+// GetCatalog returns a page of the catalog of a business, empty wid means this session
+func (bm *QpBusinessManager) GetCatalog(wid string, limit int, cursor string) (*whatsapp.WhatsappCatalog, error) {
+	businessManager, err := bm.getBusinessManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return businessManager.GetCatalog(wid, limit, cursor)
+}
@@ -0,0 +1,518 @@
+package models
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpChatExportFormat string
+
+const (
+	QpChatExportText QpChatExportFormat = "txt"
+	QpChatExportJson QpChatExportFormat = "json"
+	QpChatExportZip  QpChatExportFormat = "zip"
+)
+
+const (
+	// exports with more messages than this run as background jobs
+	QpChatExportSyncLimit = 1000
+
+	// hard limit of messages on a single export
+	QpChatExportMaxMessages = 100000
+
+	// finished export files are kept for download during this time
+	QpChatExportRetention = time.Hour
+)
+
+// ParseQpChatExportFormat validates an export format, default is txt
+func ParseQpChatExportFormat(format string) (QpChatExportFormat, error) {
+	switch QpChatExportFormat(strings.ToLower(strings.TrimSpace(format))) {
+	case "", QpChatExportText:
+		return QpChatExportText, nil
+	case QpChatExportJson:
+		return QpChatExportJson, nil
+	case QpChatExportZip:
+		return QpChatExportZip, nil
+	default:
+		return "", fmt.Errorf("invalid export format: %s, use txt, json or zip", format)
+	}
+}
+
+// GetContentType returns the mime type of the exported file
+func (source QpChatExportFormat) GetContentType() string {
+	switch source {
+	case QpChatExportJson:
+		return "application/json"
+	case QpChatExportZip:
+		return "application/zip"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// QpChatExportQuery selects the messages of a chat to export
+type QpChatExportQuery struct {
+	ChatId string
+	Format QpChatExportFormat
+
+	// date range, zero means unbounded
+	From time.Time
+	To   time.Time
+
+	// transcript dates are written on this location, default local
+	Location *time.Location
+}
+
+// GetFileName returns the exported file name, as the phone names it
+func (source QpChatExportQuery) GetFileName(title string) string {
+	return "WhatsApp Chat with " + GetChatExportSafeName(title) + "." + string(source.Format)
+}
+
+// QpChatExportJob is a background chat export, downloadable until retention ends
+type QpChatExportJob struct {
+	Id       string             `json:"id"`
+	Token    string             `json:"-"`
+	ChatId   string             `json:"chatid"`
+	Format   QpChatExportFormat `json:"format"`
+	Status   QpHistoryJobStatus `json:"status"`
+	FileName string             `json:"filename"`
+
+	Messages int    `json:"messages"`
+	Media    int    `json:"media,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	Created   time.Time  `json:"created"`
+	Completed *time.Time `json:"completed,omitempty"`
+
+	path string
+}
+
+// QpChatExportJobs keeps background exports in memory, files live on the temporary directory
+type QpChatExportJobs struct {
+	mutex sync.Mutex
+	items map[string]*QpChatExportJob
+}
+
+var ChatExportJobs = &QpChatExportJobs{items: make(map[string]*QpChatExportJob)}
+
+func (source *QpChatExportJobs) add(job *QpChatExportJob) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.cleanup(time.Now())
+	source.items[job.Id] = job
+}
+
+// Get returns a copy of an export job of the server
+func (source *QpChatExportJobs) Get(token string, id string) (*QpChatExportJob, bool) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.cleanup(time.Now())
+	job, ok := source.items[id]
+	if !ok || job.Token != token {
+		return nil, false
+	}
+
+	copied := *job
+	return &copied, true
+}
+
+func (source *QpChatExportJobs) finish(id string, result *QpChatExportJob, err error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	job, ok := source.items[id]
+	if !ok {
+		// deleted while running, nobody will download it
+		os.Remove(result.path)
+		return
+	}
+
+	now := time.Now()
+	job.Completed = &now
+	if err != nil {
+		job.Status = QpHistoryJobFailed
+		job.Error = err.Error()
+		return
+	}
+
+	job.Status = QpHistoryJobCompleted
+	job.FileName = result.FileName
+	job.Messages = result.Messages
+	job.Media = result.Media
+	job.Size = result.Size
+}
+
+// DeleteByServer removes every export of a server, with its files
+func (source *QpChatExportJobs) DeleteByServer(token string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	for id, job := range source.items {
+		if job.Token == token {
+			os.Remove(job.path)
+			delete(source.items, id)
+		}
+	}
+}
+
+// DeleteByChats removes the exports of the given chats of a server, with their files
+func (source *QpChatExportJobs) DeleteByChats(token string, ids []string) (deleted int) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	for id, job := range source.items {
+		if job.Token == token && slices.Contains(ids, job.ChatId) {
+			os.Remove(job.path)
+			delete(source.items, id)
+			deleted++
+		}
+	}
+	return
+}
+
+// removes finished exports after retention, must hold the lock
+func (source *QpChatExportJobs) cleanup(now time.Time) {
+	for id, job := range source.items {
+		if job.Completed != nil && now.Sub(*job.Completed) > QpChatExportRetention {
+			os.Remove(job.path)
+			delete(source.items, id)
+		}
+	}
+}
+
+// getChatExportStoreQuery returns the message store query of the export date range
+func getChatExportStoreQuery(query QpChatExportQuery) QpStoredMessagesQuery {
+	page := QpStoredMessagesQuery{ChatId: query.ChatId, Before: query.To, Ascending: true, Limit: QpStoredMessagesMaxLimit}
+	if !query.From.IsZero() {
+		// store filter is exclusive
+		page.After = query.From.Add(-time.Millisecond)
+	}
+	return page
+}
+
+// CountChatExportMessages returns how many messages an export would include, failing above the
+// export limit, so callers can decide on a background job before loading them
+func (server *QpWhatsappServer) CountChatExportMessages(query QpChatExportQuery) (int, error) {
+	var count int
+	if store, ok := GetMessageStore(); ok {
+		var err error
+		count, err = store.CountByChat(server.Token, getChatExportStoreQuery(query))
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		messages, err := server.getCachedChatExportMessages(query)
+		if err != nil {
+			return 0, err
+		}
+		count = len(messages)
+	}
+
+	if count > QpChatExportMaxMessages {
+		return count, fmt.Errorf("too many messages to export, max %d, narrow the date range", QpChatExportMaxMessages)
+	}
+	return count, nil
+}
+
+// GetChatExportMessages returns the messages of a chat on the date range, oldest first,
+// from the message store when enabled, otherwise from the cache
+func (server *QpWhatsappServer) GetChatExportMessages(query QpChatExportQuery) ([]*QpStoredMessage, error) {
+	if store, ok := GetMessageStore(); ok {
+		page := getChatExportStoreQuery(query)
+
+		messages := []*QpStoredMessage{}
+		for {
+			items, next, err := store.FindByChat(server.Token, page)
+			if err != nil {
+				return nil, err
+			}
+
+			messages = append(messages, items...)
+			if len(next) == 0 {
+				return messages, nil
+			}
+
+			if len(messages) >= QpChatExportMaxMessages {
+				return nil, fmt.Errorf("too many messages to export, max %d, narrow the date range", QpChatExportMaxMessages)
+			}
+			page.Cursor = next
+		}
+	}
+
+	return server.getCachedChatExportMessages(query)
+}
+
+func (server *QpWhatsappServer) getCachedChatExportMessages(query QpChatExportQuery) ([]*QpStoredMessage, error) {
+	if server.Handler == nil {
+		return nil, fmt.Errorf("messages not available")
+	}
+
+	messages := []*QpStoredMessage{}
+	for _, msg := range server.Handler.GetSlice() {
+		if msg.Chat.Id != query.ChatId || msg.Type == whatsapp.SystemMessageType || msg.Type == whatsapp.UnhandledMessageType || msg.InReaction {
+			continue
+		}
+		if !query.From.IsZero() && msg.Timestamp.Before(query.From) {
+			continue
+		}
+		if !query.To.IsZero() && !msg.Timestamp.Before(query.To) {
+			continue
+		}
+		messages = append(messages, NewQpStoredMessage(server.Token, msg))
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].SentAt < messages[j].SentAt })
+	return messages, nil
+}
+
+// WriteChatExport writes the export of the given messages, returns how many media files were included
+func (server *QpWhatsappServer) WriteChatExport(w io.Writer, query QpChatExportQuery, messages []*QpStoredMessage) (int, error) {
+	switch query.Format {
+	case QpChatExportJson:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return 0, encoder.Encode(messages)
+	case QpChatExportZip:
+		return server.writeChatExportZip(w, query, messages)
+	default:
+		return 0, server.WriteChatTranscript(w, query, messages, nil)
+	}
+}
+
+func (server *QpWhatsappServer) writeChatExportZip(w io.Writer, query QpChatExportQuery, messages []*QpStoredMessage) (int, error) {
+	archive := zip.NewWriter(w)
+	logentry := server.GetLogger().WithField(LogFields.ChatId, query.ChatId)
+
+	// media of messages already expired from the cache are omitted, contents are downloaded
+	// without keeping them on the cached messages, so memory holds one entry at a time
+	files := make(map[string]string)
+	for _, message := range messages {
+		if message.Attachment == nil || message.Revoked || server.Handler == nil {
+			continue
+		}
+
+		att, err := server.Download(message.Id, false)
+		if err != nil || att == nil || att.GetContent() == nil {
+			if err != nil {
+				logentry.Debugf("export media omitted for %s: %s", message.Id, err.Error())
+			}
+			continue
+		}
+
+		name := GetChatExportMediaFileName(message, att)
+		writer, err := archive.Create(name)
+		if err != nil {
+			return len(files), err
+		}
+		if _, err = writer.Write(*att.GetContent()); err != nil {
+			return len(files), err
+		}
+		files[message.Id] = name
+	}
+
+	title := server.GetChatExportTitle(query.ChatId, messages)
+	writer, err := archive.Create(QpChatExportQuery{Format: QpChatExportText}.GetFileName(title))
+	if err != nil {
+		return len(files), err
+	}
+	if err = server.WriteChatTranscript(writer, query, messages, files); err != nil {
+		return len(files), err
+	}
+
+	return len(files), archive.Close()
+}
+
+// GetChatExportMediaFileName returns the file name of an exported attachment, unique by message id
+func GetChatExportMediaFileName(message *QpStoredMessage, att *whatsapp.WhatsappAttachment) string {
+	if name := GetChatExportSafeName(att.FileName); len(name) > 0 {
+		return message.Id + "-" + name
+	}
+
+	name := message.Id
+	if exten, ok := library.TryGetExtensionFromMimeType(att.Mimetype); ok {
+		name += exten
+	}
+	return name
+}
+
+// GetChatExportSafeName removes path separators and control characters from a file name
+func GetChatExportSafeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	return strings.Trim(strings.TrimSpace(name), ".")
+}
+
+// GetChatExportTitle returns the chat name used on file names and as direct chat sender
+func (server *QpWhatsappServer) GetChatExportTitle(chatId string, messages []*QpStoredMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if len(messages[i].ChatTitle) > 0 {
+			return messages[i].ChatTitle
+		}
+	}
+
+	if title := server.GetChatTitle(chatId); len(title) > 0 {
+		return title
+	}
+
+	return library.GetPhoneByWId(chatId)
+}
+
+// WriteChatTranscript writes messages on the phone export layout, "dd/mm/yyyy, hh:mm - sender: text",
+// attachments reference included files or are written as omitted
+func (server *QpWhatsappServer) WriteChatTranscript(w io.Writer, query QpChatExportQuery, messages []*QpStoredMessage, files map[string]string) error {
+	location := query.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	title := server.GetChatExportTitle(query.ChatId, messages)
+	for _, message := range messages {
+		sender := "You"
+		if !message.FromMe {
+			switch {
+			case len(message.ParticipantTitle) > 0:
+				sender = message.ParticipantTitle
+			case len(message.ParticipantId) > 0:
+				sender = library.GetPhoneByWId(message.ParticipantId)
+			default:
+				sender = title
+			}
+		}
+
+		var body string
+		switch {
+		case message.Revoked:
+			body = "This message was deleted"
+		case message.Attachment != nil:
+			if name, ok := files[message.Id]; ok {
+				body = name + " (file attached)"
+			} else {
+				body = "<Media omitted>"
+			}
+			if len(message.Text) > 0 {
+				body += "\n" + message.Text
+			}
+		default:
+			body = message.Text
+		}
+
+		if message.Edited && !message.Revoked {
+			body += " <This message was edited>"
+		}
+
+		timestamp := time.UnixMilli(message.SentAt).In(location).Format("02/01/2006, 15:04")
+		if _, err := fmt.Fprintf(w, "%s - %s: %s\n", timestamp, sender, body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartChatExport loads the messages and writes the export on a temporary file in background,
+// downloadable when completed; count is the expected number of messages, reported until then
+func (server *QpWhatsappServer) StartChatExport(query QpChatExportQuery, count int) (*QpChatExportJob, error) {
+	directory := filepath.Join(os.TempDir(), "quepasa", "exports")
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	job := &QpChatExportJob{
+		Id:       id,
+		Token:    server.Token,
+		ChatId:   query.ChatId,
+		Format:   query.Format,
+		Status:   QpHistoryJobPending,
+		FileName: query.GetFileName(server.GetChatExportTitle(query.ChatId, nil)),
+		Messages: count,
+		Created:  time.Now(),
+		path:     filepath.Join(directory, id+"."+string(query.Format)),
+	}
+	ChatExportJobs.add(job)
+
+	go func() {
+		logentry := server.GetLogger().WithField(LogFields.ChatId, query.ChatId)
+
+		result := &QpChatExportJob{path: job.path, FileName: job.FileName}
+		err := func() error {
+			messages, err := server.GetChatExportMessages(query)
+			if err != nil {
+				return err
+			}
+
+			result.Messages = len(messages)
+			result.FileName = query.GetFileName(server.GetChatExportTitle(query.ChatId, messages))
+
+			file, err := os.Create(job.path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			result.Media, err = server.WriteChatExport(file, query, messages)
+			if err != nil {
+				return err
+			}
+
+			info, err := file.Stat()
+			if err != nil {
+				return err
+			}
+			result.Size = info.Size()
+			return nil
+		}()
+
+		if err != nil {
+			logentry.Errorf("chat export %s failed: %s", id, err.Error())
+			os.Remove(job.path)
+		} else {
+			logentry.Infof("chat export %s completed, messages: %d, media: %d", id, result.Messages, result.Media)
+		}
+
+		ChatExportJobs.finish(id, result, err)
+	}()
+
+	copied := *job
+	return &copied, nil
+}
+
+// GetChatExport returns a background export of this server
+func (server *QpWhatsappServer) GetChatExport(id string) (*QpChatExportJob, bool) {
+	return ChatExportJobs.Get(server.Token, strings.TrimSpace(id))
+}
+
+// OpenChatExport opens the file of a completed background export
+func (server *QpWhatsappServer) OpenChatExport(id string) (*QpChatExportJob, *os.File, error) {
+	job, ok := server.GetChatExport(id)
+	if !ok {
+		return nil, nil, fmt.Errorf("export not found: %s", id)
+	}
+
+	if job.Status != QpHistoryJobCompleted {
+		return job, nil, fmt.Errorf("export is %s", job.Status)
+	}
+
+	file, err := os.Open(job.path)
+	return job, file, err
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQpChatExportTranscript(t *testing.T) {
+	server := &QpWhatsappServer{}
+	base := time.Date(2026, 10, 19, 14, 5, 0, 0, time.UTC)
+
+	messages := []*QpStoredMessage{
+		{Id: "MSG-1", ChatId: "5521999999999@s.whatsapp.net", ChatTitle: "Alice", Text: "hello", SentAt: base.UnixMilli()},
+		{Id: "MSG-2", FromMe: true, Text: "hi\nhow are you?", SentAt: base.Add(time.Minute).UnixMilli()},
+		{Id: "MSG-3", Attachment: &QpStoredAttachment{Mimetype: "image/jpeg"}, Text: "look", SentAt: base.Add(2 * time.Minute).UnixMilli()},
+		{Id: "MSG-4", Attachment: &QpStoredAttachment{Mimetype: "image/jpeg"}, SentAt: base.Add(3 * time.Minute).UnixMilli()},
+		{Id: "MSG-5", Revoked: true, Text: "oops", SentAt: base.Add(4 * time.Minute).UnixMilli()},
+		{Id: "MSG-6", Edited: true, Text: "fixed", ParticipantTitle: "Bob", SentAt: base.Add(5 * time.Minute).UnixMilli()},
+	}
+
+	query := QpChatExportQuery{ChatId: "5521999999999@s.whatsapp.net", Format: QpChatExportText, Location: time.UTC}
+	builder := &strings.Builder{}
+	if err := server.WriteChatTranscript(builder, query, messages, map[string]string{"MSG-3": "MSG-3.jpg"}); err != nil {
+		t.Fatalf("write transcript: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"19/10/2026, 14:05 - Alice: hello",
+		"19/10/2026, 14:06 - You: hi",
+		"how are you?",
+		"19/10/2026, 14:07 - Alice: MSG-3.jpg (file attached)",
+		"look",
+		"19/10/2026, 14:08 - Alice: <Media omitted>",
+		"19/10/2026, 14:09 - Alice: This message was deleted",
+		"19/10/2026, 14:10 - Bob: fixed <This message was edited>",
+		"",
+	}, "\n")
+	if builder.String() != expected {
+		t.Fatalf("unexpected transcript:\n%s", builder.String())
+	}
+
+	if name := query.GetFileName("Alice/Bob"); name != "WhatsApp Chat with Alice_Bob.txt" {
+		t.Fatalf("unexpected file name: %s", name)
+	}
+
+	if _, err := ParseQpChatExportFormat("pdf"); err == nil {
+		t.Fatalf("expected invalid format error")
+	}
+}
@@ -0,0 +1,357 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	cache "github.com/nocodeleaks/quepasa/cache"
+	cacheservice "github.com/nocodeleaks/quepasa/cache/service"
+	library "github.com/nocodeleaks/quepasa/library"
+	storage "github.com/nocodeleaks/quepasa/media/storage"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
+)
+
+// QpContactPurgeReport describes what was removed of a contact, right to be forgotten
+type QpContactPurgeReport struct {
+	// identities of the contact, phone and lid, that were purged
+	Phone string   `json:"phone,omitempty"`
+	LId   string   `json:"lid,omitempty"`
+	Ids   []string `json:"ids"`
+
+	Sessions []*QpContactPurgeSession `json:"sessions"`
+
+	// pending dispatch payloads of the contact removed from the retry queue
+	DispatchQueue int `json:"dispatchqueue"`
+
+	// lid to phone mappings of the contact, shared by every session of the store
+	LIDMappings int `json:"lidmappings"`
+
+	// offloaded attachments removed from the object storage, objects still referenced
+	// by messages of other contacts or sessions are kept
+	StoredMedia int `json:"storedmedia"`
+
+	Errors    []string  `json:"errors,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// QpContactPurgeSession counts what was removed of a contact on a session
+type QpContactPurgeSession struct {
+	Token string `json:"token"`
+	Wid   string `json:"wid,omitempty"`
+
+	CachedMessages  int  `json:"cachedmessages"`
+	CachedMedia     int  `json:"cachedmedia"`
+	StoredMessages  int  `json:"storedmessages"`
+	StoredReactions int  `json:"storedreactions"`
+	Chats           int  `json:"chats"`
+	Labels          uint `json:"labels"`
+	Contacts        int  `json:"contacts"`
+	Exports         int  `json:"exports"`
+
+	Errors []string `json:"errors,omitempty"`
+
+	// object storage keys of the purged attachments, removed once unreferenced
+	storageKeys []string
+}
+
+// PurgeContact removes every data of a contact, given by phone and/or lid, from the given sessions:
+// cached messages and media, stored messages and reactions, chat list entries, conversation labels,
+// whatsapp contact store entries and lid mappings, chat exports, pending dispatches and offloaded media
+func PurgeContact(phone string, lid string, servers []*QpWhatsappServer) (*QpContactPurgeReport, error) {
+	phone, lid, err := GetContactPurgeIdentity(phone, lid)
+	if err != nil {
+		return nil, err
+	}
+
+	// each session may know a different side of the phone/lid mapping
+	for _, server := range servers {
+		if len(phone) > 0 && len(lid) > 0 {
+			break
+		}
+		phone, lid = server.ResolveContactIdentity(phone, lid)
+	}
+
+	report := &QpContactPurgeReport{
+		Phone:     phone,
+		LId:       lid,
+		Ids:       GetContactPurgeIds(phone, lid),
+		Sessions:  []*QpContactPurgeSession{},
+		Timestamp: time.Now().UTC(),
+	}
+
+	wids := []string{}
+	keys := []string{}
+	for _, server := range servers {
+		session := server.PurgeContact(report.Ids)
+		report.Sessions = append(report.Sessions, session)
+		keys = append(keys, session.storageKeys...)
+		if wid := server.GetWId(); len(wid) > 0 {
+			wids = append(wids, wid)
+		}
+	}
+
+	if backend, ok := GetMediaStorage(); ok && len(keys) > 0 {
+		report.StoredMedia, err = PurgeStoredMedia(backend, keys, GetContactPurgeMediaServers(servers))
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("media storage: %s", err.Error()))
+		}
+	}
+
+	if backend := cacheservice.GetInstance().GetQueueBackend(); backend != nil && len(wids) > 0 {
+		report.DispatchQueue, err = cache.PurgeQueue(backend, func(payload []byte) bool {
+			return IsContactQueuedPayload(payload, wids, report.Ids)
+		})
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("dispatch queue: %s", err.Error()))
+		}
+
+		// retry queue is the rabbitmq cache, keep its size metric in sync
+		if report.DispatchQueue > 0 {
+			AddRabbitMQCacheSize(-float64(report.DispatchQueue))
+		}
+	}
+
+	if whatsmeow.WhatsmeowService != nil {
+		report.LIDMappings, err = whatsmeow.WhatsmeowService.PurgeLIDMappings(report.Ids)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("lid mappings: %s", err.Error()))
+		}
+	}
+
+	whatsmeow.GetGlobalContactMaps().DeleteContact(phone, lid)
+	return report, nil
+}
+
+// GetContactPurgeMediaServers returns every session whose cached messages may reference offloaded media,
+// object keys are content addressed and shared between sessions
+func GetContactPurgeMediaServers(servers []*QpWhatsappServer) []*QpWhatsappServer {
+	if WhatsappService == nil {
+		return servers
+	}
+
+	result := slices.Clone(servers)
+	for _, server := range WhatsappService.Servers {
+		if server != nil && !slices.Contains(result, server) {
+			result = append(result, server)
+		}
+	}
+	return result
+}
+
+// PurgeStoredMedia removes the given objects from the storage, unless still referenced
+// by a stored message or by a cached message of the given sessions, returns how many were removed
+func PurgeStoredMedia(backend storage.ObjectStorage, keys []string, servers []*QpWhatsappServer) (int, error) {
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+	referenced := make(map[string]bool)
+
+	if store, ok := GetMessageStore(); ok {
+		found, err := store.FindStorageKeys(keys)
+		if err != nil {
+			return 0, err
+		}
+		for _, key := range found {
+			referenced[key] = true
+		}
+	}
+
+	for _, server := range servers {
+		if server.Handler == nil {
+			continue
+		}
+		for _, msg := range server.Handler.GetSlice() {
+			if msg.Attachment != nil && len(msg.Attachment.StorageKey) > 0 {
+				referenced[msg.Attachment.StorageKey] = true
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), QpMediaStorageTimeout)
+	defer cancel()
+
+	removed := 0
+	for _, key := range keys {
+		if referenced[key] {
+			continue
+		}
+		if err := backend.Delete(ctx, key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// GetContactPurgeIdentity validates the contact to purge, phone (E164 or user jid) and/or lid
+func GetContactPurgeIdentity(phone string, lid string) (string, string, error) {
+	phone = strings.TrimSpace(phone)
+	lid = strings.TrimSpace(lid)
+
+	if len(phone) == 0 && len(lid) == 0 {
+		return "", "", fmt.Errorf("missing phone or lid parameter")
+	}
+
+	if strings.HasSuffix(phone, whatsapp.WHATSAPP_SERVERDOMAIN_LID_SUFFIX) && len(lid) == 0 {
+		lid, phone = phone, ""
+	}
+
+	if len(phone) > 0 {
+		valid, err := whatsapp.GetPhoneIfValid(phone)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid phone: %s", phone)
+		}
+		phone = valid
+	}
+
+	if len(lid) > 0 {
+		if !strings.Contains(lid, "@") {
+			lid = lid + whatsapp.WHATSAPP_SERVERDOMAIN_LID_SUFFIX
+		}
+
+		if !strings.HasSuffix(lid, whatsapp.WHATSAPP_SERVERDOMAIN_LID_SUFFIX) {
+			return "", "", fmt.Errorf("invalid lid: %s", lid)
+		}
+	}
+
+	return phone, lid, nil
+}
+
+// GetContactPurgeIds returns every chat id a contact may be known by,
+// including the phone variants of its country rule, e.g. brazilian ninth digit
+func GetContactPurgeIds(phone string, lid string) []string {
+	ids := []string{}
+	if len(phone) > 0 {
+		ids = append(ids, whatsapp.PhoneToWid(phone))
+		for _, variant := range library.GetPhoneVariants(phone, false) {
+			ids = append(ids, whatsapp.PhoneToWid(variant))
+		}
+	}
+
+	if len(lid) > 0 {
+		ids = append(ids, lid)
+	}
+
+	return ids
+}
+
+// IsContactQueuedPayload checks if a queued dispatch payload is a message of one of the sessions
+// with one of the contact ids
+func IsContactQueuedPayload(payload []byte, wids []string, ids []string) bool {
+	// only the fields needed, message type does not unmarshal back
+	queued := struct {
+		Payload *struct {
+			Wid         string                 `json:"wid"`
+			Chat        whatsapp.WhatsappChat  `json:"chat"`
+			Participant *whatsapp.WhatsappChat `json:"participant"`
+		} `json:"payload"`
+	}{}
+
+	if err := json.Unmarshal(payload, &queued); err != nil || queued.Payload == nil {
+		return false
+	}
+
+	msg := &whatsapp.WhatsappMessage{Chat: queued.Payload.Chat, Participant: queued.Payload.Participant}
+	return slices.Contains(wids, queued.Payload.Wid) && IsContactMessage(msg, ids)
+}
+
+// IsContactMessage checks if the message was exchanged with, or sent on a group by, one of the contact ids
+func IsContactMessage(msg *whatsapp.WhatsappMessage, ids []string) bool {
+	if msg == nil {
+		return false
+	}
+
+	if slices.Contains(ids, msg.Chat.Id) || (len(msg.Chat.LId) > 0 && slices.Contains(ids, msg.Chat.LId)) {
+		return true
+	}
+
+	participant := msg.Participant
+	return participant != nil && (slices.Contains(ids, participant.Id) || (len(participant.LId) > 0 && slices.Contains(ids, participant.LId)))
+}
+
+// ResolveContactIdentity fills the missing side of the phone/lid mapping, when known by this session,
+// local lookups only, disconnected sessions resolve nothing
+func (server *QpWhatsappServer) ResolveContactIdentity(phone string, lid string) (string, string) {
+	contactManager := server.GetContactManager()
+	if len(lid) == 0 && len(phone) > 0 {
+		if resolved, err := contactManager.GetLIDFromPhone(phone); err == nil {
+			lid = resolved
+		}
+	} else if len(phone) == 0 && len(lid) > 0 {
+		if resolved, err := contactManager.GetPhoneFromLID(lid); err == nil && len(resolved) > 0 {
+			if valid, err := whatsapp.GetPhoneIfValid(resolved); err == nil {
+				phone = valid
+			}
+		}
+	}
+
+	return phone, lid
+}
+
+// PurgeContact removes every data of this session exchanged with the contact ids
+func (server *QpWhatsappServer) PurgeContact(ids []string) *QpContactPurgeSession {
+	result := &QpContactPurgeSession{Token: server.Token, Wid: server.GetWId()}
+	logentry := server.GetLogger()
+
+	fail := func(source string, err error) {
+		logentry.Errorf("contact purge, %s: %s", source, err.Error())
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", source, err.Error()))
+	}
+
+	if server.Handler != nil {
+		result.CachedMessages, result.CachedMedia = server.Handler.Purge(func(msg *whatsapp.WhatsappMessage) bool {
+			if !IsContactMessage(msg, ids) {
+				return false
+			}
+			if msg.Attachment != nil && len(msg.Attachment.StorageKey) > 0 {
+				result.storageKeys = append(result.storageKeys, msg.Attachment.StorageKey)
+			}
+			return true
+		})
+	}
+
+	var err error
+	if store, ok := GetMessageStore(); ok {
+		// looked up before deleting, the stored attachments hold the keys
+		var keys []string
+		if keys, err = store.FindStorageKeysByContact(server.Token, ids); err != nil {
+			fail("message store media", err)
+		}
+		result.storageKeys = append(result.storageKeys, keys...)
+
+		result.StoredMessages, result.StoredReactions, err = store.DeleteByContact(server.Token, ids)
+		if err != nil {
+			fail("message store", err)
+		}
+	}
+
+	if chats, ok := GetChatStore(); ok {
+		result.Chats, err = chats.DeleteByChats(server.Token, ids)
+		if err != nil {
+			fail("chat store", err)
+		}
+	}
+
+	if labels, ok := getConversationLabelStore(); ok {
+		result.Labels, err = labels.RemoveByChats(server.Token, ids)
+		if err != nil {
+			fail("conversation labels", err)
+		}
+	}
+
+	if wid := server.GetWId(); len(wid) > 0 && whatsmeow.WhatsmeowService != nil {
+		result.Contacts, err = whatsmeow.WhatsmeowService.PurgeContacts(wid, ids)
+		if err != nil {
+			fail("contact store", err)
+		}
+	}
+
+	result.Exports = ChatExportJobs.DeleteByChats(server.Token, ids)
+
+	logentry.Infof("contact purged, ids: %v, cached: %d, stored: %d, chats: %d, labels: %d, contacts: %d",
+		ids, result.CachedMessages, result.StoredMessages, result.Chats, result.Labels, result.Contacts)
+	return result
+}
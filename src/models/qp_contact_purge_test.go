@@ -0,0 +1,131 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+func TestGetContactPurgeIdentity(t *testing.T) {
+	phone, lid, err := GetContactPurgeIdentity(" +5521999999999 ", "123456789")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if phone != "+5521999999999" || lid != "123456789@lid" {
+		t.Errorf("unexpected identity: %s, %s", phone, lid)
+	}
+
+	phone, lid, _ = GetContactPurgeIdentity("123456789@lid", "")
+	if phone != "" || lid != "123456789@lid" {
+		t.Errorf("lid given as phone should be moved: %s, %s", phone, lid)
+	}
+
+	if _, _, err = GetContactPurgeIdentity("", ""); err == nil {
+		t.Errorf("expected error without contact")
+	}
+	if _, _, err = GetContactPurgeIdentity("", "120363000000000000@g.us"); err == nil {
+		t.Errorf("expected error for groups")
+	}
+
+	ids := GetContactPurgeIds("+5521999999999", "123456789@lid")
+	if !slices.Contains(ids, "5521999999999@s.whatsapp.net") || !slices.Contains(ids, "552199999999@s.whatsapp.net") || !slices.Contains(ids, "123456789@lid") {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+}
+
+func TestIsContactQueuedPayload(t *testing.T) {
+	ids := []string{"5521999999999@s.whatsapp.net", "123456789@lid"}
+
+	queued := func(msg *whatsapp.WhatsappMessage) []byte {
+		payload, _ := json.Marshal(map[string]any{"id": "1", "payload": msg})
+		return payload
+	}
+
+	group := whatsapp.WhatsappChat{Id: "120363000000000000@g.us"}
+	participant := &whatsapp.WhatsappChat{Id: "123456789@lid"}
+
+	if !IsContactQueuedPayload(queued(&whatsapp.WhatsappMessage{Wid: "wid-1", Chat: group, Participant: participant}), []string{"wid-1"}, ids) {
+		t.Errorf("expected participant match")
+	}
+	if IsContactQueuedPayload(queued(&whatsapp.WhatsappMessage{Wid: "wid-2", Chat: whatsapp.WhatsappChat{Id: ids[0]}}), []string{"wid-1"}, ids) {
+		t.Errorf("other sessions should not match")
+	}
+	if IsContactQueuedPayload([]byte("not json"), []string{"wid-1"}, ids) {
+		t.Errorf("invalid payloads should be kept")
+	}
+}
+
+type deleteRecordingStorage struct {
+	deleted []string
+}
+
+func (s *deleteRecordingStorage) Put(context.Context, string, []byte, string) error { return nil }
+
+func (s *deleteRecordingStorage) GetUrl(key string) (string, *time.Time, error) {
+	return "https://media.example.com/" + key, nil, nil
+}
+
+func (s *deleteRecordingStorage) Delete(_ context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func (s *deleteRecordingStorage) Cleanup(context.Context, time.Time) (int, error) { return 0, nil }
+
+func TestPurgeContactRemovesOffloadedMedia(t *testing.T) {
+	store := newTestMessagesStore(t)
+	backend := &deleteRecordingStorage{}
+
+	prevService, prevStorage, prevSetting := WhatsappService, mediaStorage, environment.Settings.General.MessageStore
+	defer func() {
+		WhatsappService, mediaStorage, environment.Settings.General.MessageStore = prevService, prevStorage, prevSetting
+	}()
+
+	server := &QpWhatsappServer{QpServer: &QpServer{Token: "token-1"}}
+	WhatsappService = &QPWhatsappService{DB: &QpDatabase{Messages: store}, Servers: map[string]*QpWhatsappServer{server.Token: server}}
+	mediaStorage = backend
+	environment.Settings.General.MessageStore = true
+
+	contact := whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net"}
+	other := whatsapp.WhatsappChat{Id: "5521888888888@s.whatsapp.net"}
+	media := func(id string, chat whatsapp.WhatsappChat, key string) *whatsapp.WhatsappMessage {
+		return &whatsapp.WhatsappMessage{Id: id, Chat: chat, Type: whatsapp.ImageMessageType, Timestamp: time.Now(),
+			Attachment: &whatsapp.WhatsappAttachment{Mimetype: "image/jpeg", StorageKey: key}}
+	}
+
+	// same content sent to the other contact, the shared object must be kept
+	for _, msg := range []*whatsapp.WhatsappMessage{
+		media("msg-1", contact, "media/2026/10/19/private.jpg"),
+		media("msg-2", contact, "media/2026/10/19/shared.jpg"),
+		media("msg-3", other, "media/2026/10/19/shared.jpg"),
+	} {
+		if err := store.Store(server.Token, msg); err != nil {
+			t.Fatalf("store %s: %v", msg.Id, err)
+		}
+	}
+
+	report, err := PurgeContact("+5521999999999", "123456789@lid", []*QpWhatsappServer{server})
+	if err != nil {
+		t.Fatalf("purge contact: %v", err)
+	}
+
+	if !slices.Equal(backend.deleted, []string{"media/2026/10/19/private.jpg"}) {
+		t.Errorf("expected only the unshared object deleted, got: %v", backend.deleted)
+	}
+	if report.StoredMedia != 1 {
+		t.Errorf("expected one stored media purged, got: %d", report.StoredMedia)
+	}
+
+	// the last reference is gone, the shared object follows its message
+	if _, err := PurgeContact("+5521888888888", "987654321@lid", []*QpWhatsappServer{server}); err != nil {
+		t.Fatalf("purge other contact: %v", err)
+	}
+	if !slices.Contains(backend.deleted, "media/2026/10/19/shared.jpg") {
+		t.Errorf("expected the shared object deleted with its last message, got: %v", backend.deleted)
+	}
+}
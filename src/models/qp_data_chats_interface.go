@@ -0,0 +1,13 @@
+package models
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpDataChatsInterface interface {
+	Touch(token string, msg *whatsapp.WhatsappMessage) error
+	UpdateState(token string, state *whatsapp.WhatsappChatState) error
+	Find(token string, query QpStoredChatsQuery) ([]*QpStoredChat, int, error)
+	DeleteByServer(token string) error
+	DeleteByChats(token string, ids []string) (int, error)
+}
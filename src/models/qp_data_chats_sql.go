@@ -0,0 +1,216 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpDataChatsSql struct {
+	db *sqlx.DB
+}
+
+const storedChatColumns = "server_token, chat_id, title, last_message_id, last_message_type, last_message_text, last_message_fromme, last_message_at, unread, markedunread, archived, pinned, muted_until, ephemeral, timestamp"
+
+// Touch updates the chat list entry from a new message
+func (source QpDataChatsSql) Touch(token string, msg *whatsapp.WhatsappMessage) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("server token is required")
+	}
+	if msg == nil || len(msg.Chat.Id) == 0 {
+		return fmt.Errorf("chat id is required")
+	}
+
+	// reactions do not change the chat list
+	if msg.InReaction {
+		return nil
+	}
+
+	id := strings.ToUpper(msg.Id)
+	preview := GetChatPreviewText(msg)
+
+	// edits and revokes only refresh the preview of the last message
+	if msg.Edited || msg.Type == whatsapp.RevokeMessageType {
+		_, err := source.db.Exec(`
+			UPDATE chats SET last_message_type = ?, last_message_text = ?, timestamp = CURRENT_TIMESTAMP
+			WHERE server_token = ? AND chat_id = ? AND last_message_id = ?
+		`, msg.Type.String(), preview, token, msg.Chat.Id, id)
+		return err
+	}
+
+	// messages sent by us mean the chat was read, history never changes counters
+	unread := 0
+	if !msg.FromMe && !msg.FromHistory {
+		unread = 1
+	}
+	read := msg.FromMe && !msg.FromHistory
+
+	_, err := source.db.Exec(`
+		INSERT INTO chats (server_token, chat_id, title, last_message_id, last_message_type, last_message_text, last_message_fromme, last_message_at, unread)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (server_token, chat_id) DO UPDATE SET
+			title = CASE WHEN excluded.title = '' THEN chats.title ELSE excluded.title END,
+			last_message_id = CASE WHEN excluded.last_message_at >= chats.last_message_at THEN excluded.last_message_id ELSE chats.last_message_id END,
+			last_message_type = CASE WHEN excluded.last_message_at >= chats.last_message_at THEN excluded.last_message_type ELSE chats.last_message_type END,
+			last_message_text = CASE WHEN excluded.last_message_at >= chats.last_message_at THEN excluded.last_message_text ELSE chats.last_message_text END,
+			last_message_fromme = CASE WHEN excluded.last_message_at >= chats.last_message_at THEN excluded.last_message_fromme ELSE chats.last_message_fromme END,
+			last_message_at = CASE WHEN excluded.last_message_at >= chats.last_message_at THEN excluded.last_message_at ELSE chats.last_message_at END,
+			unread = CASE WHEN ? THEN 0 ELSE chats.unread + excluded.unread END,
+			markedunread = CASE WHEN ? THEN FALSE ELSE chats.markedunread END,
+			timestamp = CURRENT_TIMESTAMP
+	`, token, msg.Chat.Id, msg.Chat.Title, id, msg.Type.String(), preview, msg.FromMe, msg.Timestamp.UnixMilli(), unread, read, read)
+	return err
+}
+
+// UpdateState applies a partial chat list entry update, deleted chats are removed
+func (source QpDataChatsSql) UpdateState(token string, state *whatsapp.WhatsappChatState) (err error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("server token is required")
+	}
+	if state == nil || len(state.Id) == 0 {
+		return fmt.Errorf("chat id is required")
+	}
+
+	if state.Deleted {
+		_, err = source.db.Exec("DELETE FROM chats WHERE server_token = ? AND chat_id = ?", token, state.Id)
+		return err
+	}
+
+	fields := []string{}
+	args := []any{}
+	set := func(field string, value any) {
+		fields = append(fields, field+" = ?")
+		args = append(args, value)
+	}
+
+	if len(state.Title) > 0 {
+		set("title", state.Title)
+	}
+	if state.Archived != nil {
+		set("archived", *state.Archived)
+	}
+	if state.Pinned != nil {
+		set("pinned", *state.Pinned)
+	}
+	if state.MutedUntil != nil {
+		set("muted_until", *state.MutedUntil)
+	}
+	if state.Unread != nil {
+		set("unread", *state.Unread)
+	}
+	if state.MarkedUnread != nil {
+		set("markedunread", *state.MarkedUnread)
+	}
+	if state.Ephemeral != nil {
+		set("ephemeral", *state.Ephemeral)
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec("INSERT INTO chats (server_token, chat_id) VALUES (?, ?) ON CONFLICT (server_token, chat_id) DO NOTHING", token, state.Id); err != nil {
+		return err
+	}
+
+	args = append(args, token, state.Id)
+	if _, err = tx.Exec("UPDATE chats SET "+strings.Join(fields, ", ")+", timestamp = CURRENT_TIMESTAMP WHERE server_token = ? AND chat_id = ?", args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Find returns a page of the chat list and the total of chats matching the query
+func (source QpDataChatsSql) Find(token string, query QpStoredChatsQuery) ([]*QpStoredChat, int, error) {
+	query.Normalize()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, 0, fmt.Errorf("server token is required")
+	}
+
+	where := " FROM chats WHERE server_token = ?"
+	args := []any{token}
+	if query.Archived != nil {
+		where += " AND archived = ?"
+		args = append(args, *query.Archived)
+	}
+
+	var total int
+	if err := source.db.Get(&total, "SELECT COUNT(*)"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	order := "DESC"
+	if query.Ascending {
+		order = "ASC"
+	}
+
+	var orderBy string
+	switch query.Sort {
+	case "unread":
+		orderBy = fmt.Sprintf("unread %s, last_message_at DESC", order)
+	case "title":
+		orderBy = fmt.Sprintf("title COLLATE NOCASE %s, chat_id %s", order, order)
+	default:
+		orderBy = fmt.Sprintf("pinned DESC, last_message_at %s, chat_id %s", order, order)
+	}
+
+	statement := "SELECT " + storedChatColumns + where + " ORDER BY " + orderBy + " LIMIT ? OFFSET ?"
+	args = append(args, query.Limit, query.Offset)
+
+	chats := []*QpStoredChat{}
+	if err := source.db.Select(&chats, statement, args...); err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	for _, chat := range chats {
+		chat.Fill(now)
+	}
+
+	return chats, total, nil
+}
+
+// DeleteByChats removes the given chats from the chat list of a server
+func (source QpDataChatsSql) DeleteByChats(token string, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	statement, args, err := sqlx.In("DELETE FROM chats WHERE server_token = ? AND chat_id IN (?)", token, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := source.db.Exec(source.db.Rebind(statement), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// DeleteByServer removes the chat list of a server
+func (source QpDataChatsSql) DeleteByServer(token string) error {
+	_, err := source.db.Exec("DELETE FROM chats WHERE server_token = ?", token)
+	return err
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+func newTestChatsStore(t *testing.T) QpDataChatsSql {
+	t.Helper()
+
+	messages := newTestMessagesStore(t)
+	schema, ok := FileToString("../migrations/202610191200_chats.up.sql")
+	if !ok {
+		t.Fatalf("missing chats migration")
+	}
+	if _, err := messages.db.Exec(schema); err != nil {
+		t.Fatalf("chats migration: %v", err)
+	}
+
+	return QpDataChatsSql{db: messages.db}
+}
+
+func TestQpDataChatsSqlTouchAndState(t *testing.T) {
+	store := newTestChatsStore(t)
+
+	first := whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net", Title: "Alice"}
+	second := whatsapp.WhatsappChat{Id: "5521888888888@s.whatsapp.net", Title: "bob"}
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	messages := []*whatsapp.WhatsappMessage{
+		{Id: "msg-1", Chat: first, Type: whatsapp.TextMessageType, Text: "hello", Timestamp: base},
+		{Id: "msg-2", Chat: first, Type: whatsapp.TextMessageType, Text: "are you there?", Timestamp: base.Add(time.Minute)},
+		{Id: "msg-3", Chat: second, Type: whatsapp.TextMessageType, Text: "hi", Timestamp: base.Add(2 * time.Minute)},
+		{Id: "old", Chat: second, Type: whatsapp.TextMessageType, Text: "older", Timestamp: base.Add(-time.Hour), FromHistory: true},
+		{Id: "react", Chat: second, Type: whatsapp.TextMessageType, Text: "👍", InReaction: true, InReply: "msg-3", Timestamp: base.Add(3 * time.Minute)},
+	}
+	for _, msg := range messages {
+		if err := store.Touch("token-1", msg); err != nil {
+			t.Fatalf("touch %s: %v", msg.Id, err)
+		}
+	}
+
+	chats, total, err := store.Find("token-1", QpStoredChatsQuery{})
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if total != 2 || len(chats) != 2 || chats[0].Id != second.Id {
+		t.Fatalf("unexpected chats: total %d, %d chats", total, len(chats))
+	}
+	if chats[0].LastMessage == nil || chats[0].LastMessage.Id != "MSG-3" || chats[0].Unread != 1 {
+		t.Fatalf("unexpected newest chat: %+v", chats[0])
+	}
+	if chats[1].Unread != 2 || chats[1].LastMessage.Text != "are you there?" {
+		t.Fatalf("unexpected oldest chat: %+v", chats[1])
+	}
+
+	reply := &whatsapp.WhatsappMessage{Id: "msg-4", Chat: first, Type: whatsapp.TextMessageType, Text: "yes", FromMe: true, Timestamp: base.Add(4 * time.Minute)}
+	if err := store.Touch("token-1", reply); err != nil {
+		t.Fatalf("touch reply: %v", err)
+	}
+
+	pinned, until := true, int64(-1)
+	if err := store.UpdateState("token-1", &whatsapp.WhatsappChatState{Id: second.Id, Pinned: &pinned, MutedUntil: &until}); err != nil {
+		t.Fatalf("update state: %v", err)
+	}
+
+	chats, _, err = store.Find("token-1", QpStoredChatsQuery{})
+	if err != nil {
+		t.Fatalf("find after state: %v", err)
+	}
+	if chats[0].Id != second.Id || !chats[0].Pinned || !chats[0].Muted {
+		t.Fatalf("expected pinned and muted chat first: %+v", chats[0])
+	}
+	if chats[1].Unread != 0 || !chats[1].LastMessage.FromMe {
+		t.Fatalf("expected chat read after own message: %+v", chats[1])
+	}
+
+	chats, _, err = store.Find("token-1", QpStoredChatsQuery{Sort: "title", Ascending: true, Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("find by title: %v", err)
+	}
+	if len(chats) != 1 || chats[0].Title != "bob" {
+		t.Fatalf("unexpected title page: %+v", chats)
+	}
+
+	if err := store.UpdateState("token-1", &whatsapp.WhatsappChatState{Id: second.Id, Deleted: true}); err != nil {
+		t.Fatalf("delete chat: %v", err)
+	}
+
+	archived := true
+	_, total, err = store.Find("token-1", QpStoredChatsQuery{Archived: &archived})
+	if err != nil || total != 0 {
+		t.Fatalf("expected no archived chats: %d, %v", total, err)
+	}
+
+	_, total, err = store.Find("token-1", QpStoredChatsQuery{})
+	if err != nil || total != 1 {
+		t.Fatalf("expected one chat after delete: %d, %v", total, err)
+	}
+}
@@ -8,6 +8,7 @@ type QpDataConversationLabelsInterface interface {
 	Delete(id int64, user string) error
 	Assign(serverToken string, chatID string, labelID int64, user string) (uint, error)
 	Remove(serverToken string, chatID string, labelID int64, user string) (uint, error)
+	RemoveByChats(serverToken string, chatIDs []string) (uint, error)
 	FindConversationLabels(serverToken string, chatID string, user string) ([]*QpConversationLabel, error)
 	FindConversationLabelsMap(serverToken string, user string, chatIDs []string) (map[string][]*QpConversationLabel, error)
 }
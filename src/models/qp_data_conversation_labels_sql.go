@@ -221,6 +221,34 @@ func (source QpDataConversationLabelSql) Remove(serverToken string, chatID strin
 	return uint(affected), nil
 }
 
+// RemoveByChats removes every label link of the given chats, labels themselves are kept
+func (source QpDataConversationLabelSql) RemoveByChats(serverToken string, chatIDs []string) (uint, error) {
+	serverToken = strings.TrimSpace(serverToken)
+	if serverToken == "" {
+		return 0, fmt.Errorf("server token is required")
+	}
+	if len(chatIDs) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := sqlx.In("DELETE FROM conversation_label_links WHERE server_token = ? AND chat_id IN (?)", serverToken, chatIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := source.db.Exec(source.db.Rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(affected), nil
+}
+
 func (source QpDataConversationLabelSql) FindConversationLabels(serverToken string, chatID string, user string) ([]*QpConversationLabel, error) {
 	serverToken = strings.TrimSpace(serverToken)
 	chatID = strings.TrimSpace(chatID)
@@ -0,0 +1,18 @@
+package models
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpDataMessagesInterface interface {
+	Store(token string, msg *whatsapp.WhatsappMessage) error
+	UpdateStatus(token string, id string, status whatsapp.WhatsappMessageStatus) error
+	FindByChat(token string, query QpStoredMessagesQuery) ([]*QpStoredMessage, string, error)
+	CountByChat(token string, query QpStoredMessagesQuery) (int, error)
+	FindAnchor(token string, chatId string, id string) (*QpStoredMessage, error)
+	Search(token string, query QpMessagesSearchQuery) ([]*QpMessageSearchHit, error)
+	DeleteByServer(token string) error
+	DeleteByContact(token string, ids []string) (messages int, reactions int, err error)
+	FindStorageKeysByContact(token string, ids []string) ([]string, error)
+	FindStorageKeys(keys []string) ([]string, error)
+}
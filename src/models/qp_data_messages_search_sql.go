@@ -0,0 +1,166 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// rank function of search matches, registered on the connection running the search
+const messagesSearchRankFunction = "qp_search_rank"
+
+// indexStoredMessage refreshes the full text index of a stored message, revoked messages are not searchable
+func indexStoredMessage(tx *sqlx.Tx, token string, id string) error {
+	var row struct {
+		RowId   int64 `db:"rowid"`
+		Revoked bool  `db:"revoked"`
+	}
+
+	err := tx.Get(&row, "SELECT rowid, revoked FROM messages WHERE server_token = ? AND id = ?", token, id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec("DELETE FROM messages_fts WHERE docid = ?", row.RowId); err != nil {
+		return err
+	}
+
+	if row.Revoked {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO messages_fts (docid, text, filename, contact)
+		SELECT m.rowid, m.text, COALESCE(a.filename, ''), TRIM(m.participant_title || ' ' || m.chat_title)
+		FROM messages m
+		LEFT JOIN message_attachments a ON a.server_token = m.server_token AND a.message_id = m.id
+		WHERE m.rowid = ?
+	`, row.RowId)
+	return err
+}
+
+// Search returns stored messages matching the query text, ranked by relevance
+func (source QpDataMessagesSql) Search(token string, query QpMessagesSearchQuery) ([]*QpMessageSearchHit, error) {
+	query.Normalize()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("server token is required")
+	}
+
+	match := GetMessagesSearchMatch(query.Text)
+	if len(match) == 0 {
+		return nil, fmt.Errorf("search text is required")
+	}
+
+	columns := []string{}
+	for _, column := range strings.Split(storedMessageColumns, ", ") {
+		columns = append(columns, "m."+column)
+	}
+
+	statement := `SELECT ` + strings.Join(columns, ", ") + `,
+		snippet(messages_fts, '[', ']', '...', -1, 12) AS snippet,
+		` + messagesSearchRankFunction + `(matchinfo(messages_fts, 'pcx')) AS rank
+		FROM messages_fts
+		INNER JOIN messages m ON m.rowid = messages_fts.docid
+		WHERE messages_fts MATCH ? AND m.server_token = ?`
+	args := []any{match, token}
+
+	if query.ChatId != "" {
+		statement += " AND m.chat_id = ?"
+		args = append(args, query.ChatId)
+	}
+	if !query.From.IsZero() {
+		statement += " AND m.sent_at >= ?"
+		args = append(args, query.From.UnixMilli())
+	}
+	if !query.To.IsZero() {
+		statement += " AND m.sent_at <= ?"
+		args = append(args, query.To.UnixMilli())
+	}
+	if len(query.Types) > 0 {
+		statement += " AND m.type IN (?)"
+		args = append(args, query.Types)
+	}
+
+	// the whole match set is ranked, newest first on ties, before paging
+	statement += " ORDER BY rank DESC, m.sent_at DESC LIMIT ? OFFSET ?"
+	args = append(args, query.Limit, query.Offset)
+
+	statement, args, err := sqlx.In(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	conn, err := source.getSearchConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type row struct {
+		QpStoredMessage
+		Snippet string  `db:"snippet"`
+		Rank    float64 `db:"rank"`
+	}
+
+	rows := []row{}
+	err = conn.SelectContext(ctx, &rows, source.db.Rebind(statement), args...)
+
+	// released before filling details, which needs a connection of the pool
+	conn.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*QpMessageSearchHit, 0, len(rows))
+	for i := range rows {
+		message := rows[i].QpStoredMessage
+		hits = append(hits, &QpMessageSearchHit{
+			Message: &message,
+			Snippet: rows[i].Snippet,
+			Rank:    rows[i].Rank,
+		})
+	}
+
+	messages := make([]*QpStoredMessage, 0, len(hits))
+	for _, hit := range hits {
+		messages = append(messages, hit.Message)
+	}
+
+	if err := source.fillDetails(token, messages); err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// getSearchConn returns a connection with the rank function of search matches registered,
+// sqlite functions belong to connections, not to the pool
+func (source QpDataMessagesSql) getSearchConn(ctx context.Context) (*sqlx.Conn, error) {
+	conn, err := source.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.Raw(func(driverConn any) error {
+		sqliteConn, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("message search requires a sqlite database")
+		}
+		return sqliteConn.RegisterFunc(messagesSearchRankFunction, GetMessagesSearchRank, true)
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,468 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpDataMessagesSql struct {
+	db *sqlx.DB
+}
+
+const storedMessageColumns = "server_token, id, chat_id, chat_title, participant_id, participant_title, type, text, fromme, fromhistory, inreply, status, edited, revoked, sent_at, timestamp"
+
+// Store persists a message, reactions and edits are applied over the referenced message
+func (source QpDataMessagesSql) Store(token string, msg *whatsapp.WhatsappMessage) (err error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("server token is required")
+	}
+	if msg == nil || len(msg.Id) == 0 {
+		return fmt.Errorf("message id is required")
+	}
+
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	stored := NewQpStoredMessage(token, msg)
+	switch {
+	case msg.InReaction:
+		err = storeReaction(tx, stored, GetStoredMessageSender(msg))
+	case msg.Type == whatsapp.RevokeMessageType:
+		err = storeRevoke(tx, stored)
+	case msg.Edited:
+		err = storeEdit(tx, stored)
+	default:
+		err = storeMessage(tx, stored)
+	}
+	if err != nil {
+		return err
+	}
+
+	// reactions are not searchable, they only reference the reacted message
+	if !msg.InReaction {
+		if err = indexStoredMessage(tx, token, stored.Id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetStoredMessageSender returns who sent the message, used as reactions key
+func GetStoredMessageSender(msg *whatsapp.WhatsappMessage) string {
+	if msg.FromMe {
+		return msg.Wid
+	}
+	if msg.Participant != nil && len(msg.Participant.Id) > 0 {
+		return msg.Participant.Id
+	}
+	return msg.Chat.Id
+}
+
+func storeMessage(tx *sqlx.Tx, stored *QpStoredMessage) error {
+	_, err := tx.NamedExec(`
+		INSERT INTO messages (server_token, id, chat_id, chat_title, participant_id, participant_title, type, text, fromme, fromhistory, inreply, status, edited, revoked, sent_at)
+		VALUES (:server_token, :id, :chat_id, :chat_title, :participant_id, :participant_title, :type, :text, :fromme, :fromhistory, :inreply, :status, :edited, :revoked, :sent_at)
+		ON CONFLICT (server_token, id) DO UPDATE SET
+			chat_title = CASE WHEN excluded.chat_title = '' THEN messages.chat_title ELSE excluded.chat_title END,
+			participant_title = excluded.participant_title,
+			type = excluded.type,
+			text = CASE WHEN messages.edited THEN messages.text ELSE excluded.text END,
+			status = CASE WHEN excluded.status = '' THEN messages.status ELSE excluded.status END
+	`, stored)
+	if err != nil {
+		return err
+	}
+
+	if stored.Attachment == nil {
+		return nil
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO message_attachments (server_token, message_id, mime, filename, filelength, seconds, checksum, url, storage_key)
+		VALUES (:server_token, :message_id, :mime, :filename, :filelength, :seconds, :checksum, :url, :storage_key)
+		ON CONFLICT (server_token, message_id) DO UPDATE SET
+			mime = excluded.mime,
+			filename = excluded.filename,
+			filelength = excluded.filelength,
+			seconds = excluded.seconds,
+			checksum = excluded.checksum,
+			url = CASE WHEN excluded.url = '' THEN message_attachments.url ELSE excluded.url END,
+			storage_key = CASE WHEN excluded.storage_key = '' THEN message_attachments.storage_key ELSE excluded.storage_key END
+	`, stored.Attachment)
+	return err
+}
+
+// reactions reference the reacted message at in reply, empty text removes it
+func storeReaction(tx *sqlx.Tx, stored *QpStoredMessage, sender string) error {
+	if len(stored.InReply) == 0 || len(sender) == 0 {
+		return nil
+	}
+
+	if len(stored.Text) == 0 {
+		_, err := tx.Exec("DELETE FROM message_reactions WHERE server_token = ? AND message_id = ? AND participant_id = ?", stored.ServerToken, stored.InReply, sender)
+		return err
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO message_reactions (server_token, message_id, participant_id, reaction, sent_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (server_token, message_id, participant_id) DO UPDATE SET
+			reaction = excluded.reaction,
+			sent_at = excluded.sent_at
+	`, stored.ServerToken, stored.InReply, sender, stored.Text, stored.SentAt)
+	return err
+}
+
+// revokes keep the original row, marking it as revoked
+func storeRevoke(tx *sqlx.Tx, stored *QpStoredMessage) error {
+	result, err := tx.Exec("UPDATE messages SET revoked = TRUE WHERE server_token = ? AND id = ?", stored.ServerToken, stored.Id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	return storeMessage(tx, stored)
+}
+
+// edits keep the previous text on message edits
+func storeEdit(tx *sqlx.Tx, stored *QpStoredMessage) error {
+	var previous string
+	err := tx.Get(&previous, "SELECT text FROM messages WHERE server_token = ? AND id = ?", stored.ServerToken, stored.Id)
+	if err == sql.ErrNoRows {
+		return storeMessage(tx, stored)
+	}
+	if err != nil {
+		return err
+	}
+
+	if previous == stored.Text {
+		return nil
+	}
+
+	_, err = tx.Exec("INSERT INTO message_edits (server_token, message_id, text, sent_at) VALUES (?, ?, ?, ?)", stored.ServerToken, stored.Id, previous, time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE messages SET text = ?, edited = TRUE WHERE server_token = ? AND id = ?", stored.Text, stored.ServerToken, stored.Id)
+	return err
+}
+
+func (source QpDataMessagesSql) UpdateStatus(token string, id string, status whatsapp.WhatsappMessageStatus) error {
+	_, err := source.db.Exec("UPDATE messages SET status = ? WHERE server_token = ? AND id = ?", string(status), strings.TrimSpace(token), strings.ToUpper(id))
+	return err
+}
+
+// FindByChat returns a page of messages from a chat and the cursor for the next page, empty when there is no more
+func (source QpDataMessagesSql) FindByChat(token string, query QpStoredMessagesQuery) ([]*QpStoredMessage, string, error) {
+	query.Normalize()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, "", fmt.Errorf("server token is required")
+	}
+	if query.ChatId == "" {
+		return nil, "", fmt.Errorf("chatid is required")
+	}
+
+	statement := "SELECT " + storedMessageColumns + " FROM messages WHERE server_token = ? AND chat_id = ?"
+	args := []any{token, query.ChatId}
+
+	if !query.Before.IsZero() {
+		statement += " AND sent_at < ?"
+		args = append(args, query.Before.UnixMilli())
+	}
+	if !query.After.IsZero() {
+		statement += " AND sent_at > ?"
+		args = append(args, query.After.UnixMilli())
+	}
+
+	comparer, order := "<", "DESC"
+	if query.Ascending {
+		comparer, order = ">", "ASC"
+	}
+
+	if len(query.Cursor) > 0 {
+		sentAt, id, err := DecodeStoredMessageCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		statement += fmt.Sprintf(" AND (sent_at %s ? OR (sent_at = ? AND id %s ?))", comparer, comparer)
+		args = append(args, sentAt, sentAt, id)
+	}
+
+	if len(query.Types) > 0 {
+		statement += " AND type IN (?)"
+		args = append(args, query.Types)
+	}
+
+	statement += fmt.Sprintf(" ORDER BY sent_at %s, id %s LIMIT ?", order, order)
+	args = append(args, query.Limit+1)
+
+	statement, args, err := sqlx.In(statement, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	messages := []*QpStoredMessage{}
+	if err := source.db.Select(&messages, source.db.Rebind(statement), args...); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(messages) > query.Limit {
+		messages = messages[:query.Limit]
+		next = EncodeStoredMessageCursor(messages[len(messages)-1])
+	}
+
+	if err := source.fillDetails(token, messages); err != nil {
+		return nil, "", err
+	}
+
+	return messages, next, nil
+}
+
+// CountByChat returns how many messages of a chat match the query date range and types, cursor and limit are ignored
+func (source QpDataMessagesSql) CountByChat(token string, query QpStoredMessagesQuery) (int, error) {
+	query.Normalize()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return 0, fmt.Errorf("server token is required")
+	}
+	if query.ChatId == "" {
+		return 0, fmt.Errorf("chatid is required")
+	}
+
+	statement := "SELECT COUNT(*) FROM messages WHERE server_token = ? AND chat_id = ?"
+	args := []any{token, query.ChatId}
+
+	if !query.Before.IsZero() {
+		statement += " AND sent_at < ?"
+		args = append(args, query.Before.UnixMilli())
+	}
+	if !query.After.IsZero() {
+		statement += " AND sent_at > ?"
+		args = append(args, query.After.UnixMilli())
+	}
+
+	if len(query.Types) > 0 {
+		statement += " AND type IN (?)"
+		args = append(args, query.Types)
+	}
+
+	statement, args, err := sqlx.In(statement, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = source.db.Get(&count, source.db.Rebind(statement), args...)
+	return count, err
+}
+
+// FindAnchor returns the given message, or the oldest stored message of the chat when id is empty, nil when not found
+func (source QpDataMessagesSql) FindAnchor(token string, chatId string, id string) (*QpStoredMessage, error) {
+	statement := "SELECT " + storedMessageColumns + " FROM messages WHERE server_token = ? AND chat_id = ?"
+	args := []any{strings.TrimSpace(token), chatId}
+	if len(id) > 0 {
+		statement += " AND id = ?"
+		args = append(args, strings.ToUpper(id))
+	}
+	statement += " ORDER BY sent_at ASC, id ASC LIMIT 1"
+
+	message := &QpStoredMessage{}
+	err := source.db.Get(message, statement, args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message.Timestamp = time.UnixMilli(message.SentAt).UTC()
+	return message, nil
+}
+
+// fillDetails loads attachments, reactions and edits of a page of messages
+func (source QpDataMessagesSql) fillDetails(token string, messages []*QpStoredMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	indexed := make(map[string]*QpStoredMessage, len(messages))
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		message.Timestamp = time.UnixMilli(message.SentAt).UTC()
+		indexed[message.Id] = message
+		ids = append(ids, message.Id)
+	}
+
+	attachments := []*QpStoredAttachment{}
+	if err := source.selectIn(&attachments, "SELECT server_token, message_id, mime, filename, filelength, seconds, checksum, url, storage_key FROM message_attachments WHERE server_token = ? AND message_id IN (?)", token, ids); err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if message, ok := indexed[attachment.MessageId]; ok {
+			message.Attachment = attachment
+		}
+	}
+
+	reactions := []*QpStoredReaction{}
+	if err := source.selectIn(&reactions, "SELECT server_token, message_id, participant_id, reaction, sent_at FROM message_reactions WHERE server_token = ? AND message_id IN (?) ORDER BY sent_at ASC", token, ids); err != nil {
+		return err
+	}
+	for _, reaction := range reactions {
+		if message, ok := indexed[reaction.MessageId]; ok {
+			message.Reactions = append(message.Reactions, reaction)
+		}
+	}
+
+	edits := []*QpStoredEdit{}
+	if err := source.selectIn(&edits, "SELECT id, server_token, message_id, text, sent_at FROM message_edits WHERE server_token = ? AND message_id IN (?) ORDER BY id ASC", token, ids); err != nil {
+		return err
+	}
+	for _, edit := range edits {
+		if message, ok := indexed[edit.MessageId]; ok {
+			message.Edits = append(message.Edits, edit)
+		}
+	}
+
+	return nil
+}
+
+func (source QpDataMessagesSql) selectIn(dest any, statement string, args ...any) error {
+	statement, args, err := sqlx.In(statement, args...)
+	if err != nil {
+		return err
+	}
+	return source.db.Select(dest, source.db.Rebind(statement), args...)
+}
+
+// DeleteByContact removes messages of chats with the contacts or sent by them on groups,
+// with attachments, edits and search index, and every reaction of them
+func (source QpDataMessagesSql) DeleteByContact(token string, ids []string) (messages int, reactions int, err error) {
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	exec := func(statement string, args ...any) (int, error) {
+		statement, args, err := sqlx.In(statement, args...)
+		if err != nil {
+			return 0, err
+		}
+
+		result, err := tx.Exec(tx.Rebind(statement), args...)
+		if err != nil {
+			return 0, err
+		}
+
+		affected, err := result.RowsAffected()
+		return int(affected), err
+	}
+
+	const matched = "SELECT %s FROM messages WHERE server_token = ? AND (chat_id IN (?) OR participant_id IN (?))"
+	if _, err = exec("DELETE FROM messages_fts WHERE docid IN ("+fmt.Sprintf(matched, "rowid")+")", token, ids, ids); err != nil {
+		return 0, 0, err
+	}
+
+	for _, table := range []string{"message_edits", "message_attachments", "message_reactions"} {
+		if _, err = exec("DELETE FROM "+table+" WHERE server_token = ? AND message_id IN ("+fmt.Sprintf(matched, "id")+")", token, token, ids, ids); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if reactions, err = exec("DELETE FROM message_reactions WHERE server_token = ? AND participant_id IN (?)", token, ids); err != nil {
+		return 0, 0, err
+	}
+
+	if messages, err = exec("DELETE FROM messages WHERE server_token = ? AND (chat_id IN (?) OR participant_id IN (?))", token, ids, ids); err != nil {
+		return 0, 0, err
+	}
+
+	return messages, reactions, tx.Commit()
+}
+
+// FindStorageKeysByContact returns the object storage keys of attachments of messages
+// of chats with the contacts or sent by them on groups
+func (source QpDataMessagesSql) FindStorageKeysByContact(token string, ids []string) ([]string, error) {
+	keys := []string{}
+	if len(ids) == 0 {
+		return keys, nil
+	}
+
+	err := source.selectIn(&keys, `
+		SELECT DISTINCT a.storage_key FROM message_attachments a
+		INNER JOIN messages m ON m.server_token = a.server_token AND m.id = a.message_id
+		WHERE a.server_token = ? AND a.storage_key <> '' AND (m.chat_id IN (?) OR m.participant_id IN (?))
+	`, token, ids, ids)
+	return keys, err
+}
+
+// FindStorageKeys returns the given object storage keys still referenced by attachments of any server
+func (source QpDataMessagesSql) FindStorageKeys(keys []string) ([]string, error) {
+	found := []string{}
+	if len(keys) == 0 {
+		return found, nil
+	}
+
+	err := source.selectIn(&found, "SELECT DISTINCT storage_key FROM message_attachments WHERE storage_key IN (?)", keys)
+	return found, err
+}
+
+// DeleteByServer removes every stored message of a server
+func (source QpDataMessagesSql) DeleteByServer(token string) (err error) {
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec("DELETE FROM messages_fts WHERE docid IN (SELECT rowid FROM messages WHERE server_token = ?)", token); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"message_edits", "message_reactions", "message_attachments", "messages"} {
+		if _, err = tx.Exec("DELETE FROM "+table+" WHERE server_token = ?", token); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
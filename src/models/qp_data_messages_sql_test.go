@@ -0,0 +1,266 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+func newTestMessagesStore(t *testing.T) QpDataMessagesSql {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	for _, migration := range []string{"202610191000_message_store", "202610191100_message_search", "202610191300_message_attachments_storage_key"} {
+		schema, ok := FileToString("../migrations/" + migration + ".up.sql")
+		if !ok {
+			t.Fatalf("missing migration: %s", migration)
+		}
+		if _, err := db.Exec(schema); err != nil {
+			t.Fatalf("migration %s: %v", migration, err)
+		}
+	}
+
+	return QpDataMessagesSql{db: db}
+}
+
+func TestQpDataMessagesSqlFindByChatPagination(t *testing.T) {
+	store := newTestMessagesStore(t)
+
+	chat := whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net"}
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i, id := range []string{"msg-1", "msg-2", "msg-3"} {
+		msg := &whatsapp.WhatsappMessage{Id: id, Chat: chat, Type: whatsapp.TextMessageType, Text: id, Timestamp: base.Add(time.Duration(i) * time.Minute)}
+		if err := store.Store("token-1", msg); err != nil {
+			t.Fatalf("store %s: %v", id, err)
+		}
+	}
+
+	image := &whatsapp.WhatsappMessage{Id: "msg-4", Chat: chat, Type: whatsapp.ImageMessageType, Timestamp: base.Add(3 * time.Minute),
+		Attachment: &whatsapp.WhatsappAttachment{Mimetype: "image/jpeg", FileLength: 10}}
+	if err := store.Store("token-1", image); err != nil {
+		t.Fatalf("store image: %v", err)
+	}
+
+	page, next, err := store.FindByChat("token-1", QpStoredMessagesQuery{ChatId: chat.Id, Limit: 2})
+	if err != nil {
+		t.Fatalf("find first page: %v", err)
+	}
+	if len(page) != 2 || page[0].Id != "MSG-4" || page[1].Id != "MSG-3" || len(next) == 0 {
+		t.Fatalf("unexpected first page: %d messages, next: %q", len(page), next)
+	}
+	if page[0].Attachment == nil || page[0].Attachment.Mimetype != "image/jpeg" {
+		t.Errorf("expected attachment metadata on image message")
+	}
+
+	page, next, err = store.FindByChat("token-1", QpStoredMessagesQuery{ChatId: chat.Id, Limit: 2, Cursor: next})
+	if err != nil {
+		t.Fatalf("find second page: %v", err)
+	}
+	if len(page) != 2 || page[0].Id != "MSG-2" || page[1].Id != "MSG-1" || len(next) != 0 {
+		t.Fatalf("unexpected second page: %d messages, next: %q", len(page), next)
+	}
+
+	page, _, err = store.FindByChat("token-1", QpStoredMessagesQuery{ChatId: chat.Id, Types: []string{"image"}})
+	if err != nil {
+		t.Fatalf("find by type: %v", err)
+	}
+	if len(page) != 1 || page[0].Id != "MSG-4" {
+		t.Errorf("expected only the image message, got %d", len(page))
+	}
+
+	page, _, err = store.FindByChat("token-1", QpStoredMessagesQuery{ChatId: chat.Id, After: base.Add(30 * time.Second), Before: base.Add(150 * time.Second)})
+	if err != nil {
+		t.Fatalf("find by timestamps: %v", err)
+	}
+	if len(page) != 2 {
+		t.Errorf("expected 2 messages between timestamps, got %d", len(page))
+	}
+
+	count, err := store.CountByChat("token-1", QpStoredMessagesQuery{ChatId: chat.Id, After: base.Add(30 * time.Second)})
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 messages after timestamp, got %d, %v", count, err)
+	}
+}
+
+func TestQpDataMessagesSqlReactionsAndEdits(t *testing.T) {
+	store := newTestMessagesStore(t)
+
+	chat := whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net"}
+	msg := &whatsapp.WhatsappMessage{Id: "msg-1", Chat: chat, Type: whatsapp.TextMessageType, Text: "original", Timestamp: time.Now()}
+	if err := store.Store("token-1", msg); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	edit := &whatsapp.WhatsappMessage{Id: "msg-1", Chat: chat, Type: whatsapp.TextMessageType, Text: "edited", Edited: true, Timestamp: time.Now()}
+	if err := store.Store("token-1", edit); err != nil {
+		t.Fatalf("store edit: %v", err)
+	}
+
+	reaction := &whatsapp.WhatsappMessage{Id: "reaction-1", Chat: chat, Type: whatsapp.TextMessageType, Text: "👍", InReaction: true, InReply: "msg-1", Timestamp: time.Now()}
+	if err := store.Store("token-1", reaction); err != nil {
+		t.Fatalf("store reaction: %v", err)
+	}
+
+	page, _, err := store.FindByChat("token-1", QpStoredMessagesQuery{ChatId: chat.Id})
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(page) != 1 {
+		t.Fatalf("reactions should not be stored as messages, got %d", len(page))
+	}
+
+	stored := page[0]
+	if stored.Text != "edited" || !stored.Edited || len(stored.Edits) != 1 || stored.Edits[0].Text != "original" {
+		t.Errorf("unexpected edit state: %+v", stored)
+	}
+	if len(stored.Reactions) != 1 || stored.Reactions[0].Reaction != "👍" {
+		t.Errorf("expected one reaction, got %d", len(stored.Reactions))
+	}
+
+	reaction.Text = ""
+	if err := store.Store("token-1", reaction); err != nil {
+		t.Fatalf("remove reaction: %v", err)
+	}
+
+	page, _, _ = store.FindByChat("token-1", QpStoredMessagesQuery{ChatId: chat.Id})
+	if len(page) != 1 || len(page[0].Reactions) != 0 {
+		t.Errorf("expected reaction to be removed")
+	}
+}
+
+func TestQpDataMessagesSqlSearch(t *testing.T) {
+	store := newTestMessagesStore(t)
+
+	chat := whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net", Title: "Customer"}
+	messages := []*whatsapp.WhatsappMessage{
+		{Id: "msg-1", Chat: chat, Type: whatsapp.TextMessageType, Text: "Good morning", Timestamp: time.Now().Add(-3 * time.Minute)},
+		{Id: "msg-2", Chat: chat, Type: whatsapp.TextMessageType, Text: "Here is the invoice number 4242, invoice attached", Timestamp: time.Now().Add(-2 * time.Minute)},
+		{Id: "msg-3", Chat: chat, Type: whatsapp.DocumentMessageType, Text: "Número da nota", Timestamp: time.Now().Add(-time.Minute),
+			Attachment: &whatsapp.WhatsappAttachment{Mimetype: "application/pdf", FileName: "invoice-4242.pdf"}},
+	}
+	for _, msg := range messages {
+		if err := store.Store("token-1", msg); err != nil {
+			t.Fatalf("store %s: %v", msg.Id, err)
+		}
+	}
+
+	hits, err := store.Search("token-1", QpMessagesSearchQuery{Text: "invoice"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Message.Id != "MSG-2" || len(hits[0].Snippet) == 0 {
+		t.Fatalf("unexpected hits: %d", len(hits))
+	}
+
+	hits, _ = store.Search("token-1", QpMessagesSearchQuery{Text: "numero", Types: []string{"document"}})
+	if len(hits) != 1 || hits[0].Message.Attachment == nil {
+		t.Errorf("expected diacritics insensitive match on document")
+	}
+
+	hits, _ = store.Search("token-1", QpMessagesSearchQuery{Text: "custom"})
+	if len(hits) != 3 {
+		t.Errorf("expected contact name matches on every message, got %d", len(hits))
+	}
+
+	revoke := &whatsapp.WhatsappMessage{Id: "msg-2", Chat: chat, Type: whatsapp.RevokeMessageType, Timestamp: time.Now()}
+	if err := store.Store("token-1", revoke); err != nil {
+		t.Fatalf("store revoke: %v", err)
+	}
+
+	hits, _ = store.Search("token-1", QpMessagesSearchQuery{Text: "invoice 4242"})
+	if len(hits) != 1 || hits[0].Message.Id != "MSG-3" {
+		t.Errorf("revoked messages should not be searchable")
+	}
+}
+
+// TestQpDataMessagesSqlSearchRanksAllMatches verifies the best match wins even when older
+// than many weaker matches, ranking happens before the limit
+func TestQpDataMessagesSqlSearchRanksAllMatches(t *testing.T) {
+	store := newTestMessagesStore(t)
+
+	chat := whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net"}
+	now := time.Now()
+	best := &whatsapp.WhatsappMessage{Id: "best", Chat: chat, Type: whatsapp.TextMessageType, Text: "refund refund refund", Timestamp: now.Add(-24 * time.Hour)}
+	if err := store.Store("token-1", best); err != nil {
+		t.Fatalf("store best: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		weak := &whatsapp.WhatsappMessage{Id: fmt.Sprintf("weak-%d", i), Chat: chat, Type: whatsapp.TextMessageType,
+			Text: "refund requested for the order, waiting for the manager to confirm the details", Timestamp: now.Add(time.Duration(i) * time.Second)}
+		if err := store.Store("token-1", weak); err != nil {
+			t.Fatalf("store weak: %v", err)
+		}
+	}
+
+	hits, err := store.Search("token-1", QpMessagesSearchQuery{Text: "refund", Limit: 2})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Message.Id != "BEST" || hits[0].Rank <= hits[1].Rank {
+		t.Fatalf("expected the older best match first, got: %+v", hits)
+	}
+
+	// next page follows the ranking, newest first between equal ranks
+	hits, _ = store.Search("token-1", QpMessagesSearchQuery{Text: "refund", Limit: 1, Offset: 1})
+	if len(hits) != 1 || hits[0].Message.Id != "WEAK-999" {
+		t.Errorf("unexpected second page: %+v", hits)
+	}
+}
+
+func TestQpDataMessagesSqlDeleteByContact(t *testing.T) {
+	store := newTestMessagesStore(t)
+
+	contact := whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net"}
+	other := whatsapp.WhatsappChat{Id: "5521888888888@s.whatsapp.net"}
+	group := whatsapp.WhatsappChat{Id: "120363000000000000@g.us"}
+
+	messages := []*whatsapp.WhatsappMessage{
+		{Id: "msg-1", Chat: contact, Type: whatsapp.TextMessageType, Text: "private", Timestamp: time.Now()},
+		{Id: "msg-2", Chat: group, Participant: &contact, Type: whatsapp.TextMessageType, Text: "from contact", Timestamp: time.Now()},
+		{Id: "msg-3", Chat: group, Participant: &other, Type: whatsapp.TextMessageType, Text: "from other", Timestamp: time.Now()},
+		{Id: "reaction-1", Chat: group, Participant: &contact, Type: whatsapp.TextMessageType, Text: "👍", InReaction: true, InReply: "msg-3", Timestamp: time.Now()},
+	}
+	for _, msg := range messages {
+		if err := store.Store("token-1", msg); err != nil {
+			t.Fatalf("store %s: %v", msg.Id, err)
+		}
+	}
+
+	if err := store.Store("token-2", &whatsapp.WhatsappMessage{Id: "msg-1", Chat: contact, Type: whatsapp.TextMessageType, Text: "other session", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("store other session: %v", err)
+	}
+
+	deleted, reactions, err := store.DeleteByContact("token-1", []string{contact.Id})
+	if err != nil {
+		t.Fatalf("delete by contact: %v", err)
+	}
+	if deleted != 2 || reactions != 1 {
+		t.Fatalf("expected 2 messages and 1 reaction deleted, got %d and %d", deleted, reactions)
+	}
+
+	page, _, _ := store.FindByChat("token-1", QpStoredMessagesQuery{ChatId: group.Id})
+	if len(page) != 1 || page[0].Id != "MSG-3" || len(page[0].Reactions) != 0 {
+		t.Errorf("expected only the other participant message, without the contact reaction")
+	}
+
+	hits, _ := store.Search("token-1", QpMessagesSearchQuery{Text: "private"})
+	if len(hits) != 0 {
+		t.Errorf("purged messages should not be searchable")
+	}
+
+	page, _, _ = store.FindByChat("token-2", QpStoredMessagesQuery{ChatId: contact.Id})
+	if len(page) != 1 {
+		t.Errorf("other sessions should be kept")
+	}
+}
@@ -28,6 +28,8 @@ type QpDatabase struct {
 	Servers            QpDataServersInterface
 	Dispatching        QpDataDispatchingInterface
 	ConversationLabels QpDataConversationLabelsInterface
+	Messages           QpDataMessagesInterface
+	Chats              QpDataChatsInterface
 }
 
 var (
@@ -72,6 +74,8 @@ func GetDatabase() *QpDatabase {
 	var iservers = QpDataServerSql{db}
 	var idispatching = QpDataServerDispatchingSql{db}
 	var iconversationlabels = QpDataConversationLabelSql{db}
+	var imessages = QpDataMessagesSql{db}
+	var ichats = QpDataChatsSql{db}
 
 	return &QpDatabase{
 		dbParameters,
@@ -79,7 +83,9 @@ func GetDatabase() *QpDatabase {
 		iusers,
 		iservers,
 		idispatching,
-		iconversationlabels}
+		iconversationlabels,
+		imessages,
+		ichats}
 }
 
 // NewQpDataUserSql creates a new QpDataUserSql instance with the given database connection
@@ -102,6 +108,16 @@ func NewQpDataConversationLabelSql(db *sqlx.DB) QpDataConversationLabelsInterfac
 	return QpDataConversationLabelSql{db}
 }
 
+// NewQpDataMessagesSql creates a new QpDataMessagesSql instance with the given database connection
+func NewQpDataMessagesSql(db *sqlx.DB) QpDataMessagesInterface {
+	return QpDataMessagesSql{db}
+}
+
+// NewQpDataChatsSql creates a new QpDataChatsSql instance with the given database connection
+func NewQpDataChatsSql(db *sqlx.DB) QpDataChatsInterface {
+	return QpDataChatsSql{db}
+}
+
 // MigrateToLatest updates the database to the latest schema
 func MigrateToLatest(logentry log.Logger) (err error) {
 	if !ENV.Migrate() {
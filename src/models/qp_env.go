@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings" // Certifique-se de que "strings" está importado
 
@@ -33,6 +34,7 @@ const (
 	ENV_TITLE                    = "APP_TITLE"          // application title for whatsapp id
 	ENV_REMOVEDIGIT9             = "REMOVEDIGIT9"       // deprecated: use NORMALIZE_BR_PHONE
 	ENV_NORMALIZE_BR_PHONE       = "NORMALIZE_BR_PHONE" // normalize Brazilian mobile phones (handles 8/9-digit ambiguity)
+	ENV_NORMALIZE_PHONE_REGIONS  = "NORMALIZE_PHONE_REGIONS" // comma separated regions whose phone country rules apply on send (BR, MX, AR)
 	ENV_SYNOPSISLENGTH           = "SYNOPSISLENGTH"
 	ENV_CACHELENGTH              = "CACHELENGTH" // cache max items
 	ENV_CACHEDAYS                = "CACHEDAYS"   // cache max days
@@ -198,6 +200,23 @@ func (*Environment) ShouldNormalizeBRPhone() bool {
 	return getEnvOrDefaultBool(ENV_REMOVEDIGIT9, false)
 }
 
+// PhoneNormalizationRegions returns the regions whose phone country rules are applied on send,
+// checking the registered variant of a phone via IsOnWhatsApp (cached per session).
+// Reads NORMALIZE_PHONE_REGIONS, BR is included when NORMALIZE_BR_PHONE is enabled.
+func (e *Environment) PhoneNormalizationRegions() (regions []string) {
+	for _, region := range strings.Split(getEnvOrDefaultString(ENV_NORMALIZE_PHONE_REGIONS, ""), ",") {
+		region = strings.ToUpper(strings.TrimSpace(region))
+		if len(region) > 0 && !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+
+	if e.ShouldNormalizeBRPhone() && !slices.Contains(regions, "BR") {
+		regions = append(regions, "BR")
+	}
+	return
+}
+
 // ShouldRemoveDigit9 is deprecated. Use ShouldNormalizeBRPhone instead.
 func (e *Environment) ShouldRemoveDigit9() bool {
 	return e.ShouldNormalizeBRPhone()
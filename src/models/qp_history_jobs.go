@@ -0,0 +1,283 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+const (
+	QpHistoryJobDefaultCount = 50
+	QpHistoryJobMaxCount     = 100
+
+	// pending requests without response from the phone after this are expired
+	QpHistoryJobTimeout = 2 * time.Minute
+
+	// finished jobs are kept for status queries during this time
+	QpHistoryJobRetention = 30 * time.Minute
+)
+
+type QpHistoryJobStatus string
+
+const (
+	QpHistoryJobPending   QpHistoryJobStatus = "pending"
+	QpHistoryJobCompleted QpHistoryJobStatus = "completed"
+	QpHistoryJobFailed    QpHistoryJobStatus = "failed"
+	QpHistoryJobExpired   QpHistoryJobStatus = "expired"
+)
+
+// QpHistoryJob is an on demand history request for older messages of a chat
+type QpHistoryJob struct {
+	Id     string             `json:"id"`
+	Token  string             `json:"-"`
+	ChatId string             `json:"chatid"`
+	Status QpHistoryJobStatus `json:"status"`
+
+	// messages requested before this message
+	Before whatsapp.WhatsappHistoryAnchor `json:"before"`
+	Count  int                            `json:"count"`
+
+	// messages received from the phone, and if older messages remain there
+	Received int  `json:"received"`
+	More     bool `json:"more"`
+
+	Error     string     `json:"error,omitempty"`
+	Created   time.Time  `json:"created"`
+	Completed *time.Time `json:"completed,omitempty"`
+}
+
+// QpHistoryJobs keeps on demand history jobs in memory, responses are matched by chat
+// because the phone does not echo the request id
+type QpHistoryJobs struct {
+	mutex sync.Mutex
+	items map[string]*QpHistoryJob
+}
+
+var HistoryJobs = &QpHistoryJobs{items: make(map[string]*QpHistoryJob)}
+
+// Add registers a new pending job, or returns the pending job of the same chat
+func (source *QpHistoryJobs) Add(job *QpHistoryJob) (*QpHistoryJob, bool) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.cleanup(time.Now())
+	if pending := source.pending(job.Token, job.ChatId); pending != nil {
+		return pending, false
+	}
+
+	source.items[job.Id] = job
+	return job, true
+}
+
+// Get returns a copy of a job of the server
+func (source *QpHistoryJobs) Get(token string, id string) (*QpHistoryJob, bool) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.cleanup(time.Now())
+	job, ok := source.items[id]
+	if !ok || job.Token != token {
+		return nil, false
+	}
+
+	copied := *job
+	return &copied, true
+}
+
+// Complete finishes the pending job of a chat with the history sync result
+func (source *QpHistoryJobs) Complete(token string, result *whatsapp.WhatsappHistoryChatSync) (*QpHistoryJob, bool) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	now := time.Now()
+	source.cleanup(now)
+
+	job := source.pending(token, result.ChatId)
+	if job == nil {
+		return nil, false
+	}
+
+	job.Status = QpHistoryJobCompleted
+	job.Received = result.Messages
+	job.More = result.More
+	job.Completed = &now
+
+	copied := *job
+	return &copied, true
+}
+
+// Fail finishes a job that could not be requested
+func (source *QpHistoryJobs) Fail(id string, err error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if job, ok := source.items[id]; ok {
+		now := time.Now()
+		job.Status = QpHistoryJobFailed
+		job.Error = err.Error()
+		job.Completed = &now
+	}
+}
+
+// DeleteByServer removes every job of a server
+func (source *QpHistoryJobs) DeleteByServer(token string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	for id, job := range source.items {
+		if job.Token == token {
+			delete(source.items, id)
+		}
+	}
+}
+
+func (source *QpHistoryJobs) pending(token string, chatId string) *QpHistoryJob {
+	for _, job := range source.items {
+		if job.Token == token && job.ChatId == chatId && job.Status == QpHistoryJobPending {
+			return job
+		}
+	}
+	return nil
+}
+
+// expires pending jobs without response and removes old finished ones, must hold the lock
+func (source *QpHistoryJobs) cleanup(now time.Time) {
+	for id, job := range source.items {
+		if job.Status == QpHistoryJobPending && now.Sub(job.Created) > QpHistoryJobTimeout {
+			expired := job.Created.Add(QpHistoryJobTimeout)
+			job.Status = QpHistoryJobExpired
+			job.Completed = &expired
+		}
+
+		if job.Completed != nil && now.Sub(*job.Completed) > QpHistoryJobRetention {
+			delete(source.items, id)
+		}
+	}
+}
+
+// RequestChatHistory asks the phone for older messages of a chat, before the given message or
+// the oldest known one, results are merged into cache and store and a completion event is dispatched
+func (server *QpWhatsappServer) RequestChatHistory(chatId string, messageId string, count int) (*QpHistoryJob, error) {
+	conn, err := server.GetValidConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	if count <= 0 {
+		count = QpHistoryJobDefaultCount
+	} else if count > QpHistoryJobMaxCount {
+		count = QpHistoryJobMaxCount
+	}
+
+	anchor, err := server.GetHistoryAnchor(chatId, messageId)
+	if err != nil {
+		return nil, err
+	}
+
+	job, created := HistoryJobs.Add(&QpHistoryJob{
+		Id:      uuid.New().String(),
+		Token:   server.Token,
+		ChatId:  chatId,
+		Status:  QpHistoryJobPending,
+		Before:  *anchor,
+		Count:   count,
+		Created: time.Now(),
+	})
+	if !created {
+		copied := *job
+		return &copied, nil
+	}
+
+	if err = conn.HistorySyncChat(*anchor, count); err != nil {
+		HistoryJobs.Fail(job.Id, err)
+		return nil, err
+	}
+
+	copied := *job
+	return &copied, nil
+}
+
+// GetHistoryJob returns an on demand history job of this server
+func (server *QpWhatsappServer) GetHistoryJob(id string) (*QpHistoryJob, bool) {
+	return HistoryJobs.Get(server.Token, strings.TrimSpace(id))
+}
+
+// GetHistoryAnchor finds the message history is requested before, from cache or store,
+// when no message id is given, the oldest known message of the chat
+func (server *QpWhatsappServer) GetHistoryAnchor(chatId string, messageId string) (*whatsapp.WhatsappHistoryAnchor, error) {
+	var anchor *whatsapp.WhatsappHistoryAnchor
+
+	if server.Handler != nil {
+		if len(messageId) > 0 {
+			if msg, err := server.Handler.GetById(messageId); err == nil && msg != nil {
+				anchor = &whatsapp.WhatsappHistoryAnchor{ChatId: msg.Chat.Id, MessageId: msg.Id, FromMe: msg.FromMe, Timestamp: msg.Timestamp}
+			}
+		} else {
+			for _, msg := range server.Handler.GetSlice() {
+				if msg.Chat.Id != chatId || msg.Type == whatsapp.SystemMessageType {
+					continue
+				}
+				if anchor == nil || msg.Timestamp.Before(anchor.Timestamp) {
+					anchor = &whatsapp.WhatsappHistoryAnchor{ChatId: msg.Chat.Id, MessageId: msg.Id, FromMe: msg.FromMe, Timestamp: msg.Timestamp}
+				}
+			}
+		}
+	}
+
+	if store, ok := GetMessageStore(); ok {
+		stored, err := store.FindAnchor(server.Token, chatId, messageId)
+		if err != nil {
+			return nil, err
+		}
+
+		// oldest from both when not informed, cache first for a given message
+		if stored != nil && (anchor == nil || (len(messageId) == 0 && stored.Timestamp.Before(anchor.Timestamp))) {
+			anchor = &whatsapp.WhatsappHistoryAnchor{ChatId: stored.ChatId, MessageId: stored.Id, FromMe: stored.FromMe, Timestamp: stored.Timestamp}
+		}
+	}
+
+	if anchor == nil {
+		if len(messageId) > 0 {
+			return nil, fmt.Errorf("message not found: %s", messageId)
+		}
+		return nil, fmt.Errorf("no known message on chat %s to request history before", chatId)
+	}
+
+	if anchor.ChatId != chatId {
+		return nil, fmt.Errorf("message %s does not belong to chat %s", messageId, chatId)
+	}
+
+	return anchor, nil
+}
+
+// HistorySynced completes the on demand history job of the chat and dispatches its completion event
+func (source *DispatchingHandler) HistorySynced(result *whatsapp.WhatsappHistoryChatSync) {
+	if result == nil || source.server == nil {
+		return
+	}
+
+	logentry := source.GetLogger().WithField(LogFields.ChatId, result.ChatId)
+	job, ok := HistoryJobs.Complete(source.server.Token, result)
+	if !ok {
+		logentry.Debugf("on demand history without pending job, messages: %d", result.Messages)
+		return
+	}
+
+	logentry.Infof("history job %s completed, messages: %d, more: %v", job.Id, job.Received, job.More)
+
+	message := &whatsapp.WhatsappMessage{
+		Id:        "history_" + job.Id,
+		Timestamp: time.Now(),
+		Type:      whatsapp.SystemMessageType,
+		Chat:      whatsapp.WhatsappChat{Id: job.ChatId},
+		Text:      fmt.Sprintf("history sync completed: %d messages", job.Received),
+		FromMe:    true,
+		Info:      job,
+	}
+
+	source.Trigger(message)
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+func TestQpHistoryJobsLifecycle(t *testing.T) {
+	jobs := &QpHistoryJobs{items: make(map[string]*QpHistoryJob)}
+	chatId := "5521999999999@s.whatsapp.net"
+
+	job, created := jobs.Add(&QpHistoryJob{Id: "job-1", Token: "token-1", ChatId: chatId, Status: QpHistoryJobPending, Created: time.Now()})
+	if !created || job.Id != "job-1" {
+		t.Fatalf("expected new job, got %+v", job)
+	}
+
+	job, created = jobs.Add(&QpHistoryJob{Id: "job-2", Token: "token-1", ChatId: chatId, Status: QpHistoryJobPending, Created: time.Now()})
+	if created || job.Id != "job-1" {
+		t.Fatalf("expected pending job of the chat to be reused, got %+v", job)
+	}
+
+	if _, ok := jobs.Complete("token-2", &whatsapp.WhatsappHistoryChatSync{ChatId: chatId}); ok {
+		t.Fatalf("job of another server should not be completed")
+	}
+
+	completed, ok := jobs.Complete("token-1", &whatsapp.WhatsappHistoryChatSync{ChatId: chatId, Messages: 50, More: true})
+	if !ok || completed.Status != QpHistoryJobCompleted || completed.Received != 50 || !completed.More || completed.Completed == nil {
+		t.Fatalf("unexpected completed job: %+v", completed)
+	}
+
+	if _, ok := jobs.Get("token-2", "job-1"); ok {
+		t.Fatalf("job should not be visible to another server")
+	}
+
+	jobs.Add(&QpHistoryJob{Id: "job-3", Token: "token-1", ChatId: chatId, Status: QpHistoryJobPending, Created: time.Now().Add(-QpHistoryJobTimeout - time.Second)})
+	expired, ok := jobs.Get("token-1", "job-3")
+	if !ok || expired.Status != QpHistoryJobExpired {
+		t.Fatalf("expected expired job, got %+v", expired)
+	}
+
+	jobs.DeleteByServer("token-1")
+	if _, ok := jobs.Get("token-1", "job-1"); ok {
+		t.Fatalf("jobs should be removed with the server")
+	}
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	antivirus "github.com/nocodeleaks/quepasa/media/antivirus"
+	log "github.com/nocodeleaks/quepasa/qplog"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// directions of scanned attachments, used on metrics and quarantine records
+const (
+	QpAntivirusInbound  = "inbound"
+	QpAntivirusOutbound = "outbound"
+)
+
+var antivirusScanner antivirus.Scanner
+
+// GetAntivirusScanner returns the attachments scanner, false when disabled by environment or not started
+func GetAntivirusScanner() (antivirus.Scanner, bool) {
+	return antivirusScanner, antivirusScanner != nil
+}
+
+// InitializeAntivirus starts the attachments scanner of environment settings,
+// a misconfigured scanner is logged and left disabled, attachments keep flowing unscanned
+func InitializeAntivirus(logentry log.Logger) {
+	settings := environment.Settings.Antivirus
+	if !settings.Enabled() || antivirusScanner != nil {
+		return
+	}
+
+	switch settings.Policy {
+	case environment.AntivirusPolicyBlock, environment.AntivirusPolicyQuarantine, environment.AntivirusPolicyReport:
+	default:
+		logentry.Errorf("antivirus disabled, unknown policy: %s, expected block, quarantine or report", settings.Policy)
+		return
+	}
+
+	scanner, err := antivirus.New(settings)
+	if err != nil {
+		logentry.Errorf("antivirus disabled, %s", err.Error())
+		return
+	}
+
+	if settings.Policy == environment.AntivirusPolicyQuarantine {
+		if err := os.MkdirAll(settings.QuarantinePath, 0700); err != nil {
+			logentry.Errorf("antivirus disabled, failed to create quarantine directory: %s", err.Error())
+			return
+		}
+	}
+
+	logentry.Infof("antivirus enabled, provider: %s, address: %s, policy: %s, fail closed: %v", settings.Provider, settings.Address, settings.Policy, settings.FailClosed)
+	antivirusScanner = scanner
+}
+
+// QpQuarantineRecord describes a quarantined attachment, stored beside its content
+type QpQuarantineRecord struct {
+	Direction string                           `json:"direction"`
+	Reference string                           `json:"reference,omitempty"`
+	FileName  string                           `json:"filename,omitempty"`
+	Mimetype  string                           `json:"mime,omitempty"`
+	Size      int                              `json:"size"`
+	Scan      *whatsapp.WhatsappAttachmentScan `json:"scan"`
+}
+
+// ScanAttachmentContent scans the content of an attachment and applies the antivirus policy,
+// the verdict is set on the attachment; nil when the scanner is disabled.
+// Reference identifies the message on logs and quarantine records.
+func ScanAttachmentContent(logentry log.Logger, attach *whatsapp.WhatsappAttachment, content []byte, direction string, reference string) *whatsapp.WhatsappAttachmentScan {
+	scanner, ok := GetAntivirusScanner()
+	if !ok || attach == nil {
+		return nil
+	}
+
+	settings := environment.Settings.Antivirus
+	scan := &whatsapp.WhatsappAttachmentScan{
+		Scanner:   scanner.Name(),
+		Timestamp: time.Now().UTC(),
+	}
+
+	verdict, err := scanner.Scan(context.Background(), content)
+	switch {
+	case err != nil:
+		logentry.Errorf("antivirus scan failed: %s", err.Error())
+		scan.Status = whatsapp.WhatsappAttachmentScanError
+	case verdict.Infected:
+		logentry.Warnf("antivirus, infected attachment: %s, signature: %s", reference, verdict.Signature)
+		scan.Status = whatsapp.WhatsappAttachmentScanInfected
+		scan.Signature = verdict.Signature
+	default:
+		scan.Status = whatsapp.WhatsappAttachmentScanClean
+	}
+
+	AntivirusScans.WithLabelValues(direction, scan.Status).Inc()
+
+	infected := scan.Status == whatsapp.WhatsappAttachmentScanInfected
+	if infected || (scan.Status == whatsapp.WhatsappAttachmentScanError && settings.FailClosed) {
+		switch settings.Policy {
+		case environment.AntivirusPolicyBlock:
+			scan.Action = "blocked"
+		case environment.AntivirusPolicyQuarantine:
+			scan.Action = "blocked"
+			if infected {
+				if err := quarantineAttachment(settings.QuarantinePath, attach, content, scan, direction, reference); err != nil {
+					logentry.Errorf("antivirus, failed to quarantine attachment: %s", err.Error())
+				} else {
+					scan.Action = "quarantined"
+				}
+			}
+		}
+	}
+
+	if scan.IsBlocked() {
+		AntivirusBlocked.WithLabelValues(direction, scan.Action).Inc()
+	}
+
+	attach.Scan = scan
+	return scan
+}
+
+// GetAntivirusBlockedError returns the error of attachments withheld by the antivirus policy, nil otherwise
+func GetAntivirusBlockedError(scan *whatsapp.WhatsappAttachmentScan) error {
+	if !scan.IsBlocked() {
+		return nil
+	}
+
+	if scan.Status == whatsapp.WhatsappAttachmentScanInfected {
+		return fmt.Errorf("attachment %s by antivirus, signature: %s", scan.Action, scan.Signature)
+	}
+	return fmt.Errorf("attachment %s, antivirus scan failed", scan.Action)
+}
+
+// quarantineAttachment stores an infected content under its sha256, with a json record beside it
+func quarantineAttachment(path string, attach *whatsapp.WhatsappAttachment, content []byte, scan *whatsapp.WhatsappAttachmentScan, direction string, reference string) error {
+	hash := sha256.Sum256(content)
+	name := filepath.Join(path, hex.EncodeToString(hash[:]))
+
+	if err := os.WriteFile(name, content, 0600); err != nil {
+		return err
+	}
+
+	record := QpQuarantineRecord{
+		Direction: direction,
+		Reference: reference,
+		FileName:  attach.FileName,
+		Mimetype:  attach.Mimetype,
+		Size:      len(content),
+		Scan:      scan,
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name+".json", data, 0600)
+}
+
+// scanAttachment scans the attachment of a new inbound message, before storing, transcribing,
+// caching and dispatching; blocked attachments are dispatched with the verdict, without content
+func (source *DispatchingHandler) scanAttachment(msg *whatsapp.WhatsappMessage) {
+	if _, ok := GetAntivirusScanner(); !ok || msg == nil || !msg.HasAttachment() {
+		return
+	}
+
+	// sent messages are scanned before upload, history is scanned on download
+	if msg.FromMe || msg.FromHistory || source.server == nil {
+		return
+	}
+
+	// already cached, updates of a known message keep the verdict
+	if _, err := source.QpWhatsappMessages.GetById(msg.Id); err == nil {
+		return
+	}
+
+	logentry := source.GetLogger().WithField(LogFields.MessageId, msg.Id)
+
+	content, err := source.getAttachmentContent(msg)
+	if err != nil {
+		logentry.Errorf("antivirus, attachment not scanned: %s", err.Error())
+		return
+	}
+
+	ScanAttachmentContent(logentry, msg.Attachment, *content, QpAntivirusInbound, msg.Id)
+}
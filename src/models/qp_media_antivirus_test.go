@@ -0,0 +1,77 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	antivirus "github.com/nocodeleaks/quepasa/media/antivirus"
+	log "github.com/nocodeleaks/quepasa/qplog"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type testAntivirusScanner struct{}
+
+func (testAntivirusScanner) Name() string { return "test" }
+
+func (testAntivirusScanner) Scan(ctx context.Context, content []byte) (*antivirus.Verdict, error) {
+	if bytes.Contains(content, []byte("EICAR")) {
+		return &antivirus.Verdict{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	}
+	return &antivirus.Verdict{}, nil
+}
+
+func TestScanAttachmentContentQuarantine(t *testing.T) {
+	previousScanner, previousSettings := antivirusScanner, environment.Settings.Antivirus
+	defer func() {
+		antivirusScanner, environment.Settings.Antivirus = previousScanner, previousSettings
+	}()
+
+	antivirusScanner = testAntivirusScanner{}
+	environment.Settings.Antivirus.Policy = environment.AntivirusPolicyQuarantine
+	environment.Settings.Antivirus.QuarantinePath = t.TempDir()
+
+	attach := &whatsapp.WhatsappAttachment{Mimetype: "application/pdf", FileName: "invoice.pdf"}
+	scan := ScanAttachmentContent(log.New(), attach, []byte("EICAR"), QpAntivirusInbound, "MSG1")
+
+	if scan == nil || attach.Scan != scan || scan.Status != whatsapp.WhatsappAttachmentScanInfected || scan.Action != "quarantined" {
+		t.Fatalf("unexpected verdict: %+v", scan)
+	}
+
+	if GetAntivirusBlockedError(scan) == nil {
+		t.Errorf("expected blocked error")
+	}
+
+	files, _ := filepath.Glob(filepath.Join(environment.Settings.Antivirus.QuarantinePath, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected a quarantine record, found: %v", files)
+	}
+
+	if content, _ := os.ReadFile(files[0][:len(files[0])-len(".json")]); string(content) != "EICAR" {
+		t.Errorf("unexpected quarantined content: %q", content)
+	}
+}
+
+func TestScanAttachmentContentReport(t *testing.T) {
+	previousScanner, previousSettings := antivirusScanner, environment.Settings.Antivirus
+	defer func() {
+		antivirusScanner, environment.Settings.Antivirus = previousScanner, previousSettings
+	}()
+
+	antivirusScanner = testAntivirusScanner{}
+	environment.Settings.Antivirus.Policy = environment.AntivirusPolicyReport
+
+	attach := &whatsapp.WhatsappAttachment{Mimetype: "application/pdf"}
+	scan := ScanAttachmentContent(log.New(), attach, []byte("EICAR"), QpAntivirusOutbound, "MSG2")
+	if scan.Status != whatsapp.WhatsappAttachmentScanInfected || scan.IsBlocked() || GetAntivirusBlockedError(scan) != nil {
+		t.Errorf("report policy should not block: %+v", scan)
+	}
+
+	scan = ScanAttachmentContent(log.New(), attach, []byte("brochure"), QpAntivirusOutbound, "MSG3")
+	if scan.Status != whatsapp.WhatsappAttachmentScanClean {
+		t.Errorf("unexpected verdict: %+v", scan)
+	}
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	storage "github.com/nocodeleaks/quepasa/media/storage"
+	log "github.com/nocodeleaks/quepasa/qplog"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// maximum time spent downloading and uploading an attachment before dispatching
+const QpMediaStorageTimeout = 2 * time.Minute
+
+// interval between retention cleanups of stored attachments
+const QpMediaStorageCleanupInterval = time.Hour
+
+var mediaStorage storage.ObjectStorage
+
+// GetMediaStorage returns the attachments object storage, false when disabled by environment or not started
+func GetMediaStorage() (storage.ObjectStorage, bool) {
+	return mediaStorage, mediaStorage != nil
+}
+
+// InitializeMediaStorage starts the object storage of environment settings and its retention,
+// a misconfigured storage is logged and left disabled, messages keep flowing without links
+func InitializeMediaStorage(logentry log.Logger) {
+	settings := environment.Settings.Storage
+	if !settings.Enabled() || mediaStorage != nil {
+		return
+	}
+
+	backend, err := storage.New(settings)
+	if err != nil {
+		logentry.Errorf("media storage disabled, %s", err.Error())
+		return
+	}
+
+	logentry.Infof("media storage enabled, backend: %s, outbound: %v, retention days: %d", settings.Backend, settings.Outbound, settings.RetentionDays)
+	mediaStorage = backend
+
+	if settings.RetentionDays == 0 {
+		return
+	}
+
+	if s3, ok := backend.(*storage.S3Storage); ok && settings.S3Lifecycle {
+		if err := s3.ApplyRetention(context.Background(), settings.Prefix, settings.RetentionDays); err != nil {
+			logentry.Errorf("media storage, failed to apply bucket lifecycle: %s", err.Error())
+		}
+	}
+
+	go func() {
+		retention := time.Duration(settings.RetentionDays) * 24 * time.Hour
+		ticker := time.NewTicker(QpMediaStorageCleanupInterval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			removed, err := backend.Cleanup(context.Background(), time.Now().Add(-retention))
+			if err != nil {
+				logentry.Errorf("media storage cleanup failed: %s", err.Error())
+			} else if removed > 0 {
+				logentry.Infof("media storage cleanup, removed: %d", removed)
+			}
+		}
+	}()
+}
+
+// offloadAttachment uploads the attachment of a new message to the object storage, when enabled,
+// filling its link, checksum and size before caching and dispatching
+func (source *DispatchingHandler) offloadAttachment(msg *whatsapp.WhatsappMessage) {
+	backend, ok := GetMediaStorage()
+	if !ok || msg == nil || !msg.HasAttachment() || msg.FromHistory || source.server == nil {
+		return
+	}
+
+	if msg.Attachment.Scan.IsBlocked() {
+		return
+	}
+
+	if msg.FromMe && !environment.Settings.Storage.Outbound {
+		return
+	}
+
+	// already cached, updates of a known message keep the stored link
+	if _, err := source.QpWhatsappMessages.GetById(msg.Id); err == nil {
+		return
+	}
+
+	logentry := source.GetLogger().WithField(LogFields.MessageId, msg.Id)
+	attach := msg.Attachment
+
+	content, err := source.getAttachmentContent(msg)
+	if err != nil {
+		logentry.Errorf("media storage, attachment not stored: %s", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), QpMediaStorageTimeout)
+	defer cancel()
+
+	object, err := storage.Store(ctx, backend, environment.Settings.Storage.Prefix, *content, attach.Mimetype, attach.FileName, msg.Timestamp)
+	if err != nil {
+		logentry.Errorf("media storage, failed to store attachment: %s", err.Error())
+		return
+	}
+
+	attach.StorageKey = object.Key
+	attach.Url = object.Url
+	attach.UrlExpires = object.Expires
+	attach.Checksum = object.Checksum
+	attach.FileLength = object.Size
+	logentry.Debugf("attachment stored, key: %s, size: %d", object.Key, object.Size)
+}
+
+// getAttachmentContent returns the attachment content of a message, downloading it when not in memory.
+// Downloaded content is kept on the attachment, so scan, offload and transcription share one download.
+func (source *DispatchingHandler) getAttachmentContent(msg *whatsapp.WhatsappMessage) (*[]byte, error) {
+	if content := msg.Attachment.GetContent(); content != nil {
+		return content, nil
+	}
+
+	conn, err := source.server.GetValidConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := conn.DownloadData(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment: %w", err)
+	}
+
+	msg.Attachment.SetContent(&data)
+	return &data, nil
+}
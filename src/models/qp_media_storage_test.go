@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type downloadCountingConnection struct {
+	*pairingTestConnection
+	downloads int
+}
+
+func (c *downloadCountingConnection) DownloadData(whatsapp.IWhatsappMessage) ([]byte, error) {
+	c.downloads++
+	return []byte("content"), nil
+}
+
+func TestGetAttachmentContentDownloadsOnce(t *testing.T) {
+	conn := &downloadCountingConnection{pairingTestConnection: newPairingTestConnection(t)}
+	handler := &DispatchingHandler{server: &QpWhatsappServer{connection: conn}}
+	msg := &whatsapp.WhatsappMessage{Attachment: &whatsapp.WhatsappAttachment{Mimetype: "audio/ogg"}}
+
+	for i := 0; i < 3; i++ {
+		content, err := handler.getAttachmentContent(msg)
+		if err != nil || content == nil || string(*content) != "content" {
+			t.Fatalf("unexpected content: %v, %v", content, err)
+		}
+	}
+
+	if conn.downloads != 1 {
+		t.Errorf("expected one download, got: %d", conn.downloads)
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	transcription "github.com/nocodeleaks/quepasa/media/transcription"
+	log "github.com/nocodeleaks/quepasa/qplog"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// default time waiting for a transcript, when not set by environment
+const QpTranscriptionTimeout = 2 * time.Minute
+
+var transcriber transcription.Transcriber
+
+// GetTranscriber returns the speech to text provider, false when disabled by environment or not started
+func GetTranscriber() (transcription.Transcriber, bool) {
+	return transcriber, transcriber != nil
+}
+
+// InitializeTranscriber starts the speech to text provider of environment settings,
+// a misconfigured provider is logged and left disabled, audios keep flowing without transcripts
+func InitializeTranscriber(logentry log.Logger) {
+	settings := environment.Settings.Transcription
+	if !settings.Available() || transcriber != nil {
+		return
+	}
+
+	provider, err := transcription.New(settings)
+	if err != nil {
+		logentry.Errorf("transcription disabled, %s", err.Error())
+		return
+	}
+
+	logentry.Infof("transcription enabled, provider: %s, sessions default: %v, max seconds: %d", settings.Provider, settings.Enabled, settings.MaxSeconds)
+	transcriber = provider
+}
+
+// transcribeAttachment fills the transcript of a new inbound audio message, when enabled
+// for the server, before caching and dispatching
+func (source *DispatchingHandler) transcribeAttachment(msg *whatsapp.WhatsappMessage) {
+	provider, ok := GetTranscriber()
+	if !ok || msg == nil || msg.Type != whatsapp.AudioMessageType || !msg.HasAttachment() {
+		return
+	}
+
+	if msg.FromMe || msg.FromHistory || source.server == nil || !source.server.GetTranscribe() {
+		return
+	}
+
+	if msg.Attachment.Scan.IsBlocked() {
+		return
+	}
+
+	logentry := source.GetLogger().WithField(LogFields.MessageId, msg.Id)
+	attach := msg.Attachment
+
+	if max := environment.Settings.Transcription.MaxSeconds; max > 0 && attach.Seconds > max {
+		logentry.Debugf("transcription skipped, audio too long: %d > %d seconds", attach.Seconds, max)
+		return
+	}
+
+	// already cached, updates of a known message keep the transcript
+	if _, err := source.QpWhatsappMessages.GetById(msg.Id); err == nil {
+		return
+	}
+
+	content, err := source.getAttachmentContent(msg)
+	if err != nil {
+		logentry.Errorf("transcription, %s", err.Error())
+		return
+	}
+
+	timeout := time.Duration(environment.Settings.Transcription.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = QpTranscriptionTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	options := transcription.Options{Language: source.server.GetTranscribeLanguage()}
+	text, err := provider.Transcribe(ctx, *content, attach.Mimetype, options)
+	if err != nil {
+		logentry.Errorf("transcription failed: %s", err.Error())
+		return
+	}
+
+	attach.Transcript = text
+	logentry.Debugf("audio transcribed, length: %d", len(text))
+}
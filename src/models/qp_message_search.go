@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+const (
+	QpMessagesSearchDefaultLimit = 20
+	QpMessagesSearchMaxLimit     = 100
+)
+
+// search index columns weights: text (and captions), filename, contact names
+var messagesSearchWeights = []float64{1.0, 0.75, 0.5}
+
+// QpMessagesSearchQuery filters a full text search over stored messages
+type QpMessagesSearchQuery struct {
+	Text   string
+	ChatId string
+
+	// only messages between these timestamps, zero means unbounded
+	From time.Time
+	To   time.Time
+
+	// message type names, empty means all
+	Types []string
+
+	Limit int
+
+	// hits to skip, paging over the ranked matches
+	Offset int
+}
+
+// Normalize trims and applies the default and max limits
+func (source *QpMessagesSearchQuery) Normalize() {
+	source.Text = strings.TrimSpace(source.Text)
+	source.ChatId = strings.TrimSpace(source.ChatId)
+
+	if source.Limit <= 0 {
+		source.Limit = QpMessagesSearchDefaultLimit
+	} else if source.Limit > QpMessagesSearchMaxLimit {
+		source.Limit = QpMessagesSearchMaxLimit
+	}
+
+	if source.Offset < 0 {
+		source.Offset = 0
+	}
+
+	types := make([]string, 0, len(source.Types))
+	for _, name := range source.Types {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) > 0 {
+			types = append(types, name)
+		}
+	}
+	source.Types = types
+}
+
+// QpMessageSearchHit is a stored message matching a search, higher rank first
+type QpMessageSearchHit struct {
+	Message *QpStoredMessage `json:"message"`
+	Snippet string           `json:"snippet,omitempty"`
+	Rank    float64          `json:"rank"`
+}
+
+// GetMessagesSearchMatch converts free text into a full text match expression,
+// every term is quoted (operators are not allowed) and matched as prefix
+func GetMessagesSearchMatch(text string) string {
+	terms := []string{}
+	for _, term := range strings.Fields(text) {
+		term = strings.Trim(strings.ReplaceAll(term, `"`, ""), "*")
+		if len(term) > 0 {
+			terms = append(terms, `"`+term+`*"`)
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// GetMessagesSearchRank scores a match from its matchinfo 'pcx' blob, rarer terms weight more
+func GetMessagesSearchRank(matchinfo []byte) (rank float64) {
+	values := make([]uint32, len(matchinfo)/4)
+	for i := range values {
+		values[i] = binary.NativeEndian.Uint32(matchinfo[i*4:])
+	}
+
+	if len(values) < 2 {
+		return 0
+	}
+
+	phrases, columns := int(values[0]), int(values[1])
+	for phrase := 0; phrase < phrases; phrase++ {
+		for column := 0; column < columns && column < len(messagesSearchWeights); column++ {
+			offset := 2 + (phrase*columns+column)*3
+			if offset+1 >= len(values) {
+				return
+			}
+
+			hitsThisRow, hitsAllRows := values[offset], values[offset+1]
+			if hitsThisRow > 0 && hitsAllRows > 0 {
+				rank += float64(hitsThisRow) / float64(hitsAllRows) * messagesSearchWeights[column]
+			}
+		}
+	}
+	return
+}
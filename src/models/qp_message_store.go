@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// GetMessageStore returns the durable message store, false when disabled by environment or not configured
+func GetMessageStore() (QpDataMessagesInterface, bool) {
+	if !environment.Settings.General.MessageStore {
+		return nil, false
+	}
+
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Messages == nil {
+		return nil, false
+	}
+
+	return WhatsappService.DB.Messages, true
+}
+
+// GetChatStore returns the durable chat list, false when disabled by environment or not configured
+func GetChatStore() (QpDataChatsInterface, bool) {
+	if !environment.Settings.General.MessageStore {
+		return nil, false
+	}
+
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Chats == nil {
+		return nil, false
+	}
+
+	return WhatsappService.DB.Chats, true
+}
+
+// storeMessage persists a cached message on the durable store, when enabled
+func (source *DispatchingHandler) storeMessage(msg *whatsapp.WhatsappMessage) {
+	if msg == nil || source.server == nil {
+		return
+	}
+
+	// system and unhandled messages are events, not conversation history
+	if msg.Type == whatsapp.SystemMessageType || msg.Type == whatsapp.UnhandledMessageType {
+		return
+	}
+
+	store, ok := GetMessageStore()
+	if !ok {
+		return
+	}
+
+	logentry := source.GetLogger().WithField(LogFields.MessageId, msg.Id)
+	err := store.Store(source.server.Token, msg)
+	if err != nil {
+		logentry.Errorf("failed to persist message on store: %s", err.Error())
+	}
+
+	if chats, ok := GetChatStore(); ok && !msg.FromBroadcast() {
+		if err := chats.Touch(source.server.Token, msg); err != nil {
+			logentry.Errorf("failed to update chat list: %s", err.Error())
+		}
+	}
+}
+
+// ChatState updates the durable chat list from app state events and history sync conversations
+func (source *DispatchingHandler) ChatState(state *whatsapp.WhatsappChatState) {
+	if state == nil || source.server == nil {
+		return
+	}
+
+	chats, ok := GetChatStore()
+	if !ok {
+		return
+	}
+
+	err := chats.UpdateState(source.server.Token, state)
+	if err != nil {
+		logentry := source.GetLogger().WithField(LogFields.ChatId, state.Id)
+		logentry.Errorf("failed to update chat list: %s", err.Error())
+	}
+}
+
+// MessageStatusUpdate updates the cached status and the stored one, when enabled
+func (source *DispatchingHandler) MessageStatusUpdate(id string, status whatsapp.WhatsappMessageStatus) bool {
+	updated := source.QpWhatsappMessages.MessageStatusUpdate(id, status)
+	if !updated || source.server == nil {
+		return updated
+	}
+
+	if store, ok := GetMessageStore(); ok {
+		err := store.UpdateStatus(source.server.Token, id, status)
+		if err != nil {
+			source.GetLogger().Errorf("failed to update stored message status: %s", err.Error())
+		}
+	}
+
+	return updated
+}
+
+// SearchStoredMessages runs a ranked full text search over persisted messages
+func (server *QpWhatsappServer) SearchStoredMessages(query QpMessagesSearchQuery) ([]*QpMessageSearchHit, error) {
+	store, ok := GetMessageStore()
+	if !ok {
+		return nil, fmt.Errorf("message store is disabled, set %s=true to enable it", environment.ENV_MESSAGESTORE)
+	}
+
+	return store.Search(server.Token, query)
+}
+
+// GetStoredChats returns a page of the chat list, with labels, and the total of chats
+func (server *QpWhatsappServer) GetStoredChats(query QpStoredChatsQuery) ([]*QpStoredChat, int, error) {
+	chats, ok := GetChatStore()
+	if !ok {
+		return nil, 0, fmt.Errorf("message store is disabled, set %s=true to enable it", environment.ENV_MESSAGESTORE)
+	}
+
+	items, total, err := chats.Find(server.Token, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]string, 0, len(items))
+	untitled := []string{}
+	for _, item := range items {
+		if len(item.Title) == 0 {
+			untitled = append(untitled, item.Id)
+		}
+		ids = append(ids, item.Id)
+	}
+
+	// resolved at once, a page may hold hundreds of untitled chats
+	if len(untitled) > 0 {
+		titles := server.GetChatTitles(untitled)
+		for _, item := range items {
+			if len(item.Title) == 0 {
+				item.Title = titles[item.Id]
+			}
+		}
+	}
+
+	if labels, ok := getConversationLabelStore(); ok {
+		labelsMap, err := labels.FindConversationLabelsMap(server.Token, server.GetUser(), ids)
+		if err != nil {
+			server.GetLogger().Errorf("failed to load chat labels: %s", err.Error())
+		}
+
+		for _, item := range items {
+			for _, label := range labelsMap[item.Id] {
+				item.Labels = append(item.Labels, label.ToWhatsappLabel())
+			}
+		}
+	}
+
+	return items, total, nil
+}
+
+// GetStoredMessages returns a page of persisted messages from a chat and the next page cursor
+func (server *QpWhatsappServer) GetStoredMessages(query QpStoredMessagesQuery) ([]*QpStoredMessage, string, error) {
+	store, ok := GetMessageStore()
+	if !ok {
+		return nil, "", fmt.Errorf("message store is disabled, set %s=true to enable it", environment.ENV_MESSAGESTORE)
+	}
+
+	return store.FindByChat(server.Token, query)
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Compile-time check to ensure QpPrivacyManager implements whatsapp.WhatsappPrivacyManagerInterface
+var _ whatsapp.WhatsappPrivacyManagerInterface = (*QpPrivacyManager)(nil)
+
+// QpPrivacyManager handles account privacy settings operations for QpWhatsappServer
+// Implements whatsapp.WhatsappPrivacyManagerInterface interface
+type QpPrivacyManager struct {
+	*QpWhatsappServer // embedded server for direct access
+}
+
+// NewQpPrivacyManager creates a new QpPrivacyManager instance
+func NewQpPrivacyManager(server *QpWhatsappServer) *QpPrivacyManager {
+	return &QpPrivacyManager{
+		QpWhatsappServer: server,
+	}
+}
+
+// getPrivacyManager is a helper function to get the privacy manager from connection
+func (pm *QpPrivacyManager) getPrivacyManager() (whatsapp.WhatsappPrivacyManagerInterface, error) {
+	conn, err := pm.GetValidConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	privacyManager := conn.GetPrivacyManager()
+	if privacyManager == nil {
+		return nil, fmt.Errorf("privacy manager not available")
+	}
+
+	return privacyManager, nil
+}
+
+// GetPrivacySettings returns the current account privacy settings
+func (pm *QpPrivacyManager) GetPrivacySettings() (*whatsapp.WhatsappPrivacySettings, error) {
+	privacyManager, err := pm.getPrivacyManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return privacyManager.GetPrivacySettings()
+}
+
+// SetPrivacySetting changes a single account privacy setting
+func (pm *QpPrivacyManager) SetPrivacySetting(setting whatsapp.WhatsappPrivacySettingType, value string) (*whatsapp.WhatsappPrivacySettings, error) {
+	privacyManager, err := pm.getPrivacyManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return privacyManager.SetPrivacySetting(setting, value)
+}
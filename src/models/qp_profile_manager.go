@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Compile-time check to ensure QpProfileManager implements whatsapp.WhatsappProfileManagerInterface
+var _ whatsapp.WhatsappProfileManagerInterface = (*QpProfileManager)(nil)
+
+// QpProfileManager handles own profile operations (push name, about and photo) for QpWhatsappServer
+// Implements whatsapp.WhatsappProfileManagerInterface interface
+type QpProfileManager struct {
+	*QpWhatsappServer // embedded server for direct access
+}
+
+// NewQpProfileManager creates a new QpProfileManager instance
+func NewQpProfileManager(server *QpWhatsappServer) *QpProfileManager {
+	return &QpProfileManager{
+		QpWhatsappServer: server,
+	}
+}
+
+// getProfileManager is a helper function to get the profile manager from connection
+func (pm *QpProfileManager) getProfileManager() (whatsapp.WhatsappProfileManagerInterface, error) {
+	conn, err := pm.GetValidConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	profileManager := conn.GetProfileManager()
+	if profileManager == nil {
+		return nil, fmt.Errorf("profile manager not available")
+	}
+
+	return profileManager, nil
+}
+
+// SetPushName changes the push name of this session
+func (pm *QpProfileManager) SetPushName(name string) error {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return err
+	}
+
+	return profileManager.SetPushName(name)
+}
+
+// SetAbout changes the about (status text) of this session
+func (pm *QpProfileManager) SetAbout(about string) error {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return err
+	}
+
+	return profileManager.SetAbout(about)
+}
+
+// SetProfilePicture replaces the profile photo of this session
+func (pm *QpProfileManager) SetProfilePicture(imageData []byte) (string, error) {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return "", err
+	}
+
+	return profileManager.SetProfilePicture(imageData)
+}
+
+// RemoveProfilePicture removes the profile photo of this session
+func (pm *QpProfileManager) RemoveProfilePicture() error {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return err
+	}
+
+	return profileManager.RemoveProfilePicture()
+}
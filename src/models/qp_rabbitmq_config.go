@@ -291,8 +291,8 @@ func (source *QpRabbitMQConfig) DetermineRoutingKey(message *whatsapp.WhatsappMe
 		return GetRabbitMQRoutingKeyEvents()
 	}
 
-	// Receipt events (read and delivery) go to the events queue
-	if message.Id == "readreceipt" || message.Id == "deliveryreceipt" {
+	// Receipt events (read, played and delivery) go to the events queue
+	if message.Id == "readreceipt" || message.Id == "playedreceipt" || message.Id == "deliveryreceipt" {
 		return GetRabbitMQRoutingKeyEvents()
 	}
 
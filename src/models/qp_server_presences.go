@@ -0,0 +1,34 @@
+package models
+
+import "github.com/nocodeleaks/quepasa/whatsapp"
+
+// GetPresences returns whether presence updates should be dispatched to webhooks
+// for this server, read from the server metadata. Defaults to false.
+func (server *QpServer) GetPresences() bool {
+	if server == nil {
+		return false
+	}
+
+	raw := server.GetMetadataValue(whatsapp.MetadataKeyPresences)
+	if value, ok := raw.(bool); ok {
+		return value
+	}
+
+	return false
+}
+
+// SetPresences persists the presences opt-in into the server metadata and the
+// live options. Disabling removes the key to keep the metadata clean.
+func (server *QpServer) SetPresences(enabled bool) {
+	if server == nil {
+		return
+	}
+
+	server.WhatsappOptions.Presences = enabled
+	if !enabled {
+		server.RemoveMetadataValue(whatsapp.MetadataKeyPresences)
+		return
+	}
+
+	server.SetMetadataValue(whatsapp.MetadataKeyPresences, true)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	library "github.com/nocodeleaks/quepasa/library"
+	"github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// GetRegion returns the default phone region of this server, read from the server
+// metadata. Empty when not set, phones without + are then taken as international.
+func (server *QpServer) GetRegion() string {
+	if server == nil {
+		return ""
+	}
+
+	if value, ok := server.GetMetadataValue(whatsapp.MetadataKeyRegion).(string); ok {
+		return value
+	}
+
+	return ""
+}
+
+// SetRegion validates and persists the default phone region into the server metadata.
+// An empty region removes the key to keep the metadata clean.
+func (server *QpServer) SetRegion(region string) error {
+	if server == nil {
+		return nil
+	}
+
+	region, err := library.GetPhoneRegionOrError(region)
+	if err != nil {
+		return err
+	}
+
+	if len(region) == 0 {
+		server.RemoveMetadataValue(whatsapp.MetadataKeyRegion)
+		return nil
+	}
+
+	server.SetMetadataValue(whatsapp.MetadataKeyRegion, region)
+	return nil
+}
+
+// FormatEndpoint formats a destination, reading local format phones on the server region
+func (server *QpServer) FormatEndpoint(source string) (string, error) {
+	return whatsapp.FormatEndpointWithRegion(source, server.GetRegion())
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// GetStatuses returns whether contacts statuses (stories) should be dispatched
+// for this server, read from the server metadata. Defaults to false.
+func (server *QpServer) GetStatuses() bool {
+	if server == nil {
+		return false
+	}
+
+	raw := server.GetMetadataValue(whatsapp.MetadataKeyStatuses)
+	if value, ok := raw.(bool); ok {
+		return value
+	}
+
+	return false
+}
+
+// SetStatuses persists the statuses opt-in into the server metadata and the
+// live options. Disabling removes the key to keep the metadata clean.
+func (server *QpServer) SetStatuses(enabled bool) {
+	if server == nil {
+		return
+	}
+
+	server.WhatsappOptions.Statuses = enabled
+	if !enabled {
+		server.RemoveMetadataValue(whatsapp.MetadataKeyStatuses)
+		return
+	}
+
+	server.SetMetadataValue(whatsapp.MetadataKeyStatuses, true)
+}
+
+// PublishStatus publishes a status (story) and keeps it on cache, so viewers
+// can be tracked while it remains visible
+func (source *QpWhatsappServer) PublishStatus(text string, attachment *whatsapp.WhatsappAttachment) (string, error) {
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return "", err
+	}
+
+	if attachment != nil && attachment.GetContent() != nil {
+		scan := ScanAttachmentContent(source.GetLogger(), attachment, *attachment.GetContent(), QpAntivirusOutbound, "status")
+		if err := GetAntivirusBlockedError(scan); err != nil {
+			return "", err
+		}
+	}
+
+	msgId, err := conn.PublishStatus(text, attachment)
+	if err != nil {
+		return "", err
+	}
+
+	if source.Handler == nil {
+		return msgId, nil
+	}
+
+	msg := &whatsapp.WhatsappMessage{
+		Id:           msgId,
+		Timestamp:    time.Now().UTC(),
+		Type:         whatsapp.TextMessageType,
+		Chat:         whatsapp.WhatsappChat{Id: "status@broadcast"},
+		Text:         text,
+		FromMe:       true,
+		FromInternal: true,
+		Status:       whatsapp.WhatsappMessageStatusDelivered,
+		Wid:          source.GetWId(),
+	}
+
+	if attachment != nil {
+		msg.Type = whatsapp.GetMessageType(attachment)
+
+		// only metadata is kept, content was already uploaded
+		info := *attachment
+		info.SetContent(nil)
+		msg.Attachment = &info
+	}
+
+	// dispatching only when statuses are handled, tracking viewers anyway
+	if source.Handler.HandleStatuses() {
+		source.Handler.Message(msg, "status")
+	} else if !source.Handler.QpWhatsappMessages.Append(msg, "status") {
+		source.GetLogger().Warnf("status not cached, id: %s", msgId)
+	}
+
+	return msgId, nil
+}
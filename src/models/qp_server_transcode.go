@@ -0,0 +1,39 @@
+package models
+
+import (
+	environment "github.com/nocodeleaks/quepasa/environment"
+	media "github.com/nocodeleaks/quepasa/media"
+	"github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// GetTranscodeVideo returns whether outbound videos with incompatible codecs or containers
+// are transcoded for this server, read from the server metadata. Defaults to the environment.
+func (server *QpServer) GetTranscodeVideo() bool {
+	if server == nil {
+		return environment.Settings.General.TranscodeVideo
+	}
+
+	raw := server.GetMetadataValue(whatsapp.MetadataKeyTranscodeVideo)
+	if value, ok := raw.(bool); ok {
+		return value
+	}
+
+	return environment.Settings.General.TranscodeVideo
+}
+
+// SetTranscodeVideo persists the video transcoding choice into the server metadata,
+// kept even when equal to the environment default, so later default changes do not affect it
+func (server *QpServer) SetTranscodeVideo(enabled bool) {
+	if server == nil {
+		return
+	}
+
+	server.SetMetadataValue(whatsapp.MetadataKeyTranscodeVideo, enabled)
+}
+
+// GetAttachmentOptions returns the outbound attachment pipeline options of this server
+func (server *QpServer) GetAttachmentOptions() media.AttachmentOptions {
+	return media.AttachmentOptions{
+		TranscodeVideo: server.GetTranscodeVideo(),
+	}
+}
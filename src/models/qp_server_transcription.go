@@ -0,0 +1,60 @@
+package models
+
+import (
+	environment "github.com/nocodeleaks/quepasa/environment"
+	transcription "github.com/nocodeleaks/quepasa/media/transcription"
+	"github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// GetTranscribe returns whether inbound audios are transcribed for this server,
+// read from the server metadata. Defaults to the environment.
+func (server *QpServer) GetTranscribe() bool {
+	if server == nil {
+		return environment.Settings.Transcription.Enabled
+	}
+
+	raw := server.GetMetadataValue(whatsapp.MetadataKeyTranscribe)
+	if value, ok := raw.(bool); ok {
+		return value
+	}
+
+	return environment.Settings.Transcription.Enabled
+}
+
+// SetTranscribe persists the transcription choice into the server metadata
+func (server *QpServer) SetTranscribe(enabled bool) {
+	if server == nil {
+		return
+	}
+
+	server.SetMetadataValue(whatsapp.MetadataKeyTranscribe, enabled)
+}
+
+// GetTranscribeLanguage returns the language hint of transcriptions for this server,
+// read from the server metadata. Defaults to the environment.
+func (server *QpServer) GetTranscribeLanguage() string {
+	if server != nil {
+		raw := server.GetMetadataValue(whatsapp.MetadataKeyTranscribeLanguage)
+		if value, ok := raw.(string); ok && len(value) > 0 {
+			return value
+		}
+	}
+
+	return transcription.NormalizeLanguage(environment.Settings.Transcription.Language)
+}
+
+// SetTranscribeLanguage persists the language hint into the server metadata.
+// An empty language removes the key, falling back to the environment.
+func (server *QpServer) SetTranscribeLanguage(language string) {
+	if server == nil {
+		return
+	}
+
+	language = transcription.NormalizeLanguage(language)
+	if len(language) == 0 {
+		server.RemoveMetadataValue(whatsapp.MetadataKeyTranscribeLanguage)
+		return
+	}
+
+	server.SetMetadataValue(whatsapp.MetadataKeyTranscribeLanguage, language)
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+const (
+	QpStoredChatsDefaultLimit = 50
+	QpStoredChatsMaxLimit     = 500
+)
+
+// QpStoredChat is a chat list entry, as shown on the phone
+type QpStoredChat struct {
+	ServerToken string `db:"server_token" json:"-"`
+	Id          string `db:"chat_id" json:"id"`
+	Title       string `db:"title" json:"title,omitempty"`
+
+	LastMessageId     string `db:"last_message_id" json:"-"`
+	LastMessageType   string `db:"last_message_type" json:"-"`
+	LastMessageText   string `db:"last_message_text" json:"-"`
+	LastMessageFromMe bool   `db:"last_message_fromme" json:"-"`
+	LastMessageAt     int64  `db:"last_message_at" json:"-"`
+
+	Unread       uint32 `db:"unread" json:"unread"`
+	MarkedUnread bool   `db:"markedunread" json:"markedunread,omitempty"`
+	Archived     bool   `db:"archived" json:"archived"`
+	Pinned       bool   `db:"pinned" json:"pinned"`
+
+	// unix milliseconds until the chat is muted, -1 forever, 0 unmuted
+	MutedUntil int64 `db:"muted_until" json:"muteduntil,omitempty"`
+
+	// disappearing messages timer in seconds, 0 disabled
+	Ephemeral uint32 `db:"ephemeral" json:"ephemeral,omitempty"`
+
+	// row update
+	Updated time.Time `db:"timestamp" json:"-"`
+
+	Muted       bool                         `db:"-" json:"muted"`
+	LastMessage *QpStoredChatPreview         `db:"-" json:"lastmessage,omitempty"`
+	Labels      []whatsapp.WhatsappChatLabel `db:"-" json:"labels,omitempty"`
+}
+
+// QpStoredChatPreview is the last message preview of a chat list entry
+type QpStoredChatPreview struct {
+	Id        string    `json:"id"`
+	Type      string    `json:"type"`
+	Text      string    `json:"text,omitempty"`
+	FromMe    bool      `json:"fromme"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Fill computes the fields not persisted
+func (source *QpStoredChat) Fill(now time.Time) {
+	source.Muted = source.MutedUntil < 0 || source.MutedUntil > now.UnixMilli()
+
+	if len(source.LastMessageId) > 0 {
+		source.LastMessage = &QpStoredChatPreview{
+			Id:        source.LastMessageId,
+			Type:      source.LastMessageType,
+			Text:      source.LastMessageText,
+			FromMe:    source.LastMessageFromMe,
+			Timestamp: time.UnixMilli(source.LastMessageAt).UTC(),
+		}
+	}
+}
+
+// QpStoredChatsQuery sorts and pages the chat list
+type QpStoredChatsQuery struct {
+
+	// timestamp (default, pinned first), unread or title
+	Sort      string
+	Ascending bool
+
+	// only archived or not archived chats, nil means all
+	Archived *bool
+
+	Offset int
+	Limit  int
+}
+
+// Normalize applies the default sorting and limits
+func (source *QpStoredChatsQuery) Normalize() {
+	source.Sort = strings.ToLower(strings.TrimSpace(source.Sort))
+	switch source.Sort {
+	case "unread", "title":
+	default:
+		source.Sort = "timestamp"
+	}
+
+	if source.Offset < 0 {
+		source.Offset = 0
+	}
+
+	if source.Limit <= 0 {
+		source.Limit = QpStoredChatsDefaultLimit
+	} else if source.Limit > QpStoredChatsMaxLimit {
+		source.Limit = QpStoredChatsMaxLimit
+	}
+}
+
+// GetChatPreviewText returns the text to show as last message preview, truncated at synopsis length
+func GetChatPreviewText(msg *whatsapp.WhatsappMessage) string {
+	text := msg.Text
+	if len(text) == 0 && msg.Attachment != nil {
+		text = msg.Attachment.FileName
+	}
+
+	maxlength := int(ENV.SynopsisLength())
+	if maxlength > 4 && len(text) > maxlength {
+		runes := []rune(text)
+		if len(runes) > maxlength-4 {
+			text = string(runes[:maxlength-4]) + " ..."
+		}
+	}
+	return text
+}
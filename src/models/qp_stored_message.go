@@ -0,0 +1,190 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+const (
+	QpStoredMessagesDefaultLimit = 50
+	QpStoredMessagesMaxLimit     = 500
+)
+
+// QpStoredMessage is a message persisted on the durable message store
+type QpStoredMessage struct {
+	ServerToken      string `db:"server_token" json:"-"`
+	Id               string `db:"id" json:"id"`
+	ChatId           string `db:"chat_id" json:"chatid"`
+	ChatTitle        string `db:"chat_title" json:"chattitle,omitempty"`
+	ParticipantId    string `db:"participant_id" json:"participant,omitempty"`
+	ParticipantTitle string `db:"participant_title" json:"participanttitle,omitempty"`
+	Type             string `db:"type" json:"type"`
+	Text             string `db:"text" json:"text,omitempty"`
+	FromMe           bool   `db:"fromme" json:"fromme"`
+	FromHistory      bool   `db:"fromhistory" json:"fromhistory,omitempty"`
+	InReply          string `db:"inreply" json:"inreply,omitempty"`
+	Status           string `db:"status" json:"status,omitempty"`
+	Edited           bool   `db:"edited" json:"edited,omitempty"`
+	Revoked          bool   `db:"revoked" json:"revoked,omitempty"`
+
+	// unix milliseconds of the message, used for ordering and cursors
+	SentAt    int64     `db:"sent_at" json:"-"`
+	Timestamp time.Time `db:"-" json:"timestamp"`
+
+	// row creation
+	Created time.Time `db:"timestamp" json:"-"`
+
+	Attachment *QpStoredAttachment `db:"-" json:"attachment,omitempty"`
+	Reactions  []*QpStoredReaction `db:"-" json:"reactions,omitempty"`
+	Edits      []*QpStoredEdit     `db:"-" json:"edits,omitempty"`
+}
+
+// QpStoredAttachment holds the metadata of a stored message attachment, content is never persisted
+type QpStoredAttachment struct {
+	ServerToken string `db:"server_token" json:"-"`
+	MessageId   string `db:"message_id" json:"-"`
+	Mimetype    string `db:"mime" json:"mime"`
+	FileName    string `db:"filename" json:"filename,omitempty"`
+	FileLength  uint64 `db:"filelength" json:"filelength,omitempty"`
+	Seconds     uint32 `db:"seconds" json:"seconds,omitempty"`
+	Checksum    string `db:"checksum" json:"checksum,omitempty"`
+	Url         string `db:"url" json:"url,omitempty"`
+
+	// object storage key of the offloaded content, removed with the last message referencing it
+	StorageKey string `db:"storage_key" json:"-"`
+}
+
+// QpStoredReaction is the current reaction of a participant to a stored message
+type QpStoredReaction struct {
+	ServerToken   string `db:"server_token" json:"-"`
+	MessageId     string `db:"message_id" json:"-"`
+	ParticipantId string `db:"participant_id" json:"participant"`
+	Reaction      string `db:"reaction" json:"reaction"`
+	SentAt        int64  `db:"sent_at" json:"-"`
+}
+
+// QpStoredEdit is a previous text of an edited stored message
+type QpStoredEdit struct {
+	ID          int64  `db:"id" json:"-"`
+	ServerToken string `db:"server_token" json:"-"`
+	MessageId   string `db:"message_id" json:"-"`
+	Text        string `db:"text" json:"text"`
+	SentAt      int64  `db:"sent_at" json:"-"`
+}
+
+// QpStoredMessagesQuery filters a page of stored messages from a chat
+type QpStoredMessagesQuery struct {
+	ChatId string
+
+	// opaque cursor returned by a previous page
+	Cursor string
+
+	// only messages before / after these timestamps, zero means unbounded
+	Before time.Time
+	After  time.Time
+
+	// message type names, empty means all
+	Types []string
+
+	// oldest first, default is newest first
+	Ascending bool
+
+	Limit int
+}
+
+// Normalize trims and applies the default and max limits
+func (source *QpStoredMessagesQuery) Normalize() {
+	source.ChatId = strings.TrimSpace(source.ChatId)
+	source.Cursor = strings.TrimSpace(source.Cursor)
+
+	if source.Limit <= 0 {
+		source.Limit = QpStoredMessagesDefaultLimit
+	} else if source.Limit > QpStoredMessagesMaxLimit {
+		source.Limit = QpStoredMessagesMaxLimit
+	}
+
+	types := make([]string, 0, len(source.Types))
+	for _, name := range source.Types {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) > 0 {
+			types = append(types, name)
+		}
+	}
+	source.Types = types
+}
+
+// EncodeStoredMessageCursor generates an opaque cursor pointing to the given message
+func EncodeStoredMessageCursor(msg *QpStoredMessage) string {
+	if msg == nil {
+		return ""
+	}
+
+	raw := fmt.Sprintf("%d:%s", msg.SentAt, msg.Id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeStoredMessageCursor reads the position of an opaque cursor
+func DecodeStoredMessageCursor(cursor string) (sentAt int64, id string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid cursor: %s", err.Error())
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return 0, "", fmt.Errorf("invalid cursor")
+	}
+
+	sentAt, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid cursor: %s", err.Error())
+	}
+
+	return sentAt, parts[1], nil
+}
+
+// NewQpStoredMessage converts a whatsapp message into its stored form
+func NewQpStoredMessage(token string, msg *whatsapp.WhatsappMessage) *QpStoredMessage {
+	stored := &QpStoredMessage{
+		ServerToken: token,
+		Id:          strings.ToUpper(msg.Id),
+		ChatId:      msg.Chat.Id,
+		ChatTitle:   msg.Chat.Title,
+		Type:        msg.Type.String(),
+		Text:        msg.Text,
+		FromMe:      msg.FromMe,
+		FromHistory: msg.FromHistory,
+		InReply:     strings.ToUpper(msg.InReply),
+		Status:      string(msg.Status),
+		Edited:      msg.Edited,
+		Revoked:     msg.Type == whatsapp.RevokeMessageType,
+		SentAt:      msg.Timestamp.UnixMilli(),
+		Timestamp:   msg.Timestamp,
+	}
+
+	if msg.Participant != nil {
+		stored.ParticipantId = msg.Participant.Id
+		stored.ParticipantTitle = msg.Participant.Title
+	}
+
+	if attach := msg.Attachment; attach != nil {
+		stored.Attachment = &QpStoredAttachment{
+			ServerToken: token,
+			MessageId:   stored.Id,
+			Mimetype:    attach.Mimetype,
+			FileName:    attach.FileName,
+			FileLength:  attach.FileLength,
+			Seconds:     attach.Seconds,
+			Checksum:    attach.Checksum,
+			Url:         attach.Url,
+			StorageKey:  attach.StorageKey,
+		}
+	}
+
+	return stored
+}
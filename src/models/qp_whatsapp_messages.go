@@ -232,6 +232,33 @@ func (source *QpWhatsappMessages) GetByPrefix(id string) (messages []*whatsapp.W
 	return
 }
 
+// Purge removes every cached message matching the filter, with its cached media,
+// returns how many messages and media contents were removed
+func (source *QpWhatsappMessages) Purge(match func(*whatsapp.WhatsappMessage) bool) (messages int, media int) {
+	if source.backend == nil {
+		return
+	}
+
+	for _, entry := range source.listRecords() {
+		msg := entry.Record.Message
+		if msg == nil || !match(msg) {
+			continue
+		}
+
+		if err := source.backend.Delete(entry.Key); err != nil {
+			qplog.Errorf("failed to purge message cache record: %v", err)
+			continue
+		}
+
+		messages++
+		if msg.Attachment != nil && msg.Attachment.HasContent() {
+			media++
+		}
+	}
+
+	return
+}
+
 func (source *QpWhatsappMessages) CleanUp(max uint64) {
 	if max == 0 {
 		return
@@ -314,4 +341,54 @@ func (source *QpWhatsappMessages) MessageStatusUpdate(id string, status whatsapp
 	return false
 }
 
+// StatusViewed records a viewer of a cached status post, returns false when the
+// status is unknown or the viewer was already recorded
+func (source *QpWhatsappMessages) StatusViewed(id string, viewer whatsapp.WhatsappStatusViewer) bool {
+	normalizedId := strings.ToUpper(id)
+
+	record, found, err := source.readRecord(normalizedId)
+	if err != nil {
+		qplog.Errorf("failed to read status record: %v", err)
+		return false
+	}
+
+	if !found || record.Message == nil || !record.Message.FromStatus() {
+		return false
+	}
+
+	if !record.Message.AppendViewer(viewer) {
+		return false
+	}
+
+	record.UpdatedAt = time.Now()
+	return source.writeRecord(normalizedId, record)
+}
+
+// MessageReceipt records a participant receipt on a cached group message, returns
+// false when the message is unknown or the receipt was already recorded
+func (source *QpWhatsappMessages) MessageReceipt(id string, receipt whatsapp.WhatsappMessageReceipt) bool {
+	normalizedId := strings.ToUpper(id)
+
+	record, found, err := source.readRecord(normalizedId)
+	if err != nil {
+		qplog.Errorf("failed to read message record: %v", err)
+		return false
+	}
+
+	if !found || record.Message == nil {
+		return false
+	}
+
+	if record.Message.Receipts == nil {
+		record.Message.Receipts = &whatsapp.WhatsappMessageReceipts{}
+	}
+
+	if !record.Message.Receipts.Append(receipt) {
+		return false
+	}
+
+	record.UpdatedAt = time.Now()
+	return source.writeRecord(normalizedId, record)
+}
+
 //#endregion
@@ -273,6 +273,55 @@ func TestQpWhatsappMessagesMessageStatusUpdate(t *testing.T) {
 	}
 }
 
+// TestQpWhatsappMessagesStatusViewed verifies viewers tracking on own status posts.
+func TestQpWhatsappMessagesStatusViewed(t *testing.T) {
+	messages := &QpWhatsappMessages{}
+	backend := cache_memory.NewMessagesBackend()
+	defer backend.Close()
+	messages.SetBackend(backend)
+
+	status := &whatsapp.WhatsappMessage{
+		Id:        "test-status-viewed",
+		Timestamp: time.Now(),
+		Chat:      whatsapp.WhatsappChat{Id: "status@broadcast"},
+		FromMe:    true,
+	}
+	messages.Append(status, "test")
+
+	regular := &whatsapp.WhatsappMessage{
+		Id:        "test-regular-viewed",
+		Timestamp: time.Now(),
+		Chat:      whatsapp.WhatsappChat{Id: "5521999999999@s.whatsapp.net"},
+	}
+	messages.Append(regular, "test")
+
+	viewer := whatsapp.WhatsappStatusViewer{
+		Participant: whatsapp.WhatsappChat{Id: "5521988888888@s.whatsapp.net"},
+		Timestamp:   time.Now(),
+	}
+
+	if !messages.StatusViewed("test-status-viewed", viewer) {
+		t.Fatalf("StatusViewed() should return true for a new viewer")
+	}
+
+	if messages.StatusViewed("TEST-STATUS-VIEWED", viewer) {
+		t.Errorf("StatusViewed() should return false for a known viewer")
+	}
+
+	if messages.StatusViewed("test-regular-viewed", viewer) {
+		t.Errorf("StatusViewed() should return false for non status messages")
+	}
+
+	cached, err := messages.GetById("test-status-viewed")
+	if err != nil {
+		t.Fatalf("GetById() error: %v", err)
+	}
+
+	if len(cached.Viewers) != 1 || cached.Viewers[0].Participant.Id != viewer.Participant.Id {
+		t.Errorf("unexpected viewers: %+v", cached.Viewers)
+	}
+}
+
 // TestQpWhatsappMessagesNilBackend verifies error handling when backend is nil.
 func TestQpWhatsappMessagesNilBackend(t *testing.T) {
 	messages := &QpWhatsappMessages{}
@@ -317,3 +366,57 @@ func TestQpWhatsappMessagesBackendInjection(t *testing.T) {
 		t.Errorf("Backend should be set after injection")
 	}
 }
+
+// TestQpWhatsappMessagesMessageReceipt verifies per participant receipts on group messages.
+func TestQpWhatsappMessagesMessageReceipt(t *testing.T) {
+	messages := &QpWhatsappMessages{}
+	backend := cache_memory.NewMessagesBackend()
+	defer backend.Close()
+	messages.SetBackend(backend)
+
+	msg := &whatsapp.WhatsappMessage{
+		Id:        "test-group-receipt",
+		Timestamp: time.Now(),
+		Chat:      whatsapp.WhatsappChat{Id: "120363000000000000@g.us"},
+		FromMe:    true,
+	}
+	messages.Append(msg, "test")
+
+	receipt := whatsapp.WhatsappMessageReceipt{
+		Participant: "5521988888888@s.whatsapp.net",
+		Status:      whatsapp.WhatsappMessageStatusDelivered,
+		Timestamp:   time.Now(),
+		Recipients:  30,
+	}
+
+	if !messages.MessageReceipt("test-group-receipt", receipt) {
+		t.Fatalf("MessageReceipt() should return true for a new receipt")
+	}
+
+	if messages.MessageReceipt("TEST-GROUP-RECEIPT", receipt) {
+		t.Errorf("MessageReceipt() should return false for a known receipt")
+	}
+
+	receipt.Status = whatsapp.WhatsappMessageStatusPlayed
+	if !messages.MessageReceipt("test-group-receipt", receipt) {
+		t.Errorf("MessageReceipt() should return true for a played receipt")
+	}
+
+	if messages.MessageReceipt("test-unknown-receipt", receipt) {
+		t.Errorf("MessageReceipt() should return false for unknown messages")
+	}
+
+	cached, err := messages.GetById("test-group-receipt")
+	if err != nil {
+		t.Fatalf("GetById() error: %v", err)
+	}
+
+	receipts := cached.Receipts
+	if receipts == nil || receipts.Recipients != 30 {
+		t.Fatalf("expected 30 recipients, got %+v", receipts)
+	}
+
+	if receipts.CountDelivered() != 1 || receipts.CountRead() != 1 || receipts.CountPlayed() != 1 {
+		t.Errorf("unexpected counts, delivered: %d, read: %d, played: %d", receipts.CountDelivered(), receipts.CountRead(), receipts.CountPlayed())
+	}
+}
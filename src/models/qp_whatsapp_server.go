@@ -19,10 +19,13 @@ type QpWhatsappServer struct {
 
 	Timestamps QpTimestamps `json:"timestamps"`
 
-	Handler        *DispatchingHandler `json:"-"`
-	GroupManager   *QpGroupManager     `json:"-"` // composition for group operations
-	StatusManager  *QpStatusManager    `json:"-"` // composition for status operations
-	ContactManager *QpContactManager   `json:"-"` // composition for contact operations
+	Handler         *DispatchingHandler `json:"-"`
+	GroupManager    *QpGroupManager     `json:"-"` // composition for group operations
+	StatusManager   *QpStatusManager    `json:"-"` // composition for status operations
+	ContactManager  *QpContactManager   `json:"-"` // composition for contact operations
+	PrivacyManager  *QpPrivacyManager   `json:"-"` // composition for account privacy operations
+	ProfileManager  *QpProfileManager   `json:"-"` // composition for own profile operations
+	BusinessManager *QpBusinessManager  `json:"-"` // composition for business profile and catalog operations
 
 	// Intent tracks the current application-level lifecycle request for this session.
 	// Use IsStopRequested() / IsDeleteRequested() instead of reading the field directly.
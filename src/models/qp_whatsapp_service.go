@@ -43,6 +43,15 @@ func QPWhatsappStart(logentry log.Logger) error {
 		logentry = logentry.WithLevel(loglevel)
 		WhatsappService.LogEntry = logentry
 
+		// attachments object storage, optional
+		InitializeMediaStorage(logentry)
+
+		// speech to text of inbound audios, optional
+		InitializeTranscriber(logentry)
+
+		// antivirus of inbound and outbound attachments, optional
+		InitializeAntivirus(logentry)
+
 		// seeding database
 		err := InitialSeed()
 		if err != nil {
@@ -87,16 +87,31 @@ func (c *pairingTestConnection) Dispose(string)              { c.disposed = true
 func (c *pairingTestConnection) Delete() error               { return nil }
 func (c *pairingTestConnection) IsInterfaceNil() bool        { return c == nil }
 func (c *pairingTestConnection) HistorySync(time.Time) error { return nil }
+func (c *pairingTestConnection) HistorySyncChat(whatsapp.WhatsappHistoryAnchor, int) error {
+	return nil
+}
 func (c *pairingTestConnection) PairPhone(string) (string, error) {
 	return "", nil
 }
 func (c *pairingTestConnection) SendChatPresence(string, uint) error { return nil }
+func (c *pairingTestConnection) SubscribePresence(string) (bool, error) {
+	return false, nil
+}
 func (c *pairingTestConnection) GetStatusManager() whatsapp.WhatsappStatusManagerInterface {
 	return nil
 }
 func (c *pairingTestConnection) GetContactManager() whatsapp.WhatsappContactManagerInterface {
 	return nil
 }
+func (c *pairingTestConnection) GetPrivacyManager() whatsapp.WhatsappPrivacyManagerInterface {
+	return nil
+}
+func (c *pairingTestConnection) GetProfileManager() whatsapp.WhatsappProfileManagerInterface {
+	return nil
+}
+func (c *pairingTestConnection) GetBusinessManager() whatsapp.WhatsappBusinessManagerInterface {
+	return nil
+}
 func (c *pairingTestConnection) GetResume() *whatsapp.WhatsappConnectionStatus { return nil }
 func (c *pairingTestConnection) GetOptions() *whatsapp.WhatsappOptions         { return &whatsapp.WhatsappOptions{} }
 func (c *pairingTestConnection) SetOptions(*whatsapp.WhatsappOptions)          {}
@@ -113,7 +128,16 @@ func (pairingTestHandlers) Message(*whatsapp.WhatsappMessage, string) {}
 func (pairingTestHandlers) MessageStatusUpdate(string, whatsapp.WhatsappMessageStatus) bool {
 	return false
 }
+func (pairingTestHandlers) StatusViewed(string, whatsapp.WhatsappStatusViewer) bool {
+	return false
+}
+func (pairingTestHandlers) MessageReceipt(string, whatsapp.WhatsappMessageReceipt) bool {
+	return false
+}
 func (pairingTestHandlers) Receipt(*whatsapp.WhatsappMessage)                   {}
+func (pairingTestHandlers) Presence(*whatsapp.WhatsappMessage)                  {}
+func (pairingTestHandlers) ChatState(*whatsapp.WhatsappChatState)               {}
+func (pairingTestHandlers) HistorySynced(*whatsapp.WhatsappHistoryChatSync)     {}
 func (pairingTestHandlers) LoggedOut(string)                                    {}
 func (pairingTestHandlers) GetLeading() *whatsapp.WhatsappMessage               { return nil }
 func (pairingTestHandlers) GetById(string) (*whatsapp.WhatsappMessage, error)   { return nil, nil }
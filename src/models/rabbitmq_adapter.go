@@ -49,6 +49,7 @@ var GlobalRabbitMQRoutingKeyEvents = "events"
 // Metric hooks used by models without importing rabbitmq metrics directly.
 var GlobalRabbitMQMessagesPublishedInc = func(queue string) {}
 var GlobalRabbitMQMessagePublishErrorsInc = func() {}
+var GlobalRabbitMQCacheSizeAdd = func(delta float64) {}
 
 func IncrementRabbitMQMessagesPublished(queue string) {
 	transportServicesMu.RLock()
@@ -64,6 +65,13 @@ func IncrementRabbitMQMessagePublishErrors() {
 	inc()
 }
 
+func AddRabbitMQCacheSize(delta float64) {
+	transportServicesMu.RLock()
+	add := GlobalRabbitMQCacheSizeAdd
+	transportServicesMu.RUnlock()
+	add(delta)
+}
+
 func GetRabbitMQExchangeName() string {
 	transportServicesMu.RLock()
 	name := GlobalRabbitMQExchangeName
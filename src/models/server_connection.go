@@ -65,6 +65,8 @@ func (source *QpWhatsappServer) EnsureUnderlying() (err error) {
 		// WhatsappOptions so the connection/handler layer can activate the
 		// VoIP/SIP bridge accordingly (disabled/exclusive/additional).
 		source.WhatsappOptions.VoIPMode = source.GetVoIPMode()
+		source.WhatsappOptions.Statuses = source.GetStatuses()
+		source.WhatsappOptions.Presences = source.GetPresences()
 
 		options := &whatsapp.WhatsappConnectionOptions{
 			WhatsappOptions: &source.WhatsappOptions,
@@ -278,6 +280,27 @@ func (source *QpWhatsappServer) GetChatTitle(wid string) string {
 	return conn.GetChatTitle(wid)
 }
 
+// GetChatTitles returns the titles of many chats at once, by chat id, resolved one by one when the
+// connection has no batch lookup
+func (source *QpWhatsappServer) GetChatTitles(wids []string) map[string]string {
+	titles := map[string]string{}
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return titles
+	}
+
+	if resolver, ok := conn.(whatsapp.IWhatsappConnectionWithChatTitles); ok {
+		return resolver.GetChatTitles(wids)
+	}
+
+	for _, wid := range wids {
+		if title := conn.GetChatTitle(wid); len(title) > 0 {
+			titles[wid] = title
+		}
+	}
+	return titles
+}
+
 // Usado para exibir os servidores/bots de cada usuario em suas respectivas telas
 func (server *QpWhatsappServer) GetOwnerID() string {
 	return server.GetUser()
@@ -34,6 +34,13 @@ func (source *QpWhatsappServer) Download(id string, cache bool) (att *whatsapp.W
 		return
 	}
 
+	// withheld by antivirus on dispatch
+	if msg.Attachment != nil {
+		if err = GetAntivirusBlockedError(msg.Attachment.Scan); err != nil {
+			return
+		}
+	}
+
 	logentry := source.GetLogger()
 	logentry = logentry.WithField(LogFields.MessageId, id)
 	logentry.Infof("downloading msg attachment, using cache: %v", cache)
@@ -43,6 +50,18 @@ func (source *QpWhatsappServer) Download(id string, cache bool) (att *whatsapp.W
 		return
 	}
 
+	// not scanned on dispatch, as history or sent messages
+	if att != nil && att.Scan == nil && att.GetContent() != nil {
+		scan := ScanAttachmentContent(logentry, att, *att.GetContent(), QpAntivirusInbound, id)
+		if msg.Attachment != nil && msg.Attachment != att {
+			msg.Attachment.Scan = scan
+		}
+
+		if err = GetAntivirusBlockedError(scan); err != nil {
+			return nil, err
+		}
+	}
+
 	return
 }
 
@@ -126,28 +145,16 @@ func (source *QpWhatsappServer) SendMessage(msg *whatsapp.WhatsappMessage) (resp
 		return
 	}
 
-	// Normalize Brazilian mobile phone number (handles 8/9-digit ambiguity).
+	// Normalize phones of countries with more than one registered form, e.g. the BR ninth digit.
 	// Queries IsOnWhatsApp at most once per phone per session (cached in WhatsmeowContactMaps).
-	if ENV.ShouldNormalizeBRPhone() {
+	if regions := ENV.PhoneNormalizationRegions(); len(regions) > 0 {
 
 		phone, _ := whatsapp.GetPhoneIfValid(msg.Chat.Id)
 		if len(phone) > 0 {
-			// Try remove-9: 9-digit (14 chars) → 8-digit variant (DDDs > 30)
-			phoneWithout9, errRemove := library.RemoveDigit9IfElegible(phone)
-
-			// Try add-9: 8-digit (13 chars) → 9-digit variant (all Brazilian DDDs)
-			phoneWith9, errAdd := library.AddDigit9BRAllDDDs(phone)
-
-			var variant string
-			if errRemove == nil {
-				variant = phoneWithout9
-			} else if errAdd == nil {
-				variant = phoneWith9
-			}
-
-			if len(variant) > 0 {
+			variants := library.GetPhoneVariants(phone, true, regions...)
+			if len(variants) > 0 {
 				contactManager := source.GetContactManager()
-				valids, err := contactManager.IsOnWhatsApp(phone, variant)
+				valids, err := contactManager.IsOnWhatsApp(append([]string{phone}, variants...)...)
 				if err != nil {
 					return nil, err
 				}
@@ -164,6 +171,14 @@ func (source *QpWhatsappServer) SendMessage(msg *whatsapp.WhatsappMessage) (resp
 	// Trick to send audio with text, creating a new msg
 	if msg.HasAttachment() {
 
+		// scanning before upload, infected files are not sent unless policy only reports
+		if content := msg.Attachment.GetContent(); content != nil {
+			scan := ScanAttachmentContent(logger, msg.Attachment, *content, QpAntivirusOutbound, msg.Id)
+			if err = GetAntivirusBlockedError(scan); err != nil {
+				return
+			}
+		}
+
 		// Overriding filename with caption text if IMAGE or VIDEO
 		if len(msg.Text) > 0 && msg.Type == whatsapp.AudioMessageType {
 
@@ -251,6 +266,30 @@ func (server *QpWhatsappServer) GetContactManager() whatsapp.WhatsappContactMana
 	return server.ContactManager
 }
 
+// GetPrivacyManager returns the privacy manager instance with lazy initialization
+func (server *QpWhatsappServer) GetPrivacyManager() whatsapp.WhatsappPrivacyManagerInterface {
+	if server.PrivacyManager == nil {
+		server.PrivacyManager = NewQpPrivacyManager(server)
+	}
+	return server.PrivacyManager
+}
+
+// GetProfileManager returns the profile manager instance with lazy initialization
+func (server *QpWhatsappServer) GetProfileManager() whatsapp.WhatsappProfileManagerInterface {
+	if server.ProfileManager == nil {
+		server.ProfileManager = NewQpProfileManager(server)
+	}
+	return server.ProfileManager
+}
+
+// GetBusinessManager returns the business manager instance with lazy initialization
+func (server *QpWhatsappServer) GetBusinessManager() whatsapp.WhatsappBusinessManagerInterface {
+	if server.BusinessManager == nil {
+		server.BusinessManager = NewQpBusinessManager(server)
+	}
+	return server.BusinessManager
+}
+
 func (server *QpWhatsappServer) SendChatPresence(chatId string, presenceType whatsapp.WhatsappChatPresenceType) error {
 	conn, err := server.GetValidConnection()
 	if err != nil {
@@ -259,6 +298,15 @@ func (server *QpWhatsappServer) SendChatPresence(chatId string, presenceType wha
 	return conn.SendChatPresence(chatId, uint(presenceType))
 }
 
+// SubscribePresence subscribes to presence updates of a contact, returning whether a privacy token exists
+func (server *QpWhatsappServer) SubscribePresence(chatId string) (bool, error) {
+	conn, err := server.GetValidConnection()
+	if err != nil {
+		return false, err
+	}
+	return conn.SubscribePresence(chatId)
+}
+
 func (server *QpWhatsappServer) GetLIDFromPhone(phone string) (string, error) {
 	contactManager := server.GetContactManager()
 	return contactManager.GetLIDFromPhone(phone)
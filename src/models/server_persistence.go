@@ -167,6 +167,21 @@ func (server *QpWhatsappServer) Delete(cause string) (err error) {
 		return fmt.Errorf("whatsapp server, database delete connection, error: %s", err.Error())
 	}
 
+	if store, ok := GetMessageStore(); ok {
+		if storeErr := store.DeleteByServer(server.Token); storeErr != nil {
+			server.GetLogger().Errorf("error deleting stored messages: %s", storeErr.Error())
+		}
+	}
+
+	if chats, ok := GetChatStore(); ok {
+		if chatsErr := chats.DeleteByServer(server.Token); chatsErr != nil {
+			server.GetLogger().Errorf("error deleting stored chats: %s", chatsErr.Error())
+		}
+	}
+
+	HistoryJobs.DeleteByServer(server.Token)
+	ChatExportJobs.DeleteByServer(server.Token)
+
 	if len(dispatchingSnapshot) > 0 {
 		deleteEvent := NewServerDeletedEvent(server, cause, &previousState)
 		dispatchErr := PostToDispatchings(server, dispatchingSnapshot, deleteEvent)
@@ -18,6 +18,7 @@ type TransportServices struct {
 	RabbitMQRoutingKeyEvents        string
 	RabbitMQMessagesPublishedInc    func(queue string)
 	RabbitMQMessagePublishErrorsInc func()
+	RabbitMQCacheSizeAdd            func(delta float64)
 	RabbitMQClientResolver          func(connectionString string) bool
 }
 
@@ -60,6 +61,9 @@ func ApplyTransportServices(services TransportServices) {
 	if services.RabbitMQMessagePublishErrorsInc != nil {
 		GlobalRabbitMQMessagePublishErrorsInc = services.RabbitMQMessagePublishErrorsInc
 	}
+	if services.RabbitMQCacheSizeAdd != nil {
+		GlobalRabbitMQCacheSizeAdd = services.RabbitMQCacheSizeAdd
+	}
 	if services.RabbitMQClientResolver != nil {
 		GlobalRabbitMQClientResolver = services.RabbitMQClientResolver
 	}
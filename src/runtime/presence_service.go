@@ -0,0 +1,30 @@
+package runtime
+
+import (
+	"fmt"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// GetSessionPresences returns whether presence updates reach webhooks for this
+// session. Defaults to false when never configured.
+func GetSessionPresences(server *models.QpWhatsappServer) (bool, error) {
+	if server == nil {
+		return false, ErrNilSession
+	}
+	return server.GetPresences(), nil
+}
+
+// SetSessionPresences persists the presences opt-in into the server metadata
+// and saves the instance to the database. Realtime subscribers are not affected.
+func SetSessionPresences(server *models.QpWhatsappServer, enabled bool) error {
+	if server == nil {
+		return ErrNilSession
+	}
+
+	server.SetPresences(enabled)
+	if err := server.Save(fmt.Sprintf("presences dispatching set to %v", enabled)); err != nil {
+		return fmt.Errorf("failed to persist presences option: %w", err)
+	}
+	return nil
+}
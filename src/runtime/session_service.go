@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	library "github.com/nocodeleaks/quepasa/library"
 	models "github.com/nocodeleaks/quepasa/models"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 )
@@ -18,6 +19,10 @@ type SessionConfigurationPatch struct {
 	ReadUpdate       *whatsapp.WhatsappBoolean
 	Direct           *whatsapp.WhatsappBoolean
 	Devel            *bool
+	Region           *string
+	TranscodeVideo   *bool
+	Transcribe       *bool
+	TranscribeLanguage *string
 }
 
 var ErrNilSession = errors.New("session is nil")
@@ -140,10 +145,38 @@ func BuildSessionRecord(token string, username string, patch *SessionConfigurati
 	if patch.Devel != nil {
 		info.Devel = *patch.Devel
 	}
+	if patch.Region != nil {
+		// already checked by ValidateSessionConfigurationPatch
+		_ = info.SetRegion(*patch.Region)
+	}
+	if patch.TranscodeVideo != nil {
+		info.SetTranscodeVideo(*patch.TranscodeVideo)
+	}
+	if patch.Transcribe != nil {
+		info.SetTranscribe(*patch.Transcribe)
+	}
+	if patch.TranscribeLanguage != nil {
+		info.SetTranscribeLanguage(*patch.TranscribeLanguage)
+	}
 
 	return info
 }
 
+// ValidateSessionConfigurationPatch checks patch values that may be rejected, before creating a record.
+func ValidateSessionConfigurationPatch(patch *SessionConfigurationPatch) error {
+	if patch == nil {
+		return nil
+	}
+
+	if patch.Region != nil {
+		if _, err := library.GetPhoneRegionOrError(*patch.Region); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // FindLiveSessionByToken looks up a live in-memory session by token without
 // materializing persisted records.
 func FindLiveSessionByToken(token string) (*models.QpWhatsappSession, bool) {
@@ -556,6 +589,35 @@ func ApplySessionConfigurationPatch(session *models.QpWhatsappSession, patch *Se
 		update += fmt.Sprintf("devel to: {%t}; ", *patch.Devel)
 	}
 
+	if patch.Region != nil {
+		region, err := library.GetPhoneRegionOrError(*patch.Region)
+		if err != nil {
+			return "", err
+		}
+
+		if session.GetRegion() != region {
+			if err := session.SetRegion(region); err != nil {
+				return "", err
+			}
+			update += fmt.Sprintf("region to: {%s}; ", region)
+		}
+	}
+
+	if patch.TranscodeVideo != nil && session.GetTranscodeVideo() != *patch.TranscodeVideo {
+		session.SetTranscodeVideo(*patch.TranscodeVideo)
+		update += fmt.Sprintf("transcodevideo to: {%t}; ", *patch.TranscodeVideo)
+	}
+
+	if patch.Transcribe != nil && session.GetTranscribe() != *patch.Transcribe {
+		session.SetTranscribe(*patch.Transcribe)
+		update += fmt.Sprintf("transcribe to: {%t}; ", *patch.Transcribe)
+	}
+
+	if patch.TranscribeLanguage != nil && session.GetTranscribeLanguage() != *patch.TranscribeLanguage {
+		session.SetTranscribeLanguage(*patch.TranscribeLanguage)
+		update += fmt.Sprintf("transcribelanguage to: {%s}; ", session.GetTranscribeLanguage())
+	}
+
 	return update, nil
 }
 
@@ -0,0 +1,31 @@
+package runtime
+
+import (
+	"fmt"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// GetSessionStatuses returns whether contacts statuses are dispatched for this
+// session. Defaults to false when never configured.
+func GetSessionStatuses(server *models.QpWhatsappServer) (bool, error) {
+	if server == nil {
+		return false, ErrNilSession
+	}
+	return server.GetStatuses(), nil
+}
+
+// SetSessionStatuses persists the statuses opt-in into the server metadata and
+// saves the instance to the database. The live connection shares the options,
+// so the change applies immediately.
+func SetSessionStatuses(server *models.QpWhatsappServer, enabled bool) error {
+	if server == nil {
+		return ErrNilSession
+	}
+
+	server.SetStatuses(enabled)
+	if err := server.Save(fmt.Sprintf("statuses dispatching set to %v", enabled)); err != nil {
+		return fmt.Errorf("failed to persist statuses option: %w", err)
+	}
+	return nil
+}
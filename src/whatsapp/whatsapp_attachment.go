@@ -2,6 +2,7 @@ package whatsapp
 
 import (
 	"strings"
+	"time"
 )
 
 type WhatsappAttachment struct {
@@ -15,12 +16,18 @@ type WhatsappAttachment struct {
 	// document
 	FileName string `json:"filename,omitempty"`
 
+	// document, pages of pdf and office files
+	PageCount uint32 `json:"pagecount,omitempty"`
+
 	// small image representing something in this message, MIME: image/jpeg
 	Thumbnail *WhatsappMessageThumbnail `json:"thumbnail,omitempty"`
 
 	// audio/video
 	Seconds uint32 `json:"seconds,omitempty"`
 
+	// sticker, animated webp
+	Animated bool `json:"animated,omitempty"`
+
 	// audio, used for define that this attach should be sent as ptt compatible, regards its incompatible mime type
 	ptt bool `json:"-"`
 
@@ -32,8 +39,20 @@ type WhatsappAttachment struct {
 	// Public access url helper content
 	Url string `json:"url,omitempty"`
 
+	// Expiration of the url, set for pre-signed object storage links
+	UrlExpires *time.Time `json:"urlexpires,omitempty"`
+
+	// Object storage key of the offloaded content, content addressed, may be shared by messages
+	StorageKey string `json:"storagekey,omitempty"`
+
 	WaveForm []byte `json:"waveform,omitempty"`
 
+	// audio, speech to text of inbound voice notes, when enabled for the server
+	Transcript string `json:"transcript,omitempty"`
+
+	// antivirus verdict, when a scanner is enabled
+	Scan *WhatsappAttachmentScan `json:"scan,omitempty"`
+
 	// Checksum for the message, used to verify integrity
 	// and avoid duplicates
 	Checksum string `json:"checksum,omitempty"`
@@ -0,0 +1,31 @@
+package whatsapp
+
+import "time"
+
+// antivirus scan status of attachments
+const (
+	WhatsappAttachmentScanClean    = "clean"
+	WhatsappAttachmentScanInfected = "infected"
+	WhatsappAttachmentScanError    = "error"
+)
+
+// WhatsappAttachmentScan is the antivirus verdict of an attachment
+type WhatsappAttachmentScan struct {
+	Scanner string `json:"scanner"`
+
+	// clean, infected or error, when it could not be scanned
+	Status string `json:"status"`
+
+	// malware name reported by the scanner
+	Signature string `json:"signature,omitempty"`
+
+	// blocked or quarantined, empty when the content is still available
+	Action string `json:"action,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IsBlocked reports whether the content of the attachment must not be delivered
+func (source *WhatsappAttachmentScan) IsBlocked() bool {
+	return source != nil && len(source.Action) > 0
+}
@@ -0,0 +1,76 @@
+package whatsapp
+
+import "math"
+
+// WhatsappBusinessProfile public information of a WhatsApp Business account
+type WhatsappBusinessProfile struct {
+	Wid         string                  `json:"wid"`
+	Categories  []string                `json:"categories,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Address     string                  `json:"address,omitempty"`
+	Email       string                  `json:"email,omitempty"`
+	Websites    []string                `json:"websites,omitempty"`
+	TimeZone    string                  `json:"timezone,omitempty"`
+	Hours       []WhatsappBusinessHours `json:"hours,omitempty"`
+}
+
+// WhatsappBusinessHours opening hours of a single week day
+type WhatsappBusinessHours struct {
+	Day   string `json:"day"`             // sun, mon, tue, ...
+	Mode  string `json:"mode"`            // specific_hours, open_24h, appointment_only
+	Open  string `json:"open,omitempty"`  // minutes from midnight, as sent by whatsapp
+	Close string `json:"close,omitempty"` // minutes from midnight, as sent by whatsapp
+}
+
+// WhatsappProduct a catalog item, used on catalog listing, product messages and product inquiries
+type WhatsappProduct struct {
+	Id          string  `json:"id"`
+	RetailerId  string  `json:"retailerid,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Url         string  `json:"url,omitempty"`
+	ImageUrl    string  `json:"imageurl,omitempty"`
+	Price       float64 `json:"price,omitempty"`
+	Currency    string  `json:"currency,omitempty"`
+	Hidden      bool    `json:"hidden,omitempty"`
+
+	// Business that owns this product, empty means this session
+	Owner string `json:"owner,omitempty"`
+}
+
+// WhatsappCatalog a page of products from a business catalog
+type WhatsappCatalog struct {
+	Wid      string            `json:"wid"`
+	Products []WhatsappProduct `json:"products"`
+
+	// Cursor for the next page, empty when there are no more products
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// WhatsappCatalogShare sends a link to the catalog of this session
+type WhatsappCatalogShare struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// WhatsappOrder an order placed by a customer from a business catalog
+type WhatsappOrder struct {
+	Id        string  `json:"id"`
+	Title     string  `json:"title,omitempty"`
+	ItemCount int32   `json:"itemcount,omitempty"`
+	Status    string  `json:"status,omitempty"`
+	Seller    string  `json:"seller,omitempty"`
+	Token     string  `json:"token,omitempty"`
+	Total     float64 `json:"total,omitempty"`
+	Currency  string  `json:"currency,omitempty"`
+}
+
+// AmountFromThousandths converts whatsapp amounts (value * 1000) to decimal values
+func AmountFromThousandths(amount int64) float64 {
+	return float64(amount) / 1000
+}
+
+// AmountToThousandths converts decimal values to whatsapp amounts (value * 1000)
+func AmountToThousandths(amount float64) int64 {
+	return int64(math.Round(amount * 1000))
+}
@@ -0,0 +1,16 @@
+package whatsapp
+
+// WhatsappBusinessManagerInterface defines read operations over WhatsApp Business profiles and catalogs
+type WhatsappBusinessManagerInterface interface {
+	// GetBusinessProfile returns the business profile of a contact, empty wid means this session
+	GetBusinessProfile(wid string) (*WhatsappBusinessProfile, error)
+
+	// GetCatalog returns a page of the catalog of a business, empty wid means this session
+	GetCatalog(wid string, limit int, cursor string) (*WhatsappCatalog, error)
+}
+
+// IWhatsappConnectionWithBusiness extends IWhatsappConnection with business capabilities
+type IWhatsappConnectionWithBusiness interface {
+	IWhatsappConnection
+	GetBusinessManager() WhatsappBusinessManagerInterface
+}
@@ -0,0 +1,27 @@
+package whatsapp
+
+import "time"
+
+// WhatsappChatState is a partial update of a chat list entry, nil fields are unchanged.
+// Generated from history sync conversations and app state events (archive, pin, mute, read).
+type WhatsappChatState struct {
+	Id    string `json:"id"`
+	Title string `json:"title,omitempty"`
+
+	Archived *bool `json:"archived,omitempty"`
+	Pinned   *bool `json:"pinned,omitempty"`
+
+	// unix milliseconds until the chat is muted, -1 forever, 0 unmuted
+	MutedUntil *int64 `json:"muteduntil,omitempty"`
+
+	Unread       *uint32 `json:"unread,omitempty"`
+	MarkedUnread *bool   `json:"markedunread,omitempty"`
+
+	// disappearing messages timer in seconds, 0 disabled
+	Ephemeral *uint32 `json:"ephemeral,omitempty"`
+
+	// chat was deleted, removed from chat list
+	Deleted bool `json:"deleted,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -72,10 +72,18 @@ type IWhatsappConnection interface {
 
 	HistorySync(time.Time) error
 
+	// HistorySyncChat requests older messages of a single chat, before the anchor message,
+	// results arrive asynchronously through history sync and IWhatsappHandlers.HistorySynced
+	HistorySyncChat(anchor WhatsappHistoryAnchor, count int) error
+
 	PairPhone(phone string) (string, error)
 
 	//region Send Presence
 	SendChatPresence(chatId string, presenceType uint) error
+
+	// SubscribePresence asks for presence updates of a contact, returns whether a privacy token
+	// exists, without it the server silently ignores the subscription
+	SubscribePresence(chatId string) (bool, error)
 	//endregion
 
 	// NOTE: Group operations have been moved to IGroupManager
@@ -90,7 +98,22 @@ type IWhatsappConnection interface {
 	// GetContactManager returns the contact manager for contact operations
 	GetContactManager() WhatsappContactManagerInterface
 
+	// GetPrivacyManager returns the privacy manager for account privacy operations
+	GetPrivacyManager() WhatsappPrivacyManagerInterface
+
+	// GetProfileManager returns the profile manager for own push name, about and photo operations
+	GetProfileManager() WhatsappProfileManagerInterface
+
+	// GetBusinessManager returns the business manager for business profile and catalog operations
+	GetBusinessManager() WhatsappBusinessManagerInterface
+
 	// GetResume returns detailed connection status information
 	// This consolidates all status management functionality in a single method
 	GetResume() *WhatsappConnectionStatus
 }
+
+// IWhatsappConnectionWithChatTitles resolves the titles of many chats at once, as for chat lists
+type IWhatsappConnectionWithChatTitles interface {
+	// GetChatTitles returns the titles by chat id, chats without title are not included
+	GetChatTitles([]string) map[string]string
+}
@@ -0,0 +1,23 @@
+package whatsapp
+
+// WhatsappContactUpdateAction identifies what changed on a contact profile
+type WhatsappContactUpdateAction string
+
+const (
+	ContactUpdatePicture  WhatsappContactUpdateAction = "picture"  // profile picture, new value is the picture id, empty when removed
+	ContactUpdatePushName WhatsappContactUpdateAction = "pushname" // name chosen by the contact
+	ContactUpdateAbout    WhatsappContactUpdateAction = "about"    // about (status text)
+	ContactUpdateIdentity WhatsappContactUpdateAction = "identity" // primary device changed, usually a reinstall or new phone
+)
+
+// WhatsappContactUpdate describes a single change on a contact profile
+type WhatsappContactUpdate struct {
+	Action WhatsappContactUpdateAction `json:"action"`
+
+	// previous value when known, from local caches
+	OldValue any `json:"oldvalue,omitempty"`
+	NewValue any `json:"newvalue,omitempty"`
+
+	// identity changes noticed from an untrusted identity error, not notified by the server
+	Implicit bool `json:"implicit,omitempty"`
+}
@@ -81,6 +81,22 @@ func FormatEndpoint(source string) (destination string, err error) {
 	return
 }
 
+// FormatEndpointWithRegion formats a destination as FormatEndpoint, but phones written on local
+// format are taken as numbers of the given default region (ISO code, e.g. BR, US)
+func FormatEndpointWithRegion(source string, region string) (destination string, err error) {
+	if len(region) == 0 || strings.ContainsAny(source, "@") {
+		return FormatEndpoint(source)
+	}
+
+	// group ids and anything not a phone of the region keep the default behavior
+	phone, err := library.ParsePhoneNumber(source, region)
+	if err != nil {
+		return FormatEndpoint(source)
+	}
+
+	return PhoneToWid(phone), nil
+}
+
 var RegexValidE164Test = string(`\d`)
 
 func IsValidE164(phone string) bool {
@@ -0,0 +1,41 @@
+package whatsapp
+
+// WhatsappGroupUpdateAction identifies what changed on a group
+type WhatsappGroupUpdateAction string
+
+const (
+	GroupUpdateJoin        WhatsappGroupUpdateAction = "join"        // participants joined or were added
+	GroupUpdateLeave       WhatsappGroupUpdateAction = "leave"       // participants left or were removed
+	GroupUpdatePromote     WhatsappGroupUpdateAction = "promote"     // participants promoted to admins
+	GroupUpdateDemote      WhatsappGroupUpdateAction = "demote"      // admins demoted to regular participants
+	GroupUpdateSubject     WhatsappGroupUpdateAction = "subject"     // group name
+	GroupUpdateDescription WhatsappGroupUpdateAction = "description" // group topic
+	GroupUpdatePhoto       WhatsappGroupUpdateAction = "photo"       // group picture, new value is the picture id, empty when removed
+	GroupUpdateLocked      WhatsappGroupUpdateAction = "locked"      // only admins can edit group info
+	GroupUpdateAnnounce    WhatsappGroupUpdateAction = "announce"    // only admins can send messages
+	GroupUpdateEphemeral   WhatsappGroupUpdateAction = "ephemeral"   // disappearing messages timer, in seconds
+	GroupUpdateApproval    WhatsappGroupUpdateAction = "approval"    // admins must approve new participants
+	GroupUpdateInviteLink  WhatsappGroupUpdateAction = "invitelink"  // invite link was reset
+	GroupUpdateLink        WhatsappGroupUpdateAction = "link"        // linked to a community
+	GroupUpdateUnlink      WhatsappGroupUpdateAction = "unlink"      // unlinked from a community
+	GroupUpdateDelete      WhatsappGroupUpdateAction = "delete"      // group was deleted
+	GroupUpdateSuspended   WhatsappGroupUpdateAction = "suspended"   // group was suspended or unsuspended
+)
+
+// WhatsappGroupUpdate describes a single change made on a group
+type WhatsappGroupUpdate struct {
+	Action WhatsappGroupUpdateAction `json:"action"`
+
+	// who made the change, not present for some server side changes
+	Actor *WhatsappChat `json:"actor,omitempty"`
+
+	// participants affected by join, leave, promote and demote actions
+	Targets []WhatsappChat `json:"targets,omitempty"`
+
+	// previous value when known, from group info cache
+	OldValue any `json:"oldvalue,omitempty"`
+	NewValue any `json:"newvalue,omitempty"`
+
+	// extra information, like "invite" for joins through invite link
+	Reason string `json:"reason,omitempty"`
+}
@@ -9,9 +9,24 @@ type IWhatsappHandlers interface {
 	// Update message status information
 	MessageStatusUpdate(id string, status WhatsappMessageStatus) bool
 
+	// Record a viewer of one of our own status posts
+	StatusViewed(id string, viewer WhatsappStatusViewer) bool
+
+	// Record a participant receipt of a group message
+	MessageReceipt(id string, receipt WhatsappMessageReceipt) bool
+
 	// Update read receipt status
 	Receipt(*WhatsappMessage)
 
+	// Presence update, dispatched without caching
+	Presence(*WhatsappMessage)
+
+	// Chat list entry update, archive, pin, mute, read and history sync conversations
+	ChatState(*WhatsappChatState)
+
+	// On demand history of a chat was received
+	HistorySynced(*WhatsappHistoryChatSync)
+
 	// Event
 	LoggedOut(string)
 
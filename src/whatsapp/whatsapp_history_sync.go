@@ -0,0 +1,22 @@
+package whatsapp
+
+import "time"
+
+// WhatsappHistoryAnchor is the oldest known message of a chat, on demand history is requested before it
+type WhatsappHistoryAnchor struct {
+	ChatId    string    `json:"chatid"`
+	MessageId string    `json:"messageid"`
+	FromMe    bool      `json:"fromme"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WhatsappHistoryChatSync is the result of an on demand history request for a chat
+type WhatsappHistoryChatSync struct {
+	ChatId string `json:"chatid"`
+
+	// messages received on this response
+	Messages int `json:"messages"`
+
+	// older messages remain on the phone
+	More bool `json:"more"`
+}
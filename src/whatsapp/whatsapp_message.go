@@ -78,6 +78,25 @@ type WhatsappMessage struct {
 	Location *WhatsappLocation `json:"location,omitempty"` // Location if exists
 	Contact  *WhatsappContact  `json:"contact,omitempty"`  // Contact if exists
 
+	Product *WhatsappProduct      `json:"product,omitempty"` // Product if exists, sent or inquired
+	Order   *WhatsappOrder        `json:"order,omitempty"`   // Order if exists
+	Catalog *WhatsappCatalogShare `json:"catalog,omitempty"` // Catalog link to send, outbound only
+
+	// Group change, for group messages generated from group info events
+	GroupUpdate *WhatsappGroupUpdate `json:"groupupdate,omitempty"`
+
+	// Contact profile change, for contact system messages
+	ContactUpdate *WhatsappContactUpdate `json:"contactupdate,omitempty"`
+
+	// Presence update, for presence system messages
+	Presence *WhatsappPresence `json:"presence,omitempty"`
+
+	// Contacts that viewed this message, only tracked for our own status posts
+	Viewers []WhatsappStatusViewer `json:"viewers,omitempty"`
+
+	// Per participant receipts, only for messages sent by us to groups
+	Receipts *WhatsappMessageReceipts `json:"receipts,omitempty"`
+
 	// Debug information for debug events
 	Debug *WhatsappMessageDebug `json:"debug,omitempty"`
 
@@ -182,6 +201,23 @@ func (source *WhatsappMessage) FromBroadcast() bool {
 	return false
 }
 
+// FromStatus returns true for statuses (stories) published on status@broadcast
+func (source *WhatsappMessage) FromStatus() bool {
+	return source.Chat.Id == "status" || source.Chat.Id == "status@broadcast"
+}
+
+// AppendViewer records a status viewer, returns false if it was already known
+func (source *WhatsappMessage) AppendViewer(viewer WhatsappStatusViewer) bool {
+	for _, existing := range source.Viewers {
+		if existing.Participant.Id == viewer.Participant.Id {
+			return false
+		}
+	}
+
+	source.Viewers = append(source.Viewers, viewer)
+	return true
+}
+
 //endregion
 
 //region DISPATCH ERROR MANAGEMENT
@@ -0,0 +1,111 @@
+package whatsapp
+
+import "time"
+
+// WhatsappMessageReceipt is a single receipt sent by one participant of a group chat
+type WhatsappMessageReceipt struct {
+	// participant that sent the receipt
+	Participant string `json:"participant"`
+
+	Status    WhatsappMessageStatus `json:"status"`
+	Timestamp time.Time             `json:"timestamp"`
+
+	// expected recipients of the message (group members except ourselves), 0 if unknown
+	Recipients int `json:"recipients,omitempty"`
+}
+
+// WhatsappParticipantReceipt holds the receipts timestamps of a single participant
+type WhatsappParticipantReceipt struct {
+	Delivered *time.Time `json:"delivered,omitempty"`
+	Read      *time.Time `json:"read,omitempty"`
+	Played    *time.Time `json:"played,omitempty"`
+}
+
+// WhatsappMessageReceipts is the per participant receipts map of a group message
+type WhatsappMessageReceipts struct {
+	// expected recipients of the message, 0 if unknown
+	Recipients int `json:"recipients,omitempty"`
+
+	Participants map[string]*WhatsappParticipantReceipt `json:"participants,omitempty"`
+}
+
+// Append records a receipt, returns false when nothing has changed
+func (source *WhatsappMessageReceipts) Append(receipt WhatsappMessageReceipt) bool {
+	if len(receipt.Participant) == 0 {
+		return false
+	}
+
+	changed := false
+	if receipt.Recipients > 0 && receipt.Recipients != source.Recipients {
+		source.Recipients = receipt.Recipients
+		changed = true
+	}
+
+	if source.Participants == nil {
+		source.Participants = make(map[string]*WhatsappParticipantReceipt)
+	}
+
+	participant, ok := source.Participants[receipt.Participant]
+	if !ok {
+		participant = &WhatsappParticipantReceipt{}
+		source.Participants[receipt.Participant] = participant
+	}
+
+	timestamp := receipt.Timestamp
+	switch receipt.Status {
+	case WhatsappMessageStatusDelivered:
+		if participant.Delivered == nil {
+			participant.Delivered = &timestamp
+			changed = true
+		}
+	case WhatsappMessageStatusRead:
+		if participant.Read == nil {
+			participant.Read = &timestamp
+			changed = true
+		}
+	case WhatsappMessageStatusPlayed:
+		if participant.Played == nil {
+			participant.Played = &timestamp
+			changed = true
+		}
+
+		// playing a voice note or video implies reading it
+		if participant.Read == nil {
+			participant.Read = &timestamp
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// CountDelivered returns how many participants received the message,
+// reading or playing implies delivery
+func (source *WhatsappMessageReceipts) CountDelivered() (count int) {
+	for _, participant := range source.Participants {
+		if participant.Delivered != nil || participant.Read != nil || participant.Played != nil {
+			count++
+		}
+	}
+	return
+}
+
+// CountRead returns how many participants read the message
+func (source *WhatsappMessageReceipts) CountRead() (count int) {
+	for _, participant := range source.Participants {
+		if participant.Read != nil || participant.Played != nil {
+			count++
+		}
+	}
+	return
+}
+
+// CountPlayed returns how many participants played the message
+func (source *WhatsappMessageReceipts) CountPlayed() (count int) {
+	for _, participant := range source.Participants {
+		if participant.Played != nil {
+			count++
+		}
+	}
+	return
+}
@@ -8,6 +8,7 @@ const (
 	WhatsappMessageStatusImported  WhatsappMessageStatus = "imported"
 	WhatsappMessageStatusDelivered WhatsappMessageStatus = "delivered"
 	WhatsappMessageStatusRead      WhatsappMessageStatus = "read"
+	WhatsappMessageStatusPlayed    WhatsappMessageStatus = "played" // voice notes and videos
 )
 
 func (source WhatsappMessageStatus) Uint32() uint {
@@ -20,6 +21,8 @@ func (source WhatsappMessageStatus) Uint32() uint {
 		return 3
 	case WhatsappMessageStatusRead:
 		return 4
+	case WhatsappMessageStatusPlayed:
+		return 5
 	}
 	return 0
 }
@@ -24,6 +24,8 @@ const (
 	RevokeMessageType
 	PollMessageType
 	StickerMessageType
+	ProductMessageType
+	OrderMessageType
 )
 
 func (s WhatsappMessageType) MarshalJSON() ([]byte, error) {
@@ -58,6 +60,10 @@ func (Type WhatsappMessageType) String() string {
 		return "poll"
 	case StickerMessageType:
 		return "sticker"
+	case ProductMessageType:
+		return "product"
+	case OrderMessageType:
+		return "order"
 	case ViewOnceMessageType:
 		return "view_once"
 	}
@@ -65,3 +71,15 @@ func (Type WhatsappMessageType) String() string {
 	// If the type is not recognized, return "unhandled"
 	return "unhandled"
 }
+
+// ParseWhatsappMessageType converts the string form back into a message type,
+// returns false for unknown names
+func ParseWhatsappMessageType(name string) (WhatsappMessageType, bool) {
+	for Type := UnhandledMessageType; Type <= OrderMessageType; Type++ {
+		if Type.String() == name {
+			return Type, true
+		}
+	}
+
+	return UnhandledMessageType, false
+}
@@ -29,4 +29,12 @@ type WhatsappOptions struct {
 	// SIP failure), or additional (SIP as extra device, leave call ringing).
 	// Persisted in server metadata, not as a dedicated DB column.
 	VoIPMode VoIPMode `json:"voipmode,omitempty"`
+
+	// Statuses enables dispatching of contacts statuses (stories), even when
+	// broadcasts are not handled. Persisted in server metadata as well.
+	Statuses bool `json:"statuses,omitempty"`
+
+	// Presences enables dispatching of presence updates to webhooks, realtime
+	// subscribers always receive them. Persisted in server metadata as well.
+	Presences bool `json:"presences,omitempty"`
 }
@@ -0,0 +1,27 @@
+package whatsapp
+
+import "time"
+
+// MetadataKeyPresences is the metadata key used to persist the per-instance
+// opt-in for dispatching presence updates to webhooks. Realtime subscribers
+// (cable/SignalR) always receive them.
+const MetadataKeyPresences = "presences"
+
+// WhatsappPresenceState is the inbound presence of a contact, online state or chat activity
+type WhatsappPresenceState string
+
+const (
+	WhatsappPresenceTyping    WhatsappPresenceState = "typing"
+	WhatsappPresenceRecording WhatsappPresenceState = "recording"
+	WhatsappPresencePaused    WhatsappPresenceState = "paused"
+	WhatsappPresenceOnline    WhatsappPresenceState = "online"
+	WhatsappPresenceOffline   WhatsappPresenceState = "offline"
+)
+
+// WhatsappPresence describes a presence update received from a contact
+type WhatsappPresence struct {
+	State WhatsappPresenceState `json:"state"`
+
+	// last time the contact was online, only for offline updates and when not hidden by privacy
+	LastSeen *time.Time `json:"lastseen,omitempty"`
+}
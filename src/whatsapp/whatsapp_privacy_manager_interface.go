@@ -0,0 +1,20 @@
+package whatsapp
+
+// WhatsappPrivacyManagerInterface defines the interface for account privacy settings operations
+// This interface should be implemented by the privacy manager in the whatsmeow package
+type WhatsappPrivacyManagerInterface interface {
+	// Get current privacy settings, fetched from WhatsApp servers when not cached
+	GetPrivacySettings() (*WhatsappPrivacySettings, error)
+
+	// Change a single privacy setting and return the updated settings
+	SetPrivacySetting(setting WhatsappPrivacySettingType, value string) (*WhatsappPrivacySettings, error)
+}
+
+// IWhatsappConnectionWithPrivacy extends IWhatsappConnection with privacy management
+// Use this interface when you need both connection and privacy operations
+type IWhatsappConnectionWithPrivacy interface {
+	IWhatsappConnection
+
+	// GetPrivacyManager returns the privacy manager for privacy operations
+	GetPrivacyManager() WhatsappPrivacyManagerInterface
+}
@@ -0,0 +1,116 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WhatsappPrivacySettingType names a single account privacy setting exposed by the API
+type WhatsappPrivacySettingType string
+
+const (
+	PrivacySettingLastSeen     WhatsappPrivacySettingType = "lastseen"     // who can see last seen
+	PrivacySettingOnline       WhatsappPrivacySettingType = "online"       // who can see when we are online
+	PrivacySettingProfile      WhatsappPrivacySettingType = "profile"      // who can see the profile photo
+	PrivacySettingAbout        WhatsappPrivacySettingType = "about"        // who can see the about text
+	PrivacySettingStatus       WhatsappPrivacySettingType = "status"       // who can see published statuses (stories)
+	PrivacySettingReadReceipts WhatsappPrivacySettingType = "readreceipts" // read receipts on or off
+	PrivacySettingGroupAdd     WhatsappPrivacySettingType = "groupadd"     // who can add us to groups
+	PrivacySettingCallAdd      WhatsappPrivacySettingType = "calladd"      // who can call us
+)
+
+// Allowed values for each privacy setting, as accepted by WhatsApp servers
+var WhatsappPrivacySettingValues = map[WhatsappPrivacySettingType][]string{
+	PrivacySettingLastSeen:     {"all", "contacts", "contact_blacklist", "none"},
+	PrivacySettingOnline:       {"all", "match_last_seen"},
+	PrivacySettingProfile:      {"all", "contacts", "contact_blacklist", "none"},
+	PrivacySettingAbout:        {"all", "contacts", "contact_blacklist", "none"},
+	PrivacySettingStatus:       {"contacts", "blacklist", "whitelist"},
+	PrivacySettingReadReceipts: {"all", "none"},
+	PrivacySettingGroupAdd:     {"all", "contacts", "contact_blacklist", "none"},
+	PrivacySettingCallAdd:      {"all", "known"},
+}
+
+// WhatsappPrivacySettingsWritable are the settings changed through the API, in the order they are applied;
+// the status audience can only be changed from the phone
+var WhatsappPrivacySettingsWritable = []WhatsappPrivacySettingType{
+	PrivacySettingLastSeen,
+	PrivacySettingOnline,
+	PrivacySettingProfile,
+	PrivacySettingAbout,
+	PrivacySettingReadReceipts,
+	PrivacySettingGroupAdd,
+	PrivacySettingCallAdd,
+}
+
+// WhatsappPrivacySettings is the current privacy configuration of the session account
+type WhatsappPrivacySettings struct {
+	LastSeen     string `json:"lastseen,omitempty"`
+	Online       string `json:"online,omitempty"`
+	Profile      string `json:"profile,omitempty"`
+	About        string `json:"about,omitempty"`
+	Status       string `json:"status,omitempty"`
+	ReadReceipts string `json:"readreceipts,omitempty"`
+	GroupAdd     string `json:"groupadd,omitempty"`
+	CallAdd      string `json:"calladd,omitempty"`
+}
+
+// ParseWhatsappPrivacySettingType normalizes and validates a privacy setting name
+func ParseWhatsappPrivacySettingType(name string) (WhatsappPrivacySettingType, error) {
+	normalized := WhatsappPrivacySettingType(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := WhatsappPrivacySettingValues[normalized]; !ok {
+		return "", fmt.Errorf("invalid privacy setting: %s", name)
+	}
+	return normalized, nil
+}
+
+// ValidatePrivacySettingValue checks if value is accepted for the given setting
+func ValidatePrivacySettingValue(setting WhatsappPrivacySettingType, value string) error {
+	allowed, ok := WhatsappPrivacySettingValues[setting]
+	if !ok {
+		return fmt.Errorf("invalid privacy setting: %s", setting)
+	}
+
+	for _, item := range allowed {
+		if item == value {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid value '%s' for privacy setting '%s', try {%s}", value, setting, strings.Join(allowed, ","))
+}
+
+// ValidatePrivacySettingChange checks if the setting can be changed to value through the API
+func ValidatePrivacySettingChange(setting WhatsappPrivacySettingType, value string) error {
+	if setting == PrivacySettingStatus {
+		return fmt.Errorf("status audience can only be changed from the phone")
+	}
+	return ValidatePrivacySettingValue(setting, value)
+}
+
+// Get returns the current value of a single setting
+func (source *WhatsappPrivacySettings) Get(setting WhatsappPrivacySettingType) string {
+	if source == nil {
+		return ""
+	}
+
+	switch setting {
+	case PrivacySettingLastSeen:
+		return source.LastSeen
+	case PrivacySettingOnline:
+		return source.Online
+	case PrivacySettingProfile:
+		return source.Profile
+	case PrivacySettingAbout:
+		return source.About
+	case PrivacySettingStatus:
+		return source.Status
+	case PrivacySettingReadReceipts:
+		return source.ReadReceipts
+	case PrivacySettingGroupAdd:
+		return source.GroupAdd
+	case PrivacySettingCallAdd:
+		return source.CallAdd
+	}
+	return ""
+}
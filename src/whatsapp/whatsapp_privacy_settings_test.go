@@ -0,0 +1,55 @@
+package whatsapp
+
+import "testing"
+
+func TestParseWhatsappPrivacySettingTypeNormalizesName(t *testing.T) {
+	setting, err := ParseWhatsappPrivacySettingType("  LastSeen ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if setting != PrivacySettingLastSeen {
+		t.Fatalf("expected %q, got %q", PrivacySettingLastSeen, setting)
+	}
+}
+
+func TestParseWhatsappPrivacySettingTypeRejectsUnknown(t *testing.T) {
+	if _, err := ParseWhatsappPrivacySettingType("whocanread"); err == nil {
+		t.Fatal("expected error for unknown privacy setting")
+	}
+}
+
+func TestValidatePrivacySettingValue(t *testing.T) {
+	if err := ValidatePrivacySettingValue(PrivacySettingOnline, "match_last_seen"); err != nil {
+		t.Fatalf("expected valid value, got %v", err)
+	}
+	if err := ValidatePrivacySettingValue(PrivacySettingReadReceipts, "contacts"); err == nil {
+		t.Fatal("expected readreceipts to reject 'contacts'")
+	}
+}
+
+func TestWhatsappPrivacySettingsGetReturnsFieldBySetting(t *testing.T) {
+	settings := &WhatsappPrivacySettings{About: "contacts", GroupAdd: "none"}
+	if got := settings.Get(PrivacySettingAbout); got != "contacts" {
+		t.Fatalf("expected about=contacts, got %q", got)
+	}
+	if got := settings.Get(PrivacySettingGroupAdd); got != "none" {
+		t.Fatalf("expected groupadd=none, got %q", got)
+	}
+	if got := (*WhatsappPrivacySettings)(nil).Get(PrivacySettingAbout); got != "" {
+		t.Fatalf("expected empty value from nil settings, got %q", got)
+	}
+}
+
+func TestValidatePrivacySettingChangeRejectsStatus(t *testing.T) {
+	if err := ValidatePrivacySettingChange(PrivacySettingStatus, "contacts"); err == nil {
+		t.Fatal("expected status audience change to be rejected")
+	}
+	if err := ValidatePrivacySettingChange(PrivacySettingGroupAdd, "contacts"); err != nil {
+		t.Fatalf("expected valid change, got %v", err)
+	}
+	for _, setting := range WhatsappPrivacySettingsWritable {
+		if setting == PrivacySettingStatus {
+			t.Fatal("status must not be writable")
+		}
+	}
+}
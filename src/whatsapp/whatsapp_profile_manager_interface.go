@@ -0,0 +1,22 @@
+package whatsapp
+
+// WhatsappProfileManagerInterface defines operations over the identity of this session (push name, about text and photo)
+type WhatsappProfileManagerInterface interface {
+	// SetPushName changes the display name shown to contacts that have not saved this number
+	SetPushName(name string) error
+
+	// SetAbout changes the about (status text) of this account
+	SetAbout(about string) error
+
+	// SetProfilePicture replaces the profile photo, expects a square JPEG, returns the new picture id
+	SetProfilePicture(imageData []byte) (string, error)
+
+	// RemoveProfilePicture removes the current profile photo
+	RemoveProfilePicture() error
+}
+
+// IWhatsappConnectionWithProfile extends IWhatsappConnection with profile management capabilities
+type IWhatsappConnectionWithProfile interface {
+	IWhatsappConnection
+	GetProfileManager() WhatsappProfileManagerInterface
+}
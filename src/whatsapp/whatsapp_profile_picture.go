@@ -1,8 +1,9 @@
 package whatsapp
 
 import (
-	"io"
-	"net/http"
+	"context"
+
+	library "github.com/nocodeleaks/quepasa/library"
 )
 
 type WhatsappProfilePicture struct {
@@ -29,12 +30,11 @@ type WhatsappProfilePicture struct {
 }
 
 func (source *WhatsappProfilePicture) Download() (content []byte, err error) {
-	resp, err := http.Get(source.Url)
+	result, err := library.SafeFetch(context.Background(), source.Url, library.FetchOptions{ContentTypes: []string{"image/"}})
 	if err != nil {
 		return
 	}
-	defer resp.Body.Close()
 
-	content, err = io.ReadAll(resp.Body)
+	content = result.Content
 	return
 }
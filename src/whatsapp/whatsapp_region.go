@@ -0,0 +1,5 @@
+package whatsapp
+
+// MetadataKeyRegion is the metadata key used to persist the per-instance default
+// phone region (ISO code, e.g. BR, US), used to read phones written on local format.
+const MetadataKeyRegion = "region"
@@ -0,0 +1,15 @@
+package whatsapp
+
+import "time"
+
+// MetadataKeyStatuses is the metadata key used to persist the per-instance
+// opt-in for dispatching contacts statuses (stories) received on status@broadcast.
+const MetadataKeyStatuses = "statuses"
+
+// WhatsappStatusViewer is a contact that viewed one of our own status posts
+type WhatsappStatusViewer struct {
+	Participant WhatsappChat `json:"participant"`
+
+	// when the status was viewed (or played, for voice/video statuses)
+	Timestamp time.Time `json:"timestamp"`
+}
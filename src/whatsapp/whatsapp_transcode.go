@@ -0,0 +1,6 @@
+package whatsapp
+
+// MetadataKeyTranscodeVideo is the metadata key used to persist the per-instance
+// choice of transcoding outbound videos into a WhatsApp compatible MP4. When
+// absent, the TRANSCODE_VIDEO environment default applies.
+const MetadataKeyTranscodeVideo = "transcodevideo"
@@ -0,0 +1,10 @@
+package whatsapp
+
+// MetadataKeyTranscribe is the metadata key used to persist the per-instance
+// choice of transcribing inbound audios. When absent, the TRANSCRIPTION
+// environment default applies.
+const MetadataKeyTranscribe = "transcribe"
+
+// MetadataKeyTranscribeLanguage is the metadata key used to persist the per-instance
+// language hint of transcriptions. When absent, TRANSCRIPTION_LANGUAGE applies.
+const MetadataKeyTranscribeLanguage = "transcribelanguage"
@@ -0,0 +1,225 @@
+package whatsmeow
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+// Compile-time interface check
+var _ whatsapp.WhatsappBusinessManagerInterface = (*WhatsmeowBusinessManager)(nil)
+
+// default and maximum amount of products requested per catalog page
+const (
+	BusinessCatalogDefaultLimit = 10
+	BusinessCatalogMaxLimit     = 100
+)
+
+// WhatsmeowBusinessManager handles business profile and catalog operations for WhatsmeowConnection
+type WhatsmeowBusinessManager struct {
+	*WhatsmeowConnection // embedded connection for direct access
+}
+
+// NewWhatsmeowBusinessManager creates a new WhatsmeowBusinessManager instance
+func NewWhatsmeowBusinessManager(conn *WhatsmeowConnection) *WhatsmeowBusinessManager {
+	return &WhatsmeowBusinessManager{
+		WhatsmeowConnection: conn,
+	}
+}
+
+// getBusinessJID resolves the target business, empty wid means this session
+func (bm *WhatsmeowBusinessManager) getBusinessJID(wid string) (types.JID, error) {
+	if len(wid) == 0 {
+		if bm.Client.Store == nil || bm.Client.Store.ID == nil {
+			return types.EmptyJID, fmt.Errorf("session not paired")
+		}
+		return bm.Client.Store.ID.ToNonAD(), nil
+	}
+
+	formatted, err := whatsapp.FormatEndpoint(wid)
+	if err != nil {
+		return types.EmptyJID, err
+	}
+
+	return types.ParseJID(formatted)
+}
+
+// GetBusinessProfile returns the business profile of a contact.
+// whatsmeow GetBusinessProfile drops description and websites, so the query is made here.
+func (bm *WhatsmeowBusinessManager) GetBusinessProfile(wid string) (*whatsapp.WhatsappBusinessProfile, error) {
+	if bm.WhatsmeowConnection == nil || bm.Client == nil {
+		return nil, fmt.Errorf("client not defined")
+	}
+
+	jid, err := bm.getBusinessJID(wid)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := bm.Client.DangerousInternals().SendIQ(context.Background(), whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz",
+		Type:      "get",
+		To:        types.ServerJID,
+		Content: []waBinary.Node{{
+			Tag:   "business_profile",
+			Attrs: waBinary.Attrs{"v": "244"},
+			Content: []waBinary.Node{{
+				Tag:   "profile",
+				Attrs: waBinary.Attrs{"jid": jid},
+			}},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business profile: %v", err)
+	}
+
+	node, ok := resp.GetOptionalChildByTag("business_profile", "profile")
+	if !ok {
+		return nil, fmt.Errorf("business profile not found for: %s", jid)
+	}
+
+	return ParseBusinessProfileNode(node), nil
+}
+
+// GetCatalog returns a page of products from a business catalog
+func (bm *WhatsmeowBusinessManager) GetCatalog(wid string, limit int, cursor string) (*whatsapp.WhatsappCatalog, error) {
+	if bm.WhatsmeowConnection == nil || bm.Client == nil {
+		return nil, fmt.Errorf("client not defined")
+	}
+
+	jid, err := bm.getBusinessJID(wid)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = BusinessCatalogDefaultLimit
+	} else if limit > BusinessCatalogMaxLimit {
+		limit = BusinessCatalogMaxLimit
+	}
+
+	content := []waBinary.Node{
+		{Tag: "limit", Content: []byte(strconv.Itoa(limit))},
+		{Tag: "width", Content: []byte("100")},
+		{Tag: "height", Content: []byte("100")},
+	}
+	if len(cursor) > 0 {
+		content = append(content, waBinary.Node{Tag: "after", Content: []byte(cursor)})
+	}
+
+	resp, err := bm.Client.DangerousInternals().SendIQ(context.Background(), whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz:catalog",
+		Type:      "get",
+		To:        types.ServerJID,
+		Content: []waBinary.Node{{
+			Tag:     "product_catalog",
+			Attrs:   waBinary.Attrs{"jid": jid, "allow_shop_source": "true"},
+			Content: content,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %v", err)
+	}
+
+	catalog := ParseCatalogNode(resp.GetChildByTag("product_catalog"))
+	catalog.Wid = jid.String()
+	return catalog, nil
+}
+
+// ParseBusinessProfileNode converts a business "profile" node into the transport shape
+func ParseBusinessProfileNode(node waBinary.Node) *whatsapp.WhatsappBusinessProfile {
+	profile := &whatsapp.WhatsappBusinessProfile{
+		Description: getNodeChildString(node, "description"),
+		Address:     getNodeChildString(node, "address"),
+		Email:       getNodeChildString(node, "email"),
+	}
+
+	if jid, ok := node.AttrGetter().GetJID("jid", false); ok {
+		profile.Wid = jid.String()
+	}
+
+	for _, website := range node.GetChildrenByTag("website") {
+		if value, ok := website.Content.([]byte); ok && len(value) > 0 {
+			profile.Websites = append(profile.Websites, string(value))
+		}
+	}
+
+	categories := node.GetChildByTag("categories")
+	for _, category := range categories.GetChildrenByTag("category") {
+		if value, ok := category.Content.([]byte); ok && len(value) > 0 {
+			profile.Categories = append(profile.Categories, string(value))
+		}
+	}
+
+	hours := node.GetChildByTag("business_hours")
+	profile.TimeZone = hours.AttrGetter().OptionalString("timezone")
+	for _, config := range hours.GetChildrenByTag("business_hours_config") {
+		ag := config.AttrGetter()
+		profile.Hours = append(profile.Hours, whatsapp.WhatsappBusinessHours{
+			Day:   ag.OptionalString("day_of_week"),
+			Mode:  ag.OptionalString("mode"),
+			Open:  ag.OptionalString("open_time"),
+			Close: ag.OptionalString("close_time"),
+		})
+	}
+
+	return profile
+}
+
+// ParseCatalogNode converts a "product_catalog" node into the transport shape
+func ParseCatalogNode(node waBinary.Node) *whatsapp.WhatsappCatalog {
+	catalog := &whatsapp.WhatsappCatalog{
+		Products: []whatsapp.WhatsappProduct{},
+	}
+
+	for _, item := range node.GetChildrenByTag("product") {
+		product := whatsapp.WhatsappProduct{
+			Id:          getNodeChildString(item, "id"),
+			RetailerId:  getNodeChildString(item, "retailer_id"),
+			Name:        getNodeChildString(item, "name"),
+			Description: getNodeChildString(item, "description"),
+			Url:         getNodeChildString(item, "url"),
+			Currency:    getNodeChildString(item, "currency"),
+			Hidden:      item.AttrGetter().OptionalString("is_hidden") == "true",
+		}
+
+		if price, err := strconv.ParseInt(getNodeChildString(item, "price"), 10, 64); err == nil {
+			product.Price = whatsapp.AmountFromThousandths(price)
+		}
+
+		image := item.GetChildByTag("media", "image")
+		product.ImageUrl = getNodeChildString(image, "original_image_url")
+		if len(product.ImageUrl) == 0 {
+			product.ImageUrl = getNodeChildString(image, "request_image_url")
+		}
+
+		catalog.Products = append(catalog.Products, product)
+	}
+
+	if paging, ok := node.GetOptionalChildByTag("paging"); ok {
+		catalog.Cursor = getNodeChildString(paging, "after")
+	}
+
+	return catalog
+}
+
+// getNodeChildString returns the content of a child node as string, empty if missing
+func getNodeChildString(node waBinary.Node, tag string) string {
+	child, ok := node.GetOptionalChildByTag(tag)
+	if !ok {
+		return ""
+	}
+
+	switch content := child.Content.(type) {
+	case []byte:
+		return string(content)
+	case string:
+		return content
+	}
+	return ""
+}
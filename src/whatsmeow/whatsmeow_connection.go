@@ -27,11 +27,14 @@ type WhatsmeowConnection struct {
 	library.LogStruct // logging
 	Client            *whatsmeow.Client
 
-	Handlers        *WhatsmeowHandlers       // composition for handlers
-	GroupManager    *WhatsmeowGroupManager   // composition for group operations
-	StatusManager   *WhatsmeowStatusManager  // composition for status operations
-	ContactManager  *WhatsmeowContactManager // composition for contact operations
-	WakeUpScheduler *WakeUpScheduler         // composition for scheduled presence wake-ups
+	Handlers        *WhatsmeowHandlers        // composition for handlers
+	GroupManager    *WhatsmeowGroupManager    // composition for group operations
+	StatusManager   *WhatsmeowStatusManager   // composition for status operations
+	ContactManager  *WhatsmeowContactManager  // composition for contact operations
+	PrivacyManager  *WhatsmeowPrivacyManager  // composition for account privacy operations
+	ProfileManager  *WhatsmeowProfileManager  // composition for own profile operations
+	BusinessManager *WhatsmeowBusinessManager // composition for business profile and catalog operations
+	WakeUpScheduler *WakeUpScheduler          // composition for scheduled presence wake-ups
 	// call managers intentionally omitted per request (do not include CallManager / SIPCallManager)
 
 	// voipManager holds the full VoIP Manager (WhatsApp → SIP bridge) when
@@ -77,6 +80,22 @@ func (conn *WhatsmeowConnection) GetChatTitle(wid string) string {
 	return GetChatTitleFromWId(conn, wid)
 }
 
+// GetChatTitles returns the titles of many chats at once, by chat id
+func (conn *WhatsmeowConnection) GetChatTitles(wids []string) map[string]string {
+	jids := make([]types.JID, 0, len(wids))
+	for _, wid := range wids {
+		if jid, err := types.ParseJID(wid); err == nil {
+			jids = append(jids, jid)
+		}
+	}
+
+	titles := map[string]string{}
+	for jid, title := range GetChatTitles(conn.Client, jids) {
+		titles[jid.String()] = title
+	}
+	return titles
+}
+
 // Connect to websocket only, dot not authenticate yet, errors come after
 func (source *WhatsmeowConnection) Connect() (err error) {
 	source.GetLogger().Info("starting whatsmeow connection")
@@ -593,6 +612,17 @@ func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp
 		if len(msg.InReply) > 0 {
 			newMessage.LocationMessage.ContextInfo = source.GetContextInfo(*msg)
 		}
+	} else if msg.Product != nil {
+		// Product from catalog, attachment (if any) is the product image
+		newMessage, err = source.GenerateProductMessage(msg)
+		if err != nil {
+			return msg, err
+		}
+	} else if msg.Catalog != nil {
+		newMessage, err = source.GenerateCatalogMessage(msg)
+		if err != nil {
+			return msg, err
+		}
 	} else if !msg.HasAttachment() {
 		// Text messages, buttons, polls
 		// NOTE: WhatsApp blocks ButtonsMessage (deprecated) and InteractiveMessage/NativeFlowMessage
@@ -768,7 +798,7 @@ func (source *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage
 	}
 
 	mediaType := GetMediaTypeFromWAMsgType(msg.Type)
-	response, err := source.Client.Upload(context.Background(), content, mediaType)
+	response, thumbnail, err := source.UploadWithCache(msg.Attachment, mediaType, msg.Type == whatsapp.StickerMessageType)
 	if err != nil {
 		return
 	}
@@ -777,7 +807,7 @@ func (source *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage
 	if len(msg.InReply) > 0 {
 		inreplycontext = source.GetInReplyContextInfo(msg)
 	}
-	result = NewWhatsmeowMessageAttachment(response, msg, mediaType, inreplycontext)
+	result = NewWhatsmeowMessageAttachmentWithThumbnail(response, thumbnail, msg, mediaType, inreplycontext)
 	return
 }
 
@@ -1110,6 +1140,32 @@ func (source *WhatsmeowConnection) HistorySync(timestamp time.Time) (err error)
 	return
 }
 
+// HistorySyncChat requests older messages of a single chat from the phone, before the anchor message
+func (source *WhatsmeowConnection) HistorySyncChat(anchor whatsapp.WhatsappHistoryAnchor, count int) error {
+	if source.Client == nil || source.Client.Store == nil || source.Client.Store.ID == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	jid, err := types.ParseJID(anchor.ChatId)
+	if err != nil {
+		return fmt.Errorf("invalid chatid: %s", err.Error())
+	}
+
+	info := &types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: jid, IsFromMe: anchor.FromMe},
+		ID:            anchor.MessageId,
+		Timestamp:     anchor.Timestamp,
+	}
+
+	logentry := source.GetLogger().WithField(LogFields.ChatId, anchor.ChatId)
+	logentry.Infof("requesting %d history messages before: %s", count, anchor.MessageId)
+
+	msg := source.Client.BuildHistorySyncRequest(info, count)
+	extra := whatsmeow.SendRequestExtra{Peer: true}
+	_, err = source.Client.SendMessage(context.Background(), source.Client.Store.ID.ToNonAD(), msg, extra)
+	return err
+}
+
 func (conn *WhatsmeowConnection) UpdateHandler(handlers whatsapp.IWhatsappHandlers) {
 	if conn.Handlers != nil {
 		conn.Handlers.WAHandlers = handlers
@@ -1399,6 +1455,30 @@ func (conn *WhatsmeowConnection) GetContactManager() whatsapp.WhatsappContactMan
 	return nil
 }
 
+// GetPrivacyManager returns the privacy manager instance with lazy initialization
+func (conn *WhatsmeowConnection) GetPrivacyManager() whatsapp.WhatsappPrivacyManagerInterface {
+	if conn.PrivacyManager == nil {
+		conn.PrivacyManager = NewWhatsmeowPrivacyManager(conn)
+	}
+	return conn.PrivacyManager
+}
+
+// GetProfileManager returns the profile manager instance with lazy initialization
+func (conn *WhatsmeowConnection) GetProfileManager() whatsapp.WhatsappProfileManagerInterface {
+	if conn.ProfileManager == nil {
+		conn.ProfileManager = NewWhatsmeowProfileManager(conn)
+	}
+	return conn.ProfileManager
+}
+
+// GetBusinessManager returns the business manager instance with lazy initialization
+func (conn *WhatsmeowConnection) GetBusinessManager() whatsapp.WhatsappBusinessManagerInterface {
+	if conn.BusinessManager == nil {
+		conn.BusinessManager = NewWhatsmeowBusinessManager(conn)
+	}
+	return conn.BusinessManager
+}
+
 // GetResume returns detailed connection status information
 // This method delegates to the StatusManager for comprehensive status snapshot
 func (conn *WhatsmeowConnection) GetResume() *whatsapp.WhatsappConnectionStatus {
@@ -1420,6 +1500,35 @@ func (conn *WhatsmeowConnection) HasValidHandlers() bool {
 	return h != nil && h.WAHandlers != nil && !h.WAHandlers.IsInterfaceNil()
 }
 
+// SubscribePresence subscribes to the presence of a contact, returning whether a privacy token is
+// available, without it the subscription is ignored by WhatsApp servers
+func (conn *WhatsmeowConnection) SubscribePresence(chatId string) (bool, error) {
+	if conn.Client == nil {
+		return false, fmt.Errorf("client not defined")
+	}
+
+	jid, err := types.ParseJID(chatId)
+	if err != nil {
+		return false, fmt.Errorf("invalid chat id format: %v", err)
+	}
+
+	if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+		return false, fmt.Errorf("presence is only available for contacts: %s", chatId)
+	}
+
+	tokened := conn.hasPrivacyToken(jid)
+	if !tokened {
+		conn.GetLogger().Debugf("subscribing presence without privacy token for %s, probably ignored", jid)
+	}
+
+	err = conn.Client.SubscribePresence(context.Background(), jid)
+	if err != nil {
+		return false, err
+	}
+
+	return tokened, nil
+}
+
 // SendChatPresence updates typing status in a chat
 func (conn *WhatsmeowConnection) SendChatPresence(chatId string, presenceType uint) error {
 	if conn.Client == nil {
@@ -101,6 +101,15 @@ func (cm *WhatsmeowContactManager) GetProfilePicture(wid string, knowingId strin
 		return
 	}
 
+	// cached until expiration or a picture change event for this contact
+	key := GetProfileCacheKey(cm.Client, jid)
+	if cached, found := PictureCache.GetPicture(key); found {
+		if len(knowingId) > 0 && cached.Id == knowingId {
+			return nil, nil
+		}
+		return cached, nil
+	}
+
 	params := &whatsmeow.GetProfilePictureParams{}
 	params.ExistingID = knowingId
 	params.Preview = false
@@ -116,6 +125,7 @@ func (cm *WhatsmeowContactManager) GetProfilePicture(wid string, knowingId strin
 			Type: pictureInfo.Type,
 			Url:  pictureInfo.URL,
 		}
+		PictureCache.Set(key, *picture, "GetProfilePicture")
 	}
 	return
 }
@@ -171,52 +181,36 @@ func (cm *WhatsmeowContactManager) GetLIDFromPhone(phone string) (string, error)
 		cm.maps.SetLIDFromPhoneMap(normalized, lid)
 		logger.Debugf("Phone->LID mapping cached: %s -> %s", normalized, lid)
 
-		// TEMPORARY WORKAROUND: Brazilian mobile phones exist in two variants —
-		// 8-digit (legacy, pre-migration) and 9-digit (modern, with extra leading 9 after DDD).
-		// WhatsApp has not standardized which variant is canonical for each account.
-		// When we successfully resolve a mapping for one variant, we persist the other variant
+		// TEMPORARY WORKAROUND: on some countries the same subscriber may be registered under more
+		// than one phone form (e.g. BR 8/9-digit mobiles, see library.PhoneCountryRule).
+		// When we successfully resolve a mapping for one form, we persist the others
 		// in Store.LIDs so that whatsmeow's SendMessage PN→LID conversion path (triggered
 		// when LIDMigrationTimestamp > 0) can resolve either form without a round-trip to
-		// the WhatsApp server. AllDDDs variants are used here (no DDD > 30 restriction)
+		// the WhatsApp server. Non strict variants are used here (e.g. all BR DDDs)
 		// because persisting an extra mapping that is never looked up is harmless.
 		// Remove this block once WhatsApp enforces a single canonical phone format.
-		if variantPhone, verr := library.AddDigit9BRAllDDDs("+" + normalized); verr == nil {
-			variantNormalized := strings.TrimPrefix(variantPhone, "+")
-			variantJID := types.JID{User: variantNormalized, Server: whatsapp.WHATSAPP_SERVERDOMAIN_USER}
-			if perr := cm.Client.Store.LIDs.PutLIDMapping(context.Background(), lidJID, variantJID); perr == nil {
-				logger.Debugf("BR digit-9 variant persisted in Store.LIDs: %s -> %s", variantNormalized, lid)
-				cm.maps.SetLIDFromPhoneMap(variantNormalized, lid)
-			} else {
-				logger.Warnf("BR digit-9 variant Store.LIDs write failed for %s: %v", variantNormalized, perr)
-			}
-		} else if variantPhone, verr := library.RemoveDigit9BRAllDDDs("+" + normalized); verr == nil {
+		for _, variantPhone := range library.GetPhoneVariants("+"+normalized, false) {
 			variantNormalized := strings.TrimPrefix(variantPhone, "+")
 			variantJID := types.JID{User: variantNormalized, Server: whatsapp.WHATSAPP_SERVERDOMAIN_USER}
 			if perr := cm.Client.Store.LIDs.PutLIDMapping(context.Background(), lidJID, variantJID); perr == nil {
-				logger.Debugf("BR digit-9 variant persisted in Store.LIDs: %s -> %s", variantNormalized, lid)
+				logger.Debugf("phone variant persisted in Store.LIDs: %s -> %s", variantNormalized, lid)
 				cm.maps.SetLIDFromPhoneMap(variantNormalized, lid)
 			} else {
-				logger.Warnf("BR digit-9 variant Store.LIDs write failed for %s: %v", variantNormalized, perr)
+				logger.Warnf("phone variant Store.LIDs write failed for %s: %v", variantNormalized, perr)
 			}
 		}
 
 		return lid, nil
 	}
 
-	// TEMPORARY WORKAROUND: direct lookup failed — try the BR digit-9 variant before giving up.
-	// The phone in the store may be the opposite form (e.g. stored as 9-digit, queried as 8-digit).
-	// All Brazilian DDDs are tried since the store mapping may exist for any DDD.
-	var variantNormalizedFallback string
-	if vp, verr := library.AddDigit9BRAllDDDs("+" + normalized); verr == nil {
-		variantNormalizedFallback = strings.TrimPrefix(vp, "+")
-	} else if vp, verr := library.RemoveDigit9BRAllDDDs("+" + normalized); verr == nil {
-		variantNormalizedFallback = strings.TrimPrefix(vp, "+")
-	}
+	// TEMPORARY WORKAROUND: direct lookup failed — try the other phone forms of the country
+	// before giving up (e.g. BR stored as 9-digit, queried as 8-digit, any DDD).
+	for _, variantPhone := range library.GetPhoneVariants("+"+normalized, false) {
+		variantNormalizedFallback := strings.TrimPrefix(variantPhone, "+")
 
-	if variantNormalizedFallback != "" {
 		// Check in-memory map for variant first
 		if cachedLID, exists := cm.maps.GetLIDFromPhoneMap(variantNormalizedFallback); exists {
-			logger.Debugf("LID found in maps via BR digit-9 variant for phone %s: %s", phone, cachedLID)
+			logger.Debugf("LID found in maps via phone variant for phone %s: %s", phone, cachedLID)
 			cm.maps.SetLIDFromPhoneMap(normalized, cachedLID)
 			return cachedLID, nil
 		}
@@ -224,7 +218,7 @@ func (cm *WhatsmeowContactManager) GetLIDFromPhone(phone string) (string, error)
 		variantJIDFallback := types.JID{User: variantNormalizedFallback, Server: whatsapp.WHATSAPP_SERVERDOMAIN_USER}
 		if lidJID2, err2 := cm.Client.Store.LIDs.GetLIDForPN(context.Background(), variantJIDFallback); err2 == nil && !lidJID2.IsEmpty() {
 			lid := lidJID2.ToNonAD().String()
-			logger.Debugf("LID found in database via BR digit-9 variant %s: %s", variantNormalizedFallback, lid)
+			logger.Debugf("LID found in database via phone variant %s: %s", variantNormalizedFallback, lid)
 
 			// Cache both the original and the variant so future lookups skip DB
 			cm.maps.SetLIDFromPhoneMap(normalized, lid)
@@ -233,7 +227,7 @@ func (cm *WhatsmeowContactManager) GetLIDFromPhone(phone string) (string, error)
 			// Also persist original form in Store.LIDs so whatsmeow's internal path finds it
 			originalJID := types.JID{User: normalized, Server: whatsapp.WHATSAPP_SERVERDOMAIN_USER}
 			if perr := cm.Client.Store.LIDs.PutLIDMapping(context.Background(), lidJID2, originalJID); perr != nil {
-				logger.Warnf("BR digit-9 fallback: Store.LIDs write failed for original %s: %v", normalized, perr)
+				logger.Warnf("phone variant fallback: Store.LIDs write failed for original %s: %v", normalized, perr)
 			}
 
 			return lid, nil
@@ -297,31 +291,21 @@ func (cm *WhatsmeowContactManager) GetPhoneFromLID(lid string) (string, error) {
 	cm.maps.SetPhoneFromLIDMap(lid, phone)
 	logger.Debugf("LID->Phone mapping stored: %s -> %s", lid, phone)
 
-	// TEMPORARY WORKAROUND: Brazilian mobile phones exist in two variants —
-	// 8-digit (legacy, pre-migration) and 9-digit (modern, with extra leading 9 after DDD).
-	// When we resolve a phone from a LID, we persist the alternate variant in Store.LIDs
+	// TEMPORARY WORKAROUND: on some countries the same subscriber may be registered under more
+	// than one phone form (e.g. BR 8/9-digit mobiles, see library.PhoneCountryRule).
+	// When we resolve a phone from a LID, we persist the other forms in Store.LIDs
 	// so that subsequent lookups (and whatsmeow's internal PN→LID path) find either form.
-	// AllDDDs variants are used here — no DDD > 30 restriction since storing an extra
-	// mapping that is never looked up is harmless. For phone-only sends keep using the
-	// restricted (DDD > 30) helpers to avoid wrong digit manipulation.
+	// Non strict variants are used here — storing an extra mapping that is never looked
+	// up is harmless. Phone-only sends use the strict variants to avoid wrong destinations.
 	// Remove this block once WhatsApp enforces a single canonical phone format.
-	if variantPhone, verr := library.AddDigit9BRAllDDDs("+" + phone); verr == nil {
-		variantNormalized := strings.TrimPrefix(variantPhone, "+")
-		variantJID := types.JID{User: variantNormalized, Server: whatsapp.WHATSAPP_SERVERDOMAIN_USER}
-		if perr := cm.Client.Store.LIDs.PutLIDMapping(context.Background(), lidJID, variantJID); perr == nil {
-			logger.Debugf("BR digit-9 variant persisted in Store.LIDs (reverse): %s -> %s", lid, variantNormalized)
-			cm.maps.SetLIDFromPhoneMap(variantNormalized, lid)
-		} else {
-			logger.Warnf("BR digit-9 variant Store.LIDs write failed (reverse) for %s: %v", variantNormalized, perr)
-		}
-	} else if variantPhone, verr := library.RemoveDigit9BRAllDDDs("+" + phone); verr == nil {
+	for _, variantPhone := range library.GetPhoneVariants("+"+phone, false) {
 		variantNormalized := strings.TrimPrefix(variantPhone, "+")
 		variantJID := types.JID{User: variantNormalized, Server: whatsapp.WHATSAPP_SERVERDOMAIN_USER}
 		if perr := cm.Client.Store.LIDs.PutLIDMapping(context.Background(), lidJID, variantJID); perr == nil {
-			logger.Debugf("BR digit-9 variant persisted in Store.LIDs (reverse): %s -> %s", lid, variantNormalized)
+			logger.Debugf("phone variant persisted in Store.LIDs (reverse): %s -> %s", lid, variantNormalized)
 			cm.maps.SetLIDFromPhoneMap(variantNormalized, lid)
 		} else {
-			logger.Warnf("BR digit-9 variant Store.LIDs write failed (reverse) for %s: %v", variantNormalized, perr)
+			logger.Warnf("phone variant Store.LIDs write failed (reverse) for %s: %v", variantNormalized, perr)
 		}
 	}
 
@@ -121,3 +121,26 @@ func (c *WhatsmeowContactMaps) SetIsOnWhatsAppCache(phone, jid string) {
 	defer c.mutex.Unlock()
 	c.isOnWhatsApp[normalizePhone(phone)] = jid
 }
+
+// DeleteContact forgets every mapping of a phone and/or LID (phone with or without + prefix)
+func (c *WhatsmeowContactMaps) DeleteContact(phone, lid string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	normalizedPhone := normalizePhone(phone)
+	if len(normalizedPhone) > 0 {
+		if mapped, exists := c.phoneToLID[normalizedPhone]; exists && len(lid) == 0 {
+			lid = mapped
+		}
+		delete(c.phoneToLID, normalizedPhone)
+		delete(c.isOnWhatsApp, normalizedPhone)
+	}
+
+	if len(lid) > 0 {
+		if mapped, exists := c.lidToPhone[lid]; exists {
+			delete(c.phoneToLID, mapped)
+			delete(c.isOnWhatsApp, mapped)
+		}
+		delete(c.lidToPhone, lid)
+	}
+}
@@ -112,7 +112,8 @@ func (source *WhatsmeowHandlers) buildRouter() *EventRouter {
 
 	r.register(reflect.TypeOf(&events.HistorySync{}), func(raw interface{}) {
 		evt := raw.(*events.HistorySync)
-		if source.HandleHistorySync() {
+		// on demand responses were explicitly requested, always handled
+		if source.HandleHistorySync() || IsOnDemandHistorySync(*evt) {
 			go source.OnHistorySyncEvent(*evt)
 		}
 	})
@@ -127,6 +128,41 @@ func (source *WhatsmeowHandlers) buildRouter() *EventRouter {
 		source.JoinedGroup(*evt)
 	})
 
+	r.register(reflect.TypeOf(&events.GroupInfo{}), func(raw interface{}) {
+		evt := raw.(*events.GroupInfo)
+		OnEventGroupInfo(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.Picture{}), func(raw interface{}) {
+		evt := raw.(*events.Picture)
+		go OnEventPicture(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.PushName{}), func(raw interface{}) {
+		evt := raw.(*events.PushName)
+		go OnEventPushName(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.UserAbout{}), func(raw interface{}) {
+		evt := raw.(*events.UserAbout)
+		go OnEventUserAbout(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.IdentityChange{}), func(raw interface{}) {
+		evt := raw.(*events.IdentityChange)
+		go OnEventIdentityChange(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.Presence{}), func(raw interface{}) {
+		evt := raw.(*events.Presence)
+		OnEventPresence(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.ChatPresence{}), func(raw interface{}) {
+		evt := raw.(*events.ChatPresence)
+		OnEventChatPresence(source, *evt)
+	})
+
 	r.register(reflect.TypeOf(&events.Contact{}), func(raw interface{}) {
 		evt := raw.(*events.Contact)
 		go OnEventContact(source, *evt)
@@ -137,6 +173,36 @@ func (source *WhatsmeowHandlers) buildRouter() *EventRouter {
 		go OnEventBlocklist(source, *evt)
 	})
 
+	r.register(reflect.TypeOf(&events.PrivacySettings{}), func(raw interface{}) {
+		evt := raw.(*events.PrivacySettings)
+		go OnEventPrivacySettings(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.Archive{}), func(raw interface{}) {
+		evt := raw.(*events.Archive)
+		OnEventArchive(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.Pin{}), func(raw interface{}) {
+		evt := raw.(*events.Pin)
+		OnEventPin(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.Mute{}), func(raw interface{}) {
+		evt := raw.(*events.Mute)
+		OnEventMute(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.MarkChatAsRead{}), func(raw interface{}) {
+		evt := raw.(*events.MarkChatAsRead)
+		OnEventMarkChatAsRead(source, *evt)
+	})
+
+	r.register(reflect.TypeOf(&events.DeleteChat{}), func(raw interface{}) {
+		evt := raw.(*events.DeleteChat)
+		OnEventDeleteChat(source, *evt)
+	})
+
 	r.register(reflect.TypeOf(&events.PairError{}), func(raw interface{}) {
 		evt := raw.(*events.PairError)
 		source.GetLogger().Errorf("pair error event: %v", evt)
@@ -166,14 +232,7 @@ func (source *WhatsmeowHandlers) buildRouter() *EventRouter {
 	}
 	r.register(reflect.TypeOf(&events.AppState{}), unimplementedHandler)
 	r.register(reflect.TypeOf(&events.CallTerminate{}), unimplementedHandler)
-	r.register(reflect.TypeOf(&events.DeleteChat{}), unimplementedHandler)
 	r.register(reflect.TypeOf(&events.DeleteForMe{}), unimplementedHandler)
-	r.register(reflect.TypeOf(&events.MarkChatAsRead{}), unimplementedHandler)
-	r.register(reflect.TypeOf(&events.Mute{}), unimplementedHandler)
-	r.register(reflect.TypeOf(&events.Pin{}), unimplementedHandler)
-	r.register(reflect.TypeOf(&events.PushName{}), unimplementedHandler)
-	r.register(reflect.TypeOf(&events.GroupInfo{}), unimplementedHandler)
-	r.register(reflect.TypeOf(&events.UserAbout{}), unimplementedHandler)
 	r.register(reflect.TypeOf(&events.UserStatusMute{}), unimplementedHandler)
 
 	r.register(reflect.TypeOf(&events.QR{}), func(raw interface{}) {
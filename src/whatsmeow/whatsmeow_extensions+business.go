@@ -0,0 +1,128 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"strings"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"go.mau.fi/util/random"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// GenerateProductMessage builds a product message from this session catalog.
+// An image attachment, when present, is uploaded and used as product image.
+func (source *WhatsmeowConnection) GenerateProductMessage(msg *whatsapp.WhatsappMessage) (*waE2E.Message, error) {
+	product := msg.Product
+	if product == nil {
+		return nil, fmt.Errorf("product data is nil")
+	}
+
+	if len(product.Id) == 0 {
+		return nil, fmt.Errorf("product id is required")
+	}
+
+	owner := product.Owner
+	if len(owner) == 0 {
+		if source.Client.Store == nil || source.Client.Store.ID == nil {
+			return nil, fmt.Errorf("session not paired")
+		}
+		owner = source.Client.Store.ID.ToNonAD().String()
+	}
+
+	snapshot := &waE2E.ProductMessage_ProductSnapshot{
+		ProductID: proto.String(product.Id),
+	}
+	if len(product.Name) > 0 {
+		snapshot.Title = proto.String(product.Name)
+	}
+	if len(product.Description) > 0 {
+		snapshot.Description = proto.String(product.Description)
+	}
+	if len(product.RetailerId) > 0 {
+		snapshot.RetailerID = proto.String(product.RetailerId)
+	}
+	if len(product.Url) > 0 {
+		snapshot.URL = proto.String(product.Url)
+	}
+	if len(product.Currency) > 0 {
+		snapshot.CurrencyCode = proto.String(strings.ToUpper(product.Currency))
+		snapshot.PriceAmount1000 = proto.Int64(whatsapp.AmountToThousandths(product.Price))
+	}
+
+	if msg.HasAttachment() {
+		imageMsg := *msg
+		imageMsg.Type = whatsapp.ImageMessageType
+		imageMsg.InReply = ""
+
+		uploaded, err := source.UploadAttachment(imageMsg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload product image: %v", err)
+		}
+
+		if uploaded.ImageMessage == nil {
+			return nil, fmt.Errorf("product image must be an image")
+		}
+
+		uploaded.ImageMessage.Caption = nil
+		snapshot.ProductImage = uploaded.ImageMessage
+		snapshot.ProductImageCount = proto.Uint32(1)
+	}
+
+	internal := &waE2E.ProductMessage{
+		Product:          snapshot,
+		BusinessOwnerJID: proto.String(owner),
+	}
+	if text := msg.GetText(); len(text) > 0 {
+		internal.Body = proto.String(text)
+	}
+	if len(msg.InReply) > 0 {
+		internal.ContextInfo = source.GetContextInfo(*msg)
+	}
+
+	return &waE2E.Message{
+		ProductMessage: internal,
+		MessageContextInfo: &waE2E.MessageContextInfo{
+			MessageSecret: random.Bytes(32),
+		},
+	}, nil
+}
+
+// GenerateCatalogMessage builds a text message with the link to this session catalog,
+// the same way the phone shares it, title and description feed the link preview
+func (source *WhatsmeowConnection) GenerateCatalogMessage(msg *whatsapp.WhatsappMessage) (*waE2E.Message, error) {
+	if msg.Catalog == nil {
+		return nil, fmt.Errorf("catalog data is nil")
+	}
+
+	if source.Client.Store == nil || source.Client.Store.ID == nil {
+		return nil, fmt.Errorf("session not paired")
+	}
+
+	link := GetCatalogLink(source.Client.Store.ID.User)
+	text := msg.GetText()
+	if len(text) > 0 {
+		text = text + "\n" + link
+	} else {
+		text = link
+	}
+
+	internal := &waE2E.ExtendedTextMessage{
+		Text:        proto.String(text),
+		MatchedText: proto.String(link),
+		ContextInfo: source.GetContextInfo(*msg),
+	}
+	if len(msg.Catalog.Title) > 0 {
+		internal.Title = proto.String(msg.Catalog.Title)
+	}
+	if len(msg.Catalog.Description) > 0 {
+		internal.Description = proto.String(msg.Catalog.Description)
+	}
+
+	return &waE2E.Message{ExtendedTextMessage: internal}, nil
+}
+
+// GetCatalogLink returns the public catalog link of a business phone number
+func GetCatalogLink(phone string) string {
+	return "https://wa.me/c/" + strings.TrimPrefix(phone, "+")
+}
@@ -94,14 +94,14 @@ func GetChatTitle(client *whatsmeow.Client, jid types.JID) (title string) {
 	}
 
 	if jid.Server == whatsapp.WHATSAPP_SERVERDOMAIN_GROUP {
-		title = GroupInfoCache.Get(jid.String())
-		if len(title) > 0 {
-			goto found
-		}
-		gInfo, _ := client.GetGroupInfo(context.Background(), jid)
-		if gInfo != nil {
+		// session info first, so its group changes always have the previous values
+		if gInfo := GroupInfoCache.GetOrFetchInfo(client, jid, "GetChatTitle"); gInfo != nil {
 			title = gInfo.Name
-			_ = GroupInfoCache.Append(jid.String(), title, "GetChatTitle")
+		}
+		if len(title) == 0 {
+			title = GroupInfoCache.Get(jid.String())
+		}
+		if len(title) > 0 {
 			goto found
 		}
 	} else {
@@ -115,6 +115,64 @@ found:
 	return library.NormalizeForTitle(title)
 }
 
+// GetChatTitles returns the titles of many chats with a single contacts query and, for groups
+// not cached yet for this session, a single joined groups query; chats without title are not included
+func GetChatTitles(client *whatsmeow.Client, jids []types.JID) map[types.JID]string {
+	titles := map[types.JID]string{}
+	if client == nil {
+		return titles
+	}
+
+	var contacts map[types.JID]types.ContactInfo
+	missingGroups := false
+	for _, jid := range jids {
+		jid = CleanJID(jid)
+		if jid.Server == whatsapp.WHATSAPP_SERVERDOMAIN_GROUP {
+			if title := GroupInfoCache.Get(jid.String()); len(title) > 0 {
+				titles[jid] = library.NormalizeForTitle(title)
+			}
+
+			// titles cached by other sessions do not hold the info of this one
+			if GroupInfoCache.GetInfo(client, jid) == nil {
+				missingGroups = true
+			}
+			continue
+		}
+
+		if contacts == nil && client.Store != nil && client.Store.Contacts != nil {
+			all, err := client.Store.Contacts.GetAllContacts(context.Background())
+			if err != nil {
+				all = map[types.JID]types.ContactInfo{}
+			}
+			contacts = all
+		}
+
+		if info, ok := contacts[jid]; ok {
+			if title := ExtractContactName(info); len(title) > 0 {
+				titles[jid] = library.NormalizeForTitle(title)
+			}
+		}
+	}
+
+	if missingGroups {
+		groups, _ := client.GetJoinedGroups(context.Background())
+		for _, info := range groups {
+			GroupInfoCache.AppendInfo(client, info, "GetChatTitles")
+		}
+
+		for _, jid := range jids {
+			jid = CleanJID(jid)
+			if _, ok := titles[jid]; !ok && jid.Server == whatsapp.WHATSAPP_SERVERDOMAIN_GROUP {
+				if title := GroupInfoCache.Get(jid.String()); len(title) > 0 {
+					titles[jid] = library.NormalizeForTitle(title)
+				}
+			}
+		}
+	}
+
+	return titles
+}
+
 func NewWhatsappChat(handler *WhatsmeowHandlers, jid types.JID) *whatsapp.WhatsappChat {
 	contactManager := handler.GetContactManager()
 	return NewWhatsappChatRaw(handler.Client, contactManager, jid)
@@ -24,21 +24,20 @@ func (source *WhatsmeowConnection) PublishStatus(text string, attachment *whatsa
 
 	if attachment != nil && attachment.GetContent() != nil && len(*attachment.GetContent()) > 0 {
 		// Media status: upload content and build the appropriate message type
-		content := *attachment.GetContent()
 		mediaType := GetMediaTypeFromAttachment(attachment)
 
-		response, err := source.Client.Upload(context.Background(), content, mediaType)
-		if err != nil {
-			return "", fmt.Errorf("failed to upload status media: %w", err)
-		}
-
 		waMsg := whatsapp.WhatsappMessage{
 			Text:       text,
 			Attachment: attachment,
 		}
 		waMsg.Type = whatsapp.GetMessageType(attachment)
 
-		newMessage = NewWhatsmeowMessageAttachment(response, waMsg, mediaType, nil)
+		response, thumbnail, err := source.UploadWithCache(attachment, mediaType, waMsg.Type == whatsapp.StickerMessageType)
+		if err != nil {
+			return "", fmt.Errorf("failed to upload status media: %w", err)
+		}
+
+		newMessage = NewWhatsmeowMessageAttachmentWithThumbnail(response, thumbnail, waMsg, mediaType, nil)
 	} else {
 		// Text-only status
 		if text == "" {
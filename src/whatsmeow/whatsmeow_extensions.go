@@ -84,6 +84,56 @@ func ToWhatsmeowMessage(source whatsapp.IWhatsappMessage) (msg *waE2E.Message, e
  * @return waE2E.Message pointer with the correct media type
  */
 func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, waMsg whatsapp.WhatsappMessage, mediaType whatsmeow.MediaType, inreplycontext *waE2E.ContextInfo) (msg *waE2E.Message) {
+	var thumbnail []byte
+	if waMsg.Type != whatsapp.StickerMessageType {
+		thumbnail = GetWhatsmeowAttachmentThumbnail(waMsg.Attachment)
+	}
+	return NewWhatsmeowMessageAttachmentWithThumbnail(response, thumbnail, waMsg, mediaType, inreplycontext)
+}
+
+// GetWhatsmeowAttachmentThumbnail generates the JPEG thumbnail when possible for image/video/pdf content,
+// and office documents when previews are enabled; documents also get their page count
+func GetWhatsmeowAttachmentThumbnail(attach *whatsapp.WhatsappAttachment) (thumbnail []byte) {
+	if attach == nil || attach.GetContent() == nil || len(*attach.GetContent()) == 0 {
+		return
+	}
+
+	content := *attach.GetContent()
+	mimeType := attach.Mimetype
+
+	if mimeType == "application/pdf" || media.IsOfficeDocument(mimeType, attach.FileName) {
+		if !media.CanGeneratePreview(mimeType, attach.FileName) {
+			return
+		}
+
+		preview, err := media.GeneratePreview(content, mimeType, attach.FileName, media.DefaultThumbnailConfig())
+		if err != nil {
+			log.Debugf("Failed to generate preview for %s: %v", mimeType, err)
+			return
+		}
+
+		if attach.PageCount == 0 {
+			attach.PageCount = preview.PageCount
+		}
+		log.Debugf("Generated preview for %s: %d bytes, %d pages", mimeType, len(preview.Thumbnail), preview.PageCount)
+		return preview.Thumbnail
+	}
+
+	if media.CanGenerateThumbnail(mimeType) {
+		thumbData, err := media.GenerateThumbnailByMime(content, mimeType)
+		if err != nil {
+			log.Debugf("Failed to generate thumbnail for %s: %v", mimeType, err)
+		} else {
+			thumbnail = thumbData
+			log.Debugf("Generated thumbnail for %s: %d bytes", mimeType, len(thumbData))
+		}
+	}
+	return
+}
+
+// NewWhatsmeowMessageAttachmentWithThumbnail builds the media message with an already generated thumbnail,
+// as when reusing a previous upload of the same content
+func NewWhatsmeowMessageAttachmentWithThumbnail(response whatsmeow.UploadResponse, thumbnail []byte, waMsg whatsapp.WhatsappMessage, mediaType whatsmeow.MediaType, inreplycontext *waE2E.ContextInfo) (msg *waE2E.Message) {
 	attach := waMsg.Attachment
 
 	var seconds *uint32
@@ -99,7 +149,7 @@ func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, waMsg what
 	// Stickers are sent as StickerMessage — build and return immediately,
 	// skipping thumbnail generation and the generic media switch below.
 	if waMsg.Type == whatsapp.StickerMessageType {
-		isAnimated := attach.Mimetype == "video/webp"
+		isAnimated := attach.Animated || attach.Mimetype == "video/webp"
 		internal := &waE2E.StickerMessage{
 			URL:           proto.String(response.URL),
 			DirectPath:    proto.String(response.DirectPath),
@@ -115,23 +165,6 @@ func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, waMsg what
 		return
 	}
 
-	// Generate thumbnail when possible for image/video/pdf content.
-	var thumbnail []byte
-	if attach.GetContent() != nil && len(*attach.GetContent()) > 0 {
-		content := *attach.GetContent()
-		mimeType := attach.Mimetype
-
-		if media.CanGenerateThumbnail(mimeType) {
-			thumbData, err := media.GenerateThumbnailByMime(content, mimeType)
-			if err != nil {
-				log.Debugf("Failed to generate thumbnail for %s: %v", mimeType, err)
-			} else {
-				thumbnail = thumbData
-				log.Debugf("Generated thumbnail for %s: %d bytes", mimeType, len(thumbData))
-			}
-		}
-	}
-
 	switch mediaType {
 	case whatsmeow.MediaImage:
 		internal := &waE2E.ImageMessage{
@@ -208,6 +241,9 @@ func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, waMsg what
 		if len(thumbnail) > 0 {
 			internal.JPEGThumbnail = thumbnail
 		}
+		if attach.PageCount > 0 {
+			internal.PageCount = proto.Uint32(attach.PageCount)
+		}
 		msg = &waE2E.Message{DocumentMessage: internal}
 		return
 	}
@@ -269,8 +305,10 @@ func GetWhatsappMessageStatus(receipt types.ReceiptType) whatsapp.WhatsappMessag
 		return whatsapp.WhatsappMessageStatusDelivered
 	case types.ReceiptTypeRetry, types.ReceiptTypeServerError:
 		return whatsapp.WhatsappMessageStatusError
-	case types.ReceiptTypeRead, types.ReceiptTypePlayed:
+	case types.ReceiptTypeRead:
 		return whatsapp.WhatsappMessageStatusRead
+	case types.ReceiptTypePlayed:
+		return whatsapp.WhatsappMessageStatusPlayed
 	}
 	return whatsapp.WhatsappMessageStatusUnknown
 }
@@ -1,9 +1,13 @@
 package whatsmeow
 
 import (
+	"context"
 	"time"
 
 	library "github.com/nocodeleaks/quepasa/library"
+	whatsmeow "go.mau.fi/whatsmeow"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
 )
 
 const DEFAULTEXPIRATION_WGIC time.Duration = time.Duration(1 * time.Hour)
@@ -16,22 +20,201 @@ func GetCacheExpiration() time.Time {
 	return time.Now().Add(DEFAULTEXPIRATION_WGIC)
 }
 
+// Append caches the group title, shared by all sessions
 func (source *WhatsmeowGroupInfoCache) Append(id string, title string, from string) bool {
-	expiration := GetCacheExpiration()
+	return source.setItem(id, WhatsmeowGroupInfoCacheItem{Id: id, Title: title}, from)
+}
+
+// AppendInfo caches the full group info as seen by the session of client, keeping old values
+// available for group changes; sessions in the same group follow its changes independently
+func (source *WhatsmeowGroupInfoCache) AppendInfo(client *whatsmeow.Client, info *types.GroupInfo, from string) bool {
+	if info == nil {
+		return false
+	}
+
+	id := info.JID.String()
+	source.Append(id, info.Name, from)
+	return source.setItem(GetProfileCacheKey(client, info.JID), WhatsmeowGroupInfoCacheItem{Id: id, Title: info.Name, Info: info}, from)
+}
+
+// GroupInfoFetcher gets the current group info from whatsapp servers, replaced on tests
+var GroupInfoFetcher = func(client *whatsmeow.Client, jid types.JID) (*types.GroupInfo, error) {
+	return client.GetGroupInfo(context.Background(), jid)
+}
+
+// GetOrFetchInfo returns the group info cached for the session of client, fetching and caching it
+// when unknown, even if another session already cached the group title; nil when not available
+func (source *WhatsmeowGroupInfoCache) GetOrFetchInfo(client *whatsmeow.Client, jid types.JID, from string) *types.GroupInfo {
+	if info := source.GetInfo(client, jid); info != nil {
+		return info
+	}
+
+	if client == nil {
+		return nil
+	}
+
+	info, err := GroupInfoFetcher(client, jid)
+	if err != nil || info == nil {
+		return nil
+	}
+
+	source.AppendInfo(client, info, from)
+	return info
+}
+
+func (source *WhatsmeowGroupInfoCache) setItem(key string, value WhatsmeowGroupInfoCacheItem, from string) bool {
 	item := library.CacheItem{
-		Key:        id,
-		Value:      title,
-		Expiration: expiration,
+		Key:        key,
+		Value:      value,
+		Expiration: GetCacheExpiration(),
 	}
 	return source.SetCacheItem(item, "groupinfo-"+from)
 }
 
+func (source *WhatsmeowGroupInfoCache) getItem(key string) (item WhatsmeowGroupInfoCacheItem, success bool) {
+	cached, success := source.GetAny(key)
+	if success {
+		item, success = cached.(WhatsmeowGroupInfoCacheItem)
+	}
+	return
+}
+
 func (source *WhatsmeowGroupInfoCache) Get(id string) (title string) {
-	cached, success := source.GetAny(id)
+	item, success := source.getItem(id)
 	if success {
-		title, _ = cached.(string)
+		title = item.Title
+	}
+	return
+}
+
+// GetInfo returns the full group info cached for the session of client, nil when unknown
+func (source *WhatsmeowGroupInfoCache) GetInfo(client *whatsmeow.Client, jid types.JID) *types.GroupInfo {
+	item, success := source.getItem(GetProfileCacheKey(client, jid))
+	if !success {
+		return nil
+	}
+	return item.Info
+}
+
+// Update applies a group info event over the group cached for the session of client, returning
+// the previous info (nil when unknown). Deleted groups are removed from cache.
+func (source *WhatsmeowGroupInfoCache) Update(client *whatsmeow.Client, evt events.GroupInfo) (previous *types.GroupInfo) {
+	id := evt.JID.String()
+	if evt.Delete != nil {
+		source.DeleteByKey(id)
+	} else if evt.Name != nil {
+		source.Append(id, evt.Name.Name, "event")
+	}
+
+	key := GetProfileCacheKey(client, evt.JID)
+	item, success := source.getItem(key)
+	if !success {
+		return nil
+	}
+
+	if evt.Delete != nil {
+		source.DeleteByKey(key)
+		return item.Info
+	}
+
+	if evt.Name != nil {
+		item.Title = evt.Name.Name
+	}
+
+	if item.Info != nil {
+		previous = item.Info
+		current := ApplyGroupInfoEvent(*item.Info, evt)
+		item.Info = &current
 	}
+
+	source.setItem(key, item, "event")
 	return
 }
 
+// ApplyGroupInfoEvent returns the group info with the changes of the event applied
+func ApplyGroupInfoEvent(info types.GroupInfo, evt events.GroupInfo) types.GroupInfo {
+	if evt.Name != nil {
+		info.GroupName = *evt.Name
+	}
+	if evt.Topic != nil {
+		info.GroupTopic = *evt.Topic
+	}
+	if evt.Locked != nil {
+		info.GroupLocked = *evt.Locked
+	}
+	if evt.Announce != nil {
+		info.GroupAnnounce = *evt.Announce
+	}
+	if evt.Ephemeral != nil {
+		info.GroupEphemeral = *evt.Ephemeral
+	}
+	if evt.MembershipApprovalMode != nil {
+		info.GroupMembershipApprovalMode = *evt.MembershipApprovalMode
+	}
+	if evt.Suspended {
+		info.Suspended = true
+	} else if evt.Unsuspended {
+		info.Suspended = false
+	}
+
+	// never change the participants of the cached (previous) value
+	participants := make([]types.GroupParticipant, 0, len(info.Participants)+len(evt.Join))
+	for _, participant := range info.Participants {
+		if containsGroupParticipant(evt.Leave, participant) {
+			continue
+		}
+
+		if containsGroupParticipant(evt.Promote, participant) {
+			participant.IsAdmin = true
+		} else if containsGroupParticipant(evt.Demote, participant) {
+			participant.IsAdmin = false
+			participant.IsSuperAdmin = false
+		}
+
+		participants = append(participants, participant)
+	}
+
+	for _, jid := range evt.Join {
+		if !hasGroupParticipant(participants, jid) {
+			participants = append(participants, types.GroupParticipant{JID: jid})
+		}
+	}
+
+	if len(evt.Join) > 0 || len(evt.Leave) > 0 {
+		info.ParticipantCount = len(participants)
+	}
+
+	info.Participants = participants
+	if len(evt.ParticipantVersionID) > 0 {
+		info.ParticipantVersionID = evt.ParticipantVersionID
+	}
+	return info
+}
+
+// containsGroupParticipant checks the participant by any of its known addresses
+func containsGroupParticipant(jids []types.JID, participant types.GroupParticipant) bool {
+	for _, jid := range jids {
+		jid = jid.ToNonAD()
+		if jid == participant.JID.ToNonAD() {
+			return true
+		}
+		if !participant.PhoneNumber.IsEmpty() && jid == participant.PhoneNumber.ToNonAD() {
+			return true
+		}
+		if !participant.LID.IsEmpty() && jid == participant.LID.ToNonAD() {
+			return true
+		}
+	}
+	return false
+}
+
+func hasGroupParticipant(participants []types.GroupParticipant, jid types.JID) bool {
+	for _, participant := range participants {
+		if containsGroupParticipant([]types.JID{jid}, participant) {
+			return true
+		}
+	}
+	return false
+}
+
 var GroupInfoCache WhatsmeowGroupInfoCache = WhatsmeowGroupInfoCache{}
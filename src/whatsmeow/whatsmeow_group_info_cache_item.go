@@ -1,6 +1,11 @@
 package whatsmeow
 
+import types "go.mau.fi/whatsmeow/types"
+
 type WhatsmeowGroupInfoCacheItem struct {
 	Id    string
 	Title string
+
+	// full group info when known, used to track old values of group changes
+	Info *types.GroupInfo
 }
@@ -0,0 +1,89 @@
+package whatsmeow
+
+import (
+	"testing"
+
+	whatsmeow "go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func testGroupCacheClient(user string) *whatsmeow.Client {
+	return &whatsmeow.Client{Store: &store.Device{ID: &types.JID{User: user, Server: types.DefaultUserServer}}}
+}
+
+// TestGroupInfoCacheUpdatePerSession verifies each session keeps its own previous group info
+func TestGroupInfoCacheUpdatePerSession(t *testing.T) {
+	cache := WhatsmeowGroupInfoCache{}
+	group := types.NewJID("123456", types.GroupServer)
+	first, second := testGroupCacheClient("5511111111111"), testGroupCacheClient("5522222222222")
+
+	for _, client := range []*whatsmeow.Client{first, second} {
+		cache.AppendInfo(client, &types.GroupInfo{JID: group, GroupName: types.GroupName{Name: "old"}}, "test")
+	}
+
+	evt := events.GroupInfo{JID: group, Name: &types.GroupName{Name: "new"}}
+	for _, client := range []*whatsmeow.Client{first, second} {
+		previous := cache.Update(client, evt)
+		if previous == nil || previous.Name != "old" {
+			t.Fatalf("unexpected previous info for %s: %+v", client.Store.ID.User, previous)
+		}
+	}
+
+	if title := cache.Get(group.String()); title != "new" {
+		t.Errorf("unexpected shared title: %s", title)
+	}
+
+	if info := cache.GetInfo(first, group); info == nil || info.Name != "new" {
+		t.Errorf("unexpected current info: %+v", info)
+	}
+}
+
+// TestGroupInfoCacheTwoSessions verifies a session gets its own info even when another one already
+// cached the group title, and that group events of an unknown group seed the session info
+func TestGroupInfoCacheTwoSessions(t *testing.T) {
+	group := types.NewJID("654321", types.GroupServer)
+	first, second := testGroupCacheClient("5533333333333"), testGroupCacheClient("5544444444444")
+	defer GroupInfoCache.DeleteByKey(group.String())
+
+	name := "old"
+	fetches := 0
+	previousFetcher := GroupInfoFetcher
+	defer func() { GroupInfoFetcher = previousFetcher }()
+	GroupInfoFetcher = func(client *whatsmeow.Client, jid types.JID) (*types.GroupInfo, error) {
+		fetches++
+		return &types.GroupInfo{JID: jid, GroupName: types.GroupName{Name: name}}, nil
+	}
+
+	for _, client := range []*whatsmeow.Client{first, second} {
+		if title := GetChatTitle(client, group); title != "old" {
+			t.Fatalf("unexpected title for %s: %s", client.Store.ID.User, title)
+		}
+		if GroupInfoCache.GetInfo(client, group) == nil {
+			t.Fatalf("expected session info for %s", client.Store.ID.User)
+		}
+	}
+
+	if fetches != 2 {
+		t.Errorf("expected one fetch per session, got: %d", fetches)
+	}
+
+	name = "new"
+	evt := events.GroupInfo{JID: group, Name: &types.GroupName{Name: "new"}}
+	for _, client := range []*whatsmeow.Client{first, second} {
+		if previous := GroupInfoCache.Update(client, evt); previous == nil || previous.Name != "old" {
+			t.Fatalf("unexpected previous info for %s: %+v", client.Store.ID.User, previous)
+		}
+	}
+
+	// a third session, unknown to the cache, is seeded by the event
+	third := testGroupCacheClient("5555555555555")
+	handlers := minimalHandlers(t)
+	handlers.Client = third
+	OnEventGroupInfo(handlers, evt)
+
+	if info := GroupInfoCache.GetInfo(third, group); info == nil || info.Name != "new" {
+		t.Errorf("expected session info seeded by the event: %+v", info)
+	}
+}
@@ -514,13 +514,28 @@ func (source *WhatsmeowHandlers) OnHistorySyncEvent(evt events.HistorySync) {
 	logentry.Infof("history sync: %s", evt.Data.SyncType)
 	// HistorySyncSaveJSON(evt)
 
+	conversations := evt.Data.GetConversations()
+	if IsOnDemandHistorySync(evt) {
+		source.OnDemandHistorySync(conversations)
+		return
+	}
+
+	// chat list state is kept even when messages history is not handled
+	for _, conversation := range conversations {
+		wid, err := types.ParseJID(conversation.GetID())
+		if err != nil {
+			continue
+		}
+
+		source.followChatState(wid, GetHistorySyncChatState(conversation))
+	}
+
 	// whatsmeow service options
 	options := source.GetServiceOptions()
 	if options.HistorySync == nil {
 		return
 	}
 
-	conversations := evt.Data.GetConversations()
 	for _, conversation := range conversations {
 		for _, historyMsg := range conversation.GetMessages() {
 			wid, err := types.ParseJID(conversation.GetID())
@@ -838,6 +853,18 @@ func (source *WhatsmeowHandlers) Receipt(evt events.Receipt) {
 
 	chatID := fmt.Sprint(evt.Chat.User, "@", evt.Chat.Server)
 
+	// Views of our own status posts are tracked apart from regular receipts
+	if IsStatusViewReceipt(evt) {
+		source.StatusReceipt(evt)
+		return
+	}
+
+	// Group receipts are tracked per participant on the cached message
+	if IsGroupReceipt(evt) {
+		source.GroupReceipt(evt)
+		return
+	}
+
 	// Ignore chats with @broadcast and @newsletter
 	if strings.Contains(chatID, "@broadcast") || strings.Contains(chatID, "@newsletter") || strings.Contains(chatID, "@g.us") {
 		return
@@ -888,7 +915,7 @@ func (source *WhatsmeowHandlers) Receipt(evt events.Receipt) {
 			continue
 		}
 
-		if status.Uint32() != whatsapp.WhatsappMessageStatusRead.Uint32() {
+		if status.Uint32() < whatsapp.WhatsappMessageStatusRead.Uint32() {
 			continue
 		}
 
@@ -900,6 +927,10 @@ func (source *WhatsmeowHandlers) Receipt(evt events.Receipt) {
 
 		message := &whatsapp.WhatsappMessage{Content: evt}
 		message.Id = "readreceipt"
+		if status == whatsapp.WhatsappMessageStatusPlayed {
+			// voice notes and videos
+			message.Id = "playedreceipt"
+		}
 
 		// basic information
 		message.Timestamp = evt.Timestamp
@@ -0,0 +1,102 @@
+package whatsmeow
+
+import (
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	waHistorySync "go.mau.fi/whatsmeow/proto/waHistorySync"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// OnEventArchive updates the chat list when a chat is archived or unarchived from another device
+func OnEventArchive(source *WhatsmeowHandlers, evt events.Archive) {
+	archived := evt.Action.GetArchived()
+	source.followChatState(evt.JID, &whatsapp.WhatsappChatState{Archived: &archived, Timestamp: evt.Timestamp})
+}
+
+// OnEventPin updates the chat list when a chat is pinned or unpinned from another device
+func OnEventPin(source *WhatsmeowHandlers, evt events.Pin) {
+	pinned := evt.Action.GetPinned()
+	source.followChatState(evt.JID, &whatsapp.WhatsappChatState{Pinned: &pinned, Timestamp: evt.Timestamp})
+}
+
+// OnEventMute updates the chat list when a chat is muted or unmuted from another device
+func OnEventMute(source *WhatsmeowHandlers, evt events.Mute) {
+	var until int64
+	if evt.Action.GetMuted() {
+		until = evt.Action.GetMuteEndTimestamp()
+		if until < 0 {
+			until = -1
+		}
+	}
+
+	source.followChatState(evt.JID, &whatsapp.WhatsappChatState{MutedUntil: &until, Timestamp: evt.Timestamp})
+}
+
+// OnEventMarkChatAsRead updates the chat list when a whole chat is marked as read or unread from another device
+func OnEventMarkChatAsRead(source *WhatsmeowHandlers, evt events.MarkChatAsRead) {
+	state := &whatsapp.WhatsappChatState{Timestamp: evt.Timestamp}
+
+	markedUnread := !evt.Action.GetRead()
+	state.MarkedUnread = &markedUnread
+	if !markedUnread {
+		var unread uint32
+		state.Unread = &unread
+	}
+
+	source.followChatState(evt.JID, state)
+}
+
+// OnEventDeleteChat removes a chat deleted from another device from the chat list
+func OnEventDeleteChat(source *WhatsmeowHandlers, evt events.DeleteChat) {
+	source.followChatState(evt.JID, &whatsapp.WhatsappChatState{Deleted: true, Timestamp: evt.Timestamp})
+}
+
+// GetHistorySyncChatState converts a history sync conversation into a full chat list entry
+func GetHistorySyncChatState(conversation *waHistorySync.Conversation) *whatsapp.WhatsappChatState {
+	title := conversation.GetName()
+	if len(title) == 0 {
+		title = conversation.GetDisplayName()
+	}
+
+	archived := conversation.GetArchived()
+	pinned := conversation.GetPinned() > 0
+	unread := conversation.GetUnreadCount()
+	markedUnread := conversation.GetMarkedAsUnread()
+	ephemeral := conversation.GetEphemeralExpiration()
+
+	// history sync conversations hold the mute end in seconds
+	var mutedUntil int64
+	if end := conversation.GetMuteEndTime(); end > 0 {
+		mutedUntil = time.Unix(int64(end), 0).UnixMilli()
+	}
+
+	return &whatsapp.WhatsappChatState{
+		Title:        title,
+		Archived:     &archived,
+		Pinned:       &pinned,
+		MutedUntil:   &mutedUntil,
+		Unread:       &unread,
+		MarkedUnread: &markedUnread,
+		Ephemeral:    &ephemeral,
+		Timestamp:    time.Unix(int64(conversation.GetConversationTimestamp()), 0),
+	}
+}
+
+func (source *WhatsmeowHandlers) followChatState(jid types.JID, state *whatsapp.WhatsappChatState) {
+	if source == nil || source.WAHandlers == nil || state == nil {
+		return
+	}
+
+	state.Id = jid.ToNonAD().String()
+	if state.Timestamp.IsZero() {
+		state.Timestamp = source.getTimestamp()
+	}
+
+	logentry := source.GetLogger().WithField(LogFields.ChatId, state.Id)
+	logentry.Tracef("chat state: %+v", state)
+
+	// synchronous, an older state dispatched late would overwrite a newer one
+	source.WAHandlers.ChatState(state)
+}
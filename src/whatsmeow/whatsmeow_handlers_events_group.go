@@ -0,0 +1,217 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"time"
+
+	qpevents "github.com/nocodeleaks/quepasa/events"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// OnEventGroupInfo updates the group info cache and dispatches one group message per change
+func OnEventGroupInfo(source *WhatsmeowHandlers, evt events.GroupInfo) {
+	if source == nil {
+		return
+	}
+
+	logentry := source.GetLogger()
+	logentry.Debugf("on event group info: %+v", evt)
+
+	// cache must follow the changes, even when groups are not dispatched
+	previous := GroupInfoCache.Update(source.Client, evt)
+
+	// unknown to this session, seeded after the event, so the next changes have previous values
+	if previous == nil && evt.Delete == nil && GroupInfoCache.GetInfo(source.Client, evt.JID) == nil {
+		GroupInfoCache.GetOrFetchInfo(source.Client, evt.JID, "event")
+	}
+
+	if !source.HandleGroups() {
+		return
+	}
+
+	toChat := func(jid types.JID) whatsapp.WhatsappChat {
+		return *NewWhatsappChat(source, jid.ToNonAD())
+	}
+
+	updates := GetGroupUpdates(evt, previous, toChat)
+	if len(updates) == 0 {
+		logentry.Debug("group info event without known changes")
+		return
+	}
+
+	actor := GetGroupInfoActor(evt)
+	for i := range updates {
+		update := &updates[i]
+		if !actor.IsEmpty() {
+			chat := toChat(actor)
+			update.Actor = &chat
+		}
+
+		source.followGroupUpdate(evt, evt.JID, evt.Timestamp, update)
+	}
+}
+
+// OnEventPicture invalidates the cached picture and dispatches group photo or contact picture changes
+func OnEventPicture(source *WhatsmeowHandlers, evt events.Picture) {
+	if source == nil {
+		return
+	}
+
+	key := GetProfileCacheKey(source.Client, evt.JID)
+	var previous any
+	if cached, found := PictureCache.GetPicture(key); found {
+		previous = cached.Id
+	}
+	PictureCache.DeleteByKey(key)
+
+	if evt.JID.Server != types.GroupServer {
+		OnEventContactPicture(source, evt, previous)
+		return
+	}
+
+	if !source.HandleGroups() {
+		return
+	}
+
+	update := &whatsapp.WhatsappGroupUpdate{
+		Action:   whatsapp.GroupUpdatePhoto,
+		OldValue: previous,
+		NewValue: evt.PictureID,
+	}
+
+	if !evt.Author.IsEmpty() {
+		actor := *NewWhatsappChat(source, evt.Author.ToNonAD())
+		update.Actor = &actor
+	}
+
+	source.followGroupUpdate(evt, evt.JID, evt.Timestamp, update)
+}
+
+// followGroupUpdate dispatches a group change as a group message, groups option is respected by the internal handlers
+func (source *WhatsmeowHandlers) followGroupUpdate(evt any, group types.JID, timestamp time.Time, update *whatsapp.WhatsappGroupUpdate) {
+	if timestamp.IsZero() {
+		timestamp = source.getTimestamp()
+	}
+
+	var id string
+	if source.Client != nil {
+		id = source.Client.GenerateMessageID()
+	}
+
+	message := &whatsapp.WhatsappMessage{
+		Content:     evt,
+		Id:          fmt.Sprintf("group_%s", id),
+		Timestamp:   timestamp,
+		Type:        whatsapp.GroupMessageType,
+		Chat:        *NewWhatsappChat(source, group),
+		Participant: update.Actor,
+		Text:        string(update.Action),
+		GroupUpdate: update,
+	}
+
+	source.Follow(message, "group")
+
+	qpevents.Publish(qpevents.Event{
+		Name:   "whatsapp.group.updated",
+		Source: "whatsmeow.handlers",
+		Status: "success",
+		Attributes: map[string]string{
+			"action": string(update.Action),
+		},
+	})
+}
+
+// GetGroupInfoActor returns who made the change, preferring the phone number when the sender is a LID
+func GetGroupInfoActor(evt events.GroupInfo) types.JID {
+	if evt.SenderPN != nil && !evt.SenderPN.IsEmpty() {
+		return *evt.SenderPN
+	}
+	if evt.Sender != nil {
+		return *evt.Sender
+	}
+	return types.EmptyJID
+}
+
+// GetGroupUpdates lists the changes of a group info event, old values come from the
+// previous cached group info when known
+func GetGroupUpdates(evt events.GroupInfo, previous *types.GroupInfo, toChat func(types.JID) whatsapp.WhatsappChat) (updates []whatsapp.WhatsappGroupUpdate) {
+	targets := func(jids []types.JID) (chats []whatsapp.WhatsappChat) {
+		for _, jid := range jids {
+			chats = append(chats, toChat(jid))
+		}
+		return
+	}
+
+	if len(evt.Join) > 0 {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdateJoin, Targets: targets(evt.Join), Reason: evt.JoinReason})
+	}
+	if len(evt.Leave) > 0 {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdateLeave, Targets: targets(evt.Leave)})
+	}
+	if len(evt.Promote) > 0 {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdatePromote, Targets: targets(evt.Promote)})
+	}
+	if len(evt.Demote) > 0 {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdateDemote, Targets: targets(evt.Demote)})
+	}
+
+	// setting changes, old value is nil when the group was not cached
+	setting := func(action whatsapp.WhatsappGroupUpdateAction, oldValue func(*types.GroupInfo) any, newValue any) {
+		update := whatsapp.WhatsappGroupUpdate{Action: action, NewValue: newValue}
+		if previous != nil {
+			update.OldValue = oldValue(previous)
+		}
+		updates = append(updates, update)
+	}
+
+	if evt.Name != nil {
+		setting(whatsapp.GroupUpdateSubject, func(info *types.GroupInfo) any { return info.Name }, evt.Name.Name)
+	}
+	if evt.Topic != nil {
+		topic := evt.Topic.Topic
+		if evt.Topic.TopicDeleted {
+			topic = ""
+		}
+		setting(whatsapp.GroupUpdateDescription, func(info *types.GroupInfo) any { return info.Topic }, topic)
+	}
+	if evt.Locked != nil {
+		setting(whatsapp.GroupUpdateLocked, func(info *types.GroupInfo) any { return info.IsLocked }, evt.Locked.IsLocked)
+	}
+	if evt.Announce != nil {
+		setting(whatsapp.GroupUpdateAnnounce, func(info *types.GroupInfo) any { return info.IsAnnounce }, evt.Announce.IsAnnounce)
+	}
+	if evt.Ephemeral != nil {
+		setting(whatsapp.GroupUpdateEphemeral, func(info *types.GroupInfo) any { return GetGroupEphemeralTimer(info.GroupEphemeral) }, GetGroupEphemeralTimer(*evt.Ephemeral))
+	}
+	if evt.MembershipApprovalMode != nil {
+		setting(whatsapp.GroupUpdateApproval, func(info *types.GroupInfo) any { return info.IsJoinApprovalRequired }, evt.MembershipApprovalMode.IsJoinApprovalRequired)
+	}
+	if evt.Suspended || evt.Unsuspended {
+		setting(whatsapp.GroupUpdateSuspended, func(info *types.GroupInfo) any { return info.Suspended }, evt.Suspended)
+	}
+
+	if evt.NewInviteLink != nil {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdateInviteLink, NewValue: *evt.NewInviteLink})
+	}
+	if evt.Link != nil {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdateLink, NewValue: evt.Link.Group.JID.String(), Reason: string(evt.Link.Type)})
+	}
+	if evt.Unlink != nil {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdateUnlink, OldValue: evt.Unlink.Group.JID.String(), Reason: string(evt.Unlink.UnlinkReason)})
+	}
+	if evt.Delete != nil {
+		updates = append(updates, whatsapp.WhatsappGroupUpdate{Action: whatsapp.GroupUpdateDelete, NewValue: evt.Delete.Deleted, Reason: evt.Delete.DeleteReason})
+	}
+
+	return
+}
+
+// GetGroupEphemeralTimer returns the disappearing messages timer in seconds, 0 when disabled
+func GetGroupEphemeralTimer(ephemeral types.GroupEphemeral) uint32 {
+	if !ephemeral.IsEphemeral {
+		return 0
+	}
+	return ephemeral.DisappearingTimer
+}
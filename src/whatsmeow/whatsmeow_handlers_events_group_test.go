@@ -0,0 +1,95 @@
+package whatsmeow
+
+import (
+	"testing"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func testGroupChat(jid types.JID) whatsapp.WhatsappChat {
+	return whatsapp.WhatsappChat{Id: jid.String()}
+}
+
+func TestGetGroupUpdatesWithPreviousInfo(t *testing.T) {
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	member := types.NewJID("5521999999999", types.DefaultUserServer)
+
+	previous := &types.GroupInfo{JID: group}
+	previous.Name = "old subject"
+	previous.IsLocked = false
+
+	evt := events.GroupInfo{
+		JID:     group,
+		Name:    &types.GroupName{Name: "new subject"},
+		Locked:  &types.GroupLocked{IsLocked: true},
+		Promote: []types.JID{member},
+	}
+
+	updates := GetGroupUpdates(evt, previous, testGroupChat)
+	if len(updates) != 3 {
+		t.Fatalf("expected 3 updates, got %d: %+v", len(updates), updates)
+	}
+
+	if updates[0].Action != whatsapp.GroupUpdatePromote || len(updates[0].Targets) != 1 || updates[0].Targets[0].Id != member.String() {
+		t.Errorf("unexpected promote update: %+v", updates[0])
+	}
+
+	if updates[1].Action != whatsapp.GroupUpdateSubject || updates[1].OldValue != "old subject" || updates[1].NewValue != "new subject" {
+		t.Errorf("unexpected subject update: %+v", updates[1])
+	}
+
+	if updates[2].Action != whatsapp.GroupUpdateLocked || updates[2].OldValue != false || updates[2].NewValue != true {
+		t.Errorf("unexpected locked update: %+v", updates[2])
+	}
+
+	// without cached info, old values are unknown
+	updates = GetGroupUpdates(evt, nil, testGroupChat)
+	if updates[1].OldValue != nil {
+		t.Errorf("expected unknown old value, got: %v", updates[1].OldValue)
+	}
+}
+
+func TestApplyGroupInfoEventParticipants(t *testing.T) {
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	admin := types.NewJID("5521911111111", types.DefaultUserServer)
+	leaving := types.NewJID("5521922222222", types.DefaultUserServer)
+	joining := types.NewJID("5521933333333", types.DefaultUserServer)
+
+	info := types.GroupInfo{
+		JID: group,
+		Participants: []types.GroupParticipant{
+			{JID: admin, IsAdmin: true},
+			{JID: leaving},
+		},
+	}
+
+	current := ApplyGroupInfoEvent(info, events.GroupInfo{
+		JID:    group,
+		Leave:  []types.JID{leaving},
+		Join:   []types.JID{joining},
+		Demote: []types.JID{admin},
+		Topic:  &types.GroupTopic{Topic: "about"},
+	})
+
+	if len(current.Participants) != 2 || current.ParticipantCount != 2 {
+		t.Fatalf("unexpected participants: %+v", current.Participants)
+	}
+
+	if current.Participants[0].IsAdmin {
+		t.Errorf("admin should be demoted")
+	}
+
+	if current.Participants[1].JID != joining {
+		t.Errorf("expected joined participant, got: %v", current.Participants[1].JID)
+	}
+
+	if current.Topic != "about" {
+		t.Errorf("expected topic to be applied, got: %s", current.Topic)
+	}
+
+	if len(info.Participants) != 2 || !info.Participants[0].IsAdmin {
+		t.Errorf("previous info should not change")
+	}
+}
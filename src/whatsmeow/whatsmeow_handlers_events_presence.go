@@ -0,0 +1,79 @@
+package whatsmeow
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// OnEventPresence dispatches online and last seen changes of subscribed contacts
+func OnEventPresence(source *WhatsmeowHandlers, evt events.Presence) {
+	if source == nil || source.WAHandlers == nil {
+		return
+	}
+
+	presence := &whatsapp.WhatsappPresence{State: whatsapp.WhatsappPresenceOnline}
+	if evt.Unavailable {
+		presence.State = whatsapp.WhatsappPresenceOffline
+		if !evt.LastSeen.IsZero() {
+			lastseen := evt.LastSeen
+			presence.LastSeen = &lastseen
+		}
+	}
+
+	chat := NewWhatsappChat(source, evt.From.ToNonAD())
+	source.followPresence(evt, *chat, nil, presence)
+}
+
+// OnEventChatPresence dispatches typing, recording and paused activities on chats
+func OnEventChatPresence(source *WhatsmeowHandlers, evt events.ChatPresence) {
+	if source == nil || source.WAHandlers == nil {
+		return
+	}
+
+	if evt.IsGroup && !source.HandleGroups() {
+		return
+	}
+
+	presence := &whatsapp.WhatsappPresence{State: GetWhatsappPresenceState(evt.State, evt.Media)}
+	chat := NewWhatsappChat(source, evt.Chat.ToNonAD())
+
+	var participant *whatsapp.WhatsappChat
+	if evt.IsGroup {
+		participant = NewWhatsappChat(source, evt.Sender.ToNonAD())
+	}
+
+	source.followPresence(evt, *chat, participant, presence)
+}
+
+// GetWhatsappPresenceState converts a whatsmeow chat activity into a presence state
+func GetWhatsappPresenceState(state types.ChatPresence, media types.ChatPresenceMedia) whatsapp.WhatsappPresenceState {
+	if state != types.ChatPresenceComposing {
+		return whatsapp.WhatsappPresencePaused
+	}
+
+	if media == types.ChatPresenceMediaAudio {
+		return whatsapp.WhatsappPresenceRecording
+	}
+
+	return whatsapp.WhatsappPresenceTyping
+}
+
+func (source *WhatsmeowHandlers) followPresence(evt any, chat whatsapp.WhatsappChat, participant *whatsapp.WhatsappChat, presence *whatsapp.WhatsappPresence) {
+	logentry := source.GetLogger()
+	logentry.Tracef("presence update, chat: %s, state: %s", chat.Id, presence.State)
+
+	message := &whatsapp.WhatsappMessage{
+		Content:     evt,
+		Id:          "presence",
+		Timestamp:   source.getTimestamp(),
+		Type:        whatsapp.SystemMessageType,
+		Chat:        chat,
+		Participant: participant,
+		Text:        string(presence.State),
+		Presence:    presence,
+	}
+
+	// synchronous, a paused dispatched late would arrive before the typing it ends
+	source.WAHandlers.Presence(message)
+}
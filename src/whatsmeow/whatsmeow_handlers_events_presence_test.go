@@ -0,0 +1,27 @@
+package whatsmeow
+
+import (
+	"testing"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+func TestGetWhatsappPresenceState(t *testing.T) {
+	cases := []struct {
+		state    types.ChatPresence
+		media    types.ChatPresenceMedia
+		expected whatsapp.WhatsappPresenceState
+	}{
+		{types.ChatPresenceComposing, types.ChatPresenceMediaText, whatsapp.WhatsappPresenceTyping},
+		{types.ChatPresenceComposing, types.ChatPresenceMediaAudio, whatsapp.WhatsappPresenceRecording},
+		{types.ChatPresencePaused, types.ChatPresenceMediaText, whatsapp.WhatsappPresencePaused},
+		{types.ChatPresencePaused, types.ChatPresenceMediaAudio, whatsapp.WhatsappPresencePaused},
+	}
+
+	for _, c := range cases {
+		if result := GetWhatsappPresenceState(c.state, c.media); result != c.expected {
+			t.Errorf("state %q media %q: expected %s, got %s", c.state, c.media, c.expected, result)
+		}
+	}
+}
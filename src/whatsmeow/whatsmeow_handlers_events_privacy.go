@@ -0,0 +1,87 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"strings"
+
+	qpevents "github.com/nocodeleaks/quepasa/events"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// OnEventPrivacySettings dispatches privacy changes pushed from another device (usually the phone)
+func OnEventPrivacySettings(source *WhatsmeowHandlers, evt events.PrivacySettings) {
+	if source == nil {
+		return
+	}
+
+	logentry := source.GetLogger()
+	logentry.Debugf("on event privacy settings: %+v", evt)
+
+	changed := GetChangedPrivacySettings(evt)
+	if len(changed) == 0 {
+		logentry.Debug("privacy settings event without known changes")
+		return
+	}
+
+	settings := ToWhatsappPrivacySettings(evt.NewSettings)
+	info := map[string]interface{}{
+		"event":    "privacy_settings",
+		"changed":  changed,
+		"settings": settings,
+	}
+
+	var id string
+	if source.Client != nil {
+		id = source.Client.GenerateMessageID()
+	}
+
+	message := &whatsapp.WhatsappMessage{
+		Content:   evt,
+		Id:        fmt.Sprintf("privacy_%s", id),
+		Timestamp: source.getTimestamp(),
+		Type:      whatsapp.SystemMessageType,
+		Chat:      whatsapp.WASYSTEMCHAT,
+		Text:      library.ToJson(info),
+		Info:      info,
+		FromMe:    true,
+	}
+
+	source.Follow(message, "privacy")
+
+	qpevents.Publish(qpevents.Event{
+		Name:   "whatsapp.privacy.updated",
+		Source: "whatsmeow.handlers",
+		Status: "success",
+		Attributes: map[string]string{
+			"changed": strings.Join(changed, ","),
+		},
+	})
+}
+
+// GetChangedPrivacySettings lists the API names of settings flagged as changed on the event
+func GetChangedPrivacySettings(evt events.PrivacySettings) (changed []string) {
+	if evt.LastSeenChanged {
+		changed = append(changed, string(whatsapp.PrivacySettingLastSeen))
+	}
+	if evt.OnlineChanged {
+		changed = append(changed, string(whatsapp.PrivacySettingOnline))
+	}
+	if evt.ProfileChanged {
+		changed = append(changed, string(whatsapp.PrivacySettingProfile))
+	}
+	if evt.StatusChanged {
+		changed = append(changed, string(whatsapp.PrivacySettingAbout))
+	}
+	if evt.ReadReceiptsChanged {
+		changed = append(changed, string(whatsapp.PrivacySettingReadReceipts))
+	}
+	if evt.GroupAddChanged {
+		changed = append(changed, string(whatsapp.PrivacySettingGroupAdd))
+	}
+	if evt.CallAddChanged {
+		changed = append(changed, string(whatsapp.PrivacySettingCallAdd))
+	}
+	return
+}
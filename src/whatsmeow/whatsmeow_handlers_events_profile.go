@@ -0,0 +1,135 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"time"
+
+	qpevents "github.com/nocodeleaks/quepasa/events"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// OnEventContactPicture dispatches contact picture changes, the cache was already invalidated
+func OnEventContactPicture(source *WhatsmeowHandlers, evt events.Picture, previous any) {
+	update := &whatsapp.WhatsappContactUpdate{
+		Action:   whatsapp.ContactUpdatePicture,
+		OldValue: previous,
+		NewValue: evt.PictureID,
+	}
+
+	source.followContactUpdate(evt, evt.JID, evt.Timestamp, update)
+}
+
+// OnEventPushName dispatches push name changes, whatsmeow already updated the contacts store
+func OnEventPushName(source *WhatsmeowHandlers, evt events.PushName) {
+	if source == nil {
+		return
+	}
+
+	// keeping lid and phone mappings fresh, as both addresses are known here
+	RefreshContactMaps(evt.JID, evt.JIDAlt)
+
+	update := &whatsapp.WhatsappContactUpdate{
+		Action:   whatsapp.ContactUpdatePushName,
+		NewValue: evt.NewPushName,
+	}
+
+	if len(evt.OldPushName) > 0 {
+		update.OldValue = evt.OldPushName
+	}
+
+	var timestamp time.Time
+	if evt.Message != nil {
+		timestamp = evt.Message.Timestamp
+	}
+
+	source.followContactUpdate(evt, evt.JID, timestamp, update)
+}
+
+// OnEventUserAbout dispatches about changes, old value is known only if seen before on this session
+func OnEventUserAbout(source *WhatsmeowHandlers, evt events.UserAbout) {
+	if source == nil {
+		return
+	}
+
+	key := GetProfileCacheKey(source.Client, evt.JID)
+	update := &whatsapp.WhatsappContactUpdate{
+		Action:   whatsapp.ContactUpdateAbout,
+		NewValue: evt.Status,
+	}
+
+	if previous, found := AboutCache.GetString(key); found {
+		if previous == evt.Status {
+			return
+		}
+		update.OldValue = previous
+	}
+
+	AboutCache.Set(key, evt.Status, "event")
+	source.followContactUpdate(evt, evt.JID, evt.Timestamp, update)
+}
+
+// OnEventIdentityChange dispatches primary device changes, commonly a new phone or reinstall
+func OnEventIdentityChange(source *WhatsmeowHandlers, evt events.IdentityChange) {
+	if source == nil {
+		return
+	}
+
+	update := &whatsapp.WhatsappContactUpdate{
+		Action:   whatsapp.ContactUpdateIdentity,
+		Implicit: evt.Implicit,
+	}
+
+	source.followContactUpdate(evt, evt.JID, evt.Timestamp, update)
+}
+
+// RefreshContactMaps stores the lid and phone relation when both addresses of a contact are known
+func RefreshContactMaps(jid types.JID, alt types.JID) {
+	if jid.IsEmpty() || alt.IsEmpty() {
+		return
+	}
+
+	if jid.Server == types.DefaultUserServer {
+		jid, alt = alt, jid
+	}
+
+	if jid.Server != types.HiddenUserServer || alt.Server != types.DefaultUserServer {
+		return
+	}
+
+	GetGlobalContactMaps().SetPhoneFromLIDMap(jid.ToNonAD().String(), alt.User)
+}
+
+// followContactUpdate dispatches a contact change as a system message on the contact chat
+func (source *WhatsmeowHandlers) followContactUpdate(evt any, contact types.JID, timestamp time.Time, update *whatsapp.WhatsappContactUpdate) {
+	if timestamp.IsZero() {
+		timestamp = source.getTimestamp()
+	}
+
+	var id string
+	if source.Client != nil {
+		id = source.Client.GenerateMessageID()
+	}
+
+	message := &whatsapp.WhatsappMessage{
+		Content:       evt,
+		Id:            fmt.Sprintf("contact_%s", id),
+		Timestamp:     timestamp,
+		Type:          whatsapp.SystemMessageType,
+		Chat:          *NewWhatsappChat(source, contact.ToNonAD()),
+		Text:          string(update.Action),
+		ContactUpdate: update,
+	}
+
+	source.Follow(message, "contact")
+
+	qpevents.Publish(qpevents.Event{
+		Name:   "whatsapp.contact.updated",
+		Source: "whatsmeow.handlers",
+		Status: "success",
+		Attributes: map[string]string{
+			"action": string(update.Action),
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package whatsmeow
+
+import (
+	"testing"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+func TestRefreshContactMapsWithAlternateJID(t *testing.T) {
+	lid := types.NewJID("123456789012345", types.HiddenUserServer)
+	phone := types.NewJID("5521977777777", types.DefaultUserServer)
+
+	// order of addresses must not matter
+	RefreshContactMaps(phone, lid)
+
+	cached, found := GetGlobalContactMaps().GetPhoneFromLIDMap(lid.String())
+	if !found || cached != "+5521977777777" {
+		t.Errorf("expected phone mapping for lid, got: %q (found: %v)", cached, found)
+	}
+}
+
+func TestPictureCacheReturnsCopies(t *testing.T) {
+	jid := types.NewJID("5521966666666", types.DefaultUserServer)
+	key := GetProfileCacheKey(nil, jid)
+	defer PictureCache.DeleteByKey(key)
+
+	PictureCache.Set(key, whatsapp.WhatsappProfilePicture{Id: "1", Url: "https://example.com/a.jpg"}, "test")
+
+	first, found := PictureCache.GetPicture(key)
+	if !found || first.Id != "1" {
+		t.Fatalf("expected cached picture, got: %+v", first)
+	}
+
+	first.Id = "changed"
+	second, _ := PictureCache.GetPicture(key)
+	if second.Id != "1" {
+		t.Errorf("cached picture should not change through returned values")
+	}
+
+	PictureCache.DeleteByKey(key)
+	if _, found := PictureCache.GetPicture(key); found {
+		t.Errorf("picture should be invalidated")
+	}
+}
@@ -0,0 +1,49 @@
+package whatsmeow
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	waHistorySync "go.mau.fi/whatsmeow/proto/waHistorySync"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// IsOnDemandHistorySync returns true for history requested by HistorySyncChat
+func IsOnDemandHistorySync(evt events.HistorySync) bool {
+	return evt.Data.GetSyncType() == waHistorySync.HistorySync_ON_DEMAND
+}
+
+// OnDemandHistorySync dispatches requested history, ignoring history sync options,
+// and notifies the result of each chat
+func (source *WhatsmeowHandlers) OnDemandHistorySync(conversations []*waHistorySync.Conversation) {
+	logentry := source.GetLogger()
+	for _, conversation := range conversations {
+		jid, err := types.ParseJID(conversation.GetID())
+		if err != nil {
+			logentry.Errorf("failed to parse jid at on demand history sync: %v", err)
+			continue
+		}
+
+		count := 0
+		for _, historyMsg := range conversation.GetMessages() {
+			msgevt, err := source.Client.ParseWebMessage(jid, historyMsg.GetMessage())
+			if err != nil {
+				logentry.Errorf("failed to parse web message at on demand history sync: %v", err)
+				continue
+			}
+
+			source.Message(*msgevt, "history")
+			count++
+		}
+
+		result := &whatsapp.WhatsappHistoryChatSync{
+			ChatId:   jid.ToNonAD().String(),
+			Messages: count,
+			More:     conversation.GetEndOfHistoryTransferType() != waHistorySync.Conversation_COMPLETE_AND_NO_MORE_MESSAGE_REMAIN_ON_PRIMARY,
+		}
+
+		logentry.Infof("on demand history sync for: %s, messages: %d, more: %v", result.ChatId, result.Messages, result.More)
+		if source.WAHandlers != nil {
+			go source.WAHandlers.HistorySynced(result)
+		}
+	}
+}
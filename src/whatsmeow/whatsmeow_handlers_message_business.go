@@ -0,0 +1,63 @@
+package whatsmeow
+
+import (
+	"strings"
+
+	log "github.com/nocodeleaks/quepasa/qplog"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+// HandleProductMessage handles a product shared from a catalog, usually a customer inquiry
+func HandleProductMessage(logentry log.Logger, out *whatsapp.WhatsappMessage, in *waE2E.ProductMessage) {
+	logentry.Debug("received a product message !")
+	out.Type = whatsapp.ProductMessageType
+	out.Text = in.GetBody()
+
+	snapshot := in.GetProduct()
+	product := &whatsapp.WhatsappProduct{
+		Id:          snapshot.GetProductID(),
+		RetailerId:  snapshot.GetRetailerID(),
+		Name:        snapshot.GetTitle(),
+		Description: snapshot.GetDescription(),
+		Url:         snapshot.GetURL(),
+		Currency:    snapshot.GetCurrencyCode(),
+		Price:       whatsapp.AmountFromThousandths(snapshot.GetPriceAmount1000()),
+		Owner:       in.GetBusinessOwnerJID(),
+	}
+	out.Product = product
+
+	if len(out.Text) == 0 {
+		out.Text = product.Name
+	}
+
+	if info := in.GetContextInfo(); info != nil && len(info.GetStanzaID()) > 0 {
+		out.InReply = info.GetStanzaID()
+	}
+}
+
+// HandleOrderMessage handles an order placed by a customer from our catalog
+func HandleOrderMessage(logentry log.Logger, out *whatsapp.WhatsappMessage, in *waE2E.OrderMessage) {
+	logentry.Debug("received an order message !")
+	out.Type = whatsapp.OrderMessageType
+	out.Text = in.GetMessage()
+
+	out.Order = &whatsapp.WhatsappOrder{
+		Id:        in.GetOrderID(),
+		Title:     in.GetOrderTitle(),
+		ItemCount: in.GetItemCount(),
+		Status:    strings.ToLower(in.GetStatus().String()),
+		Seller:    in.GetSellerJID(),
+		Token:     in.GetToken(),
+		Total:     whatsapp.AmountFromThousandths(in.GetTotalAmount1000()),
+		Currency:  in.GetTotalCurrencyCode(),
+	}
+
+	if len(out.Text) == 0 {
+		out.Text = out.Order.Title
+	}
+
+	if info := in.GetContextInfo(); info != nil && len(info.GetStanzaID()) > 0 {
+		out.InReply = info.GetStanzaID()
+	}
+}
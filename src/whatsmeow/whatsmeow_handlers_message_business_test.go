@@ -0,0 +1,120 @@
+package whatsmeow
+
+import (
+	"testing"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHandleKnowingMessagesOrderMessage(t *testing.T) {
+	handler := &WhatsmeowHandlers{}
+	out := &whatsapp.WhatsappMessage{
+		Id:   "test-order-id",
+		Chat: whatsapp.WhatsappChat{Id: "12345@s.whatsapp.net"},
+	}
+	in := &waE2E.Message{
+		OrderMessage: &waE2E.OrderMessage{
+			OrderID:           proto.String("987"),
+			OrderTitle:        proto.String("Coffee shop"),
+			ItemCount:         proto.Int32(3),
+			Status:            waE2E.OrderMessage_INQUIRY.Enum(),
+			TotalAmount1000:   proto.Int64(25500),
+			TotalCurrencyCode: proto.String("BRL"),
+		},
+	}
+
+	HandleKnowingMessages(handler, out, in)
+
+	if out.Type != whatsapp.OrderMessageType {
+		t.Fatalf("expected type %v, got %v", whatsapp.OrderMessageType, out.Type)
+	}
+	if out.Order == nil {
+		t.Fatal("expected order to be set")
+	}
+	if out.Order.Id != "987" || out.Order.ItemCount != 3 || out.Order.Status != "inquiry" {
+		t.Fatalf("unexpected order: %+v", out.Order)
+	}
+	if out.Order.Total != 25.5 || out.Order.Currency != "BRL" {
+		t.Fatalf("unexpected order total: %v %s", out.Order.Total, out.Order.Currency)
+	}
+	if out.Text != "Coffee shop" {
+		t.Fatalf("expected order title as text, got %q", out.Text)
+	}
+}
+
+func TestHandleKnowingMessagesProductMessage(t *testing.T) {
+	handler := &WhatsmeowHandlers{}
+	out := &whatsapp.WhatsappMessage{
+		Id:   "test-product-id",
+		Chat: whatsapp.WhatsappChat{Id: "12345@s.whatsapp.net"},
+	}
+	in := &waE2E.Message{
+		ProductMessage: &waE2E.ProductMessage{
+			Product: &waE2E.ProductMessage_ProductSnapshot{
+				ProductID:       proto.String("p1"),
+				Title:           proto.String("Espresso"),
+				CurrencyCode:    proto.String("USD"),
+				PriceAmount1000: proto.Int64(3990),
+			},
+			BusinessOwnerJID: proto.String("5511999999999@s.whatsapp.net"),
+			Body:             proto.String("is it available?"),
+		},
+	}
+
+	HandleKnowingMessages(handler, out, in)
+
+	if out.Type != whatsapp.ProductMessageType {
+		t.Fatalf("expected type %v, got %v", whatsapp.ProductMessageType, out.Type)
+	}
+	if out.Product == nil || out.Product.Id != "p1" || out.Product.Price != 3.99 {
+		t.Fatalf("unexpected product: %+v", out.Product)
+	}
+	if out.Text != "is it available?" {
+		t.Fatalf("expected body as text, got %q", out.Text)
+	}
+}
+
+func TestParseCatalogNode(t *testing.T) {
+	node := waBinary.Node{
+		Tag: "product_catalog",
+		Content: []waBinary.Node{
+			{
+				Tag:   "product",
+				Attrs: waBinary.Attrs{"is_hidden": "true"},
+				Content: []waBinary.Node{
+					{Tag: "id", Content: []byte("p1")},
+					{Tag: "name", Content: []byte("Espresso")},
+					{Tag: "price", Content: []byte("3990")},
+					{Tag: "currency", Content: []byte("USD")},
+					{Tag: "media", Content: []waBinary.Node{{
+						Tag:     "image",
+						Content: []waBinary.Node{{Tag: "original_image_url", Content: []byte("https://example.com/p1.jpg")}},
+					}}},
+				},
+			},
+			{
+				Tag:     "paging",
+				Content: []waBinary.Node{{Tag: "after", Content: []byte("next")}},
+			},
+		},
+	}
+
+	catalog := ParseCatalogNode(node)
+	if len(catalog.Products) != 1 {
+		t.Fatalf("expected 1 product, got %d", len(catalog.Products))
+	}
+
+	product := catalog.Products[0]
+	if product.Id != "p1" || product.Name != "Espresso" || product.Price != 3.99 || !product.Hidden {
+		t.Fatalf("unexpected product: %+v", product)
+	}
+	if product.ImageUrl != "https://example.com/p1.jpg" {
+		t.Fatalf("unexpected image url: %s", product.ImageUrl)
+	}
+	if catalog.Cursor != "next" {
+		t.Fatalf("expected cursor %q, got %q", "next", catalog.Cursor)
+	}
+}
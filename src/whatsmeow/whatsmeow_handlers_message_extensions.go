@@ -57,6 +57,10 @@ func HandleKnowingMessages(handler *WhatsmeowHandlers, out *whatsapp.WhatsappMes
 		HandleContactsArrayMessage(handler, logentry, out, in.ContactsArrayMessage)
 	case in.ListMessage != nil:
 		HandleListMessage(logentry, out, in.ListMessage)
+	case in.ProductMessage != nil:
+		HandleProductMessage(logentry, out, in.ProductMessage)
+	case in.OrderMessage != nil:
+		HandleOrderMessage(logentry, out, in.OrderMessage)
 	case in.SenderKeyDistributionMessage != nil:
 
 		json := library.ToJson(in.SenderKeyDistributionMessage)
@@ -382,6 +386,7 @@ func HandleStickerMessage(log log.Logger, out *whatsapp.WhatsappMessage, in *waE
 	out.Attachment = &whatsapp.WhatsappAttachment{
 		Mimetype:   in.GetMimetype(),
 		FileLength: in.GetFileLength(),
+		Animated:   in.GetIsAnimated(),
 	}
 
 	// handling thumbnail
@@ -421,6 +426,7 @@ func HandleDocumentMessage(logentry log.Logger, out *whatsapp.WhatsappMessage, i
 		Mimetype:   in.GetMimetype(),
 		FileLength: in.GetFileLength(),
 		FileName:   in.GetFileName(),
+		PageCount:  in.GetPageCount(),
 	}
 
 	// handling thumnail
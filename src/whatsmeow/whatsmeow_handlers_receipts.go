@@ -0,0 +1,53 @@
+package whatsmeow
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// IsGroupReceipt returns true for receipts sent by participants of a group chat
+func IsGroupReceipt(evt events.Receipt) bool {
+	return evt.Chat.Server == types.GroupServer && !evt.IsFromMe
+}
+
+// GroupReceipt records per participant receipts of our own messages sent to groups
+func (source *WhatsmeowHandlers) GroupReceipt(evt events.Receipt) {
+	if source.WAHandlers == nil || !IsGroupReceipt(evt) {
+		return
+	}
+
+	status := GetWhatsappMessageStatus(evt.Type)
+	if status == whatsapp.WhatsappMessageStatusUnknown {
+		return
+	}
+
+	receipt := whatsapp.WhatsappMessageReceipt{
+		Participant: evt.Sender.ToNonAD().String(),
+		Status:      status,
+		Timestamp:   evt.Timestamp,
+		Recipients:  source.GetGroupRecipients(evt.Chat),
+	}
+
+	logentry := source.GetLogger()
+	for _, id := range evt.MessageIDs {
+		if !source.WAHandlers.MessageReceipt(id, receipt) {
+			continue
+		}
+
+		sublogentry := logentry.WithField(LogFields.MessageId, id)
+		sublogentry.Tracef("group receipt from: %s, status: %s", receipt.Participant, status)
+	}
+}
+
+// GetGroupRecipients returns the expected recipients of a message sent to the group,
+// all participants except ourselves, 0 when unknown
+func (source *WhatsmeowHandlers) GetGroupRecipients(jid types.JID) int {
+	info := GroupInfoCache.GetOrFetchInfo(source.Client, jid, "GetGroupRecipients")
+
+	if info == nil || len(info.Participants) == 0 {
+		return 0
+	}
+
+	return len(info.Participants) - 1
+}
@@ -0,0 +1,73 @@
+package whatsmeow
+
+import (
+	qpevents "github.com/nocodeleaks/quepasa/events"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// HandleStatuses returns true when contacts statuses (stories) should be dispatched
+func (source *WhatsmeowHandlers) HandleStatuses() bool {
+	if source == nil || source.WhatsappOptions == nil {
+		return false
+	}
+
+	return source.WhatsappOptions.Statuses
+}
+
+// IsStatusViewReceipt returns true for receipts sent by contacts viewing our own status posts
+func IsStatusViewReceipt(evt events.Receipt) bool {
+	if evt.Chat != types.StatusBroadcastJID || evt.IsFromMe {
+		return false
+	}
+
+	return evt.Type == types.ReceiptTypeRead || evt.Type == types.ReceiptTypePlayed
+}
+
+// StatusReceipt records the viewers of our own status posts and dispatches a status viewed event
+func (source *WhatsmeowHandlers) StatusReceipt(evt events.Receipt) {
+	if source.WAHandlers == nil || !IsStatusViewReceipt(evt) {
+		return
+	}
+
+	logentry := source.GetLogger()
+	viewer := whatsapp.WhatsappStatusViewer{
+		Participant: *NewWhatsappChat(source, evt.Sender.ToNonAD()),
+		Timestamp:   evt.Timestamp,
+	}
+
+	for _, id := range evt.MessageIDs {
+		if !source.WAHandlers.StatusViewed(id, viewer) {
+			continue
+		}
+
+		sublogentry := logentry.WithField(LogFields.MessageId, id)
+		sublogentry.Debugf("status viewed by: %s", viewer.Participant.Id)
+
+		qpevents.Publish(qpevents.Event{
+			Name:   "whatsapp.status.viewed",
+			Source: "whatsmeow.handlers",
+			Status: "success",
+			Attributes: map[string]string{
+				"message_id": id,
+				"viewer":     viewer.Participant.Id,
+			},
+		})
+
+		if !source.HandleStatuses() {
+			continue
+		}
+
+		message := &whatsapp.WhatsappMessage{Content: evt}
+		message.Id = "statusviewed"
+		message.Timestamp = evt.Timestamp
+		message.FromMe = false
+		message.Chat = whatsapp.WhatsappChat{Id: types.StatusBroadcastJID.String()}
+		message.Participant = &viewer.Participant
+		message.Type = whatsapp.SystemMessageType
+		message.Text = id
+
+		go source.WAHandlers.Receipt(message)
+	}
+}
@@ -10,5 +10,7 @@ var (
 	MessageReceiveErrors     = metrics.CreateCounterRecorder("quepasa_whatsmeow_message_receive_errors_total", "Total message receive errors via WhatsMeow")
 	MessageReceiveUnhandled  = metrics.CreateCounterRecorder("quepasa_whatsmeow_message_receive_unhandled_total", "Total unhandled messages received via WhatsMeow")
 	MessageReceiveSyncEvents = metrics.CreateCounterRecorder("quepasa_whatsmeow_message_receive_sync_events_total", "Total sync events received via WhatsMeow")
+	UploadCacheHits          = metrics.CreateCounterRecorder("quepasa_whatsmeow_upload_cache_hits_total", "Total media sends reusing a previous upload of identical content")
+	UploadCacheMisses        = metrics.CreateCounterRecorder("quepasa_whatsmeow_upload_cache_misses_total", "Total media uploads not found on the upload cache")
 	MessagesByType           = metrics.CreateCounterVecRecorder("quepasa_whatsmeow_messages_by_type_total", "Total messages by type (text, image, audio, etc)", []string{"type"})
 )
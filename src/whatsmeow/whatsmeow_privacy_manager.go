@@ -0,0 +1,115 @@
+package whatsmeow
+
+import (
+	"context"
+	"fmt"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+// Compile-time interface check
+var _ whatsapp.WhatsappPrivacyManagerInterface = (*WhatsmeowPrivacyManager)(nil)
+
+// WhatsmeowPrivacyManager handles account privacy settings operations for WhatsmeowConnection
+type WhatsmeowPrivacyManager struct {
+	*WhatsmeowConnection // embedded connection for direct access
+}
+
+// NewWhatsmeowPrivacyManager creates a new WhatsmeowPrivacyManager instance
+func NewWhatsmeowPrivacyManager(conn *WhatsmeowConnection) *WhatsmeowPrivacyManager {
+	return &WhatsmeowPrivacyManager{
+		WhatsmeowConnection: conn,
+	}
+}
+
+// GetPrivacySettings returns the current privacy settings, including the status (stories) audience
+func (pm *WhatsmeowPrivacyManager) GetPrivacySettings() (*whatsapp.WhatsappPrivacySettings, error) {
+	if pm.WhatsmeowConnection == nil || pm.Client == nil {
+		return nil, fmt.Errorf("client not defined")
+	}
+
+	ctx := context.Background()
+	settings, err := pm.Client.TryFetchPrivacySettings(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ToWhatsappPrivacySettings(*settings)
+
+	// status audience lives on a separate query, failures here are not fatal
+	statusPrivacy, err := pm.Client.GetStatusPrivacy(ctx)
+	if err != nil {
+		pm.GetLogger().Warnf("error on getting status privacy: %s", err.Error())
+	} else {
+		for _, item := range statusPrivacy {
+			if item.IsDefault {
+				result.Status = string(item.Type)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// SetPrivacySetting changes a single privacy setting and returns the updated settings
+func (pm *WhatsmeowPrivacyManager) SetPrivacySetting(setting whatsapp.WhatsappPrivacySettingType, value string) (*whatsapp.WhatsappPrivacySettings, error) {
+	if pm.WhatsmeowConnection == nil || pm.Client == nil {
+		return nil, fmt.Errorf("client not defined")
+	}
+
+	if err := whatsapp.ValidatePrivacySettingValue(setting, value); err != nil {
+		return nil, err
+	}
+
+	name, err := GetPrivacySettingType(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := pm.Client.SetPrivacySetting(context.Background(), name, types.PrivacySetting(value))
+	if err != nil {
+		return nil, err
+	}
+
+	pm.GetLogger().Infof("privacy setting updated: %s = %s", setting, value)
+	return ToWhatsappPrivacySettings(updated), nil
+}
+
+// GetPrivacySettingType converts an API privacy setting name to the whatsmeow equivalent
+func GetPrivacySettingType(setting whatsapp.WhatsappPrivacySettingType) (types.PrivacySettingType, error) {
+	switch setting {
+	case whatsapp.PrivacySettingLastSeen:
+		return types.PrivacySettingTypeLastSeen, nil
+	case whatsapp.PrivacySettingOnline:
+		return types.PrivacySettingTypeOnline, nil
+	case whatsapp.PrivacySettingProfile:
+		return types.PrivacySettingTypeProfile, nil
+	case whatsapp.PrivacySettingAbout:
+		// whatsmeow names the about text visibility as "status"
+		return types.PrivacySettingTypeStatus, nil
+	case whatsapp.PrivacySettingReadReceipts:
+		return types.PrivacySettingTypeReadReceipts, nil
+	case whatsapp.PrivacySettingGroupAdd:
+		return types.PrivacySettingTypeGroupAdd, nil
+	case whatsapp.PrivacySettingCallAdd:
+		return types.PrivacySettingTypeCallAdd, nil
+	case whatsapp.PrivacySettingStatus:
+		return "", fmt.Errorf("status audience can only be changed from the phone")
+	}
+	return "", fmt.Errorf("invalid privacy setting: %s", setting)
+}
+
+// ToWhatsappPrivacySettings converts whatsmeow privacy settings to the transport shape
+func ToWhatsappPrivacySettings(settings types.PrivacySettings) *whatsapp.WhatsappPrivacySettings {
+	return &whatsapp.WhatsappPrivacySettings{
+		LastSeen:     string(settings.LastSeen),
+		Online:       string(settings.Online),
+		Profile:      string(settings.Profile),
+		About:        string(settings.Status),
+		ReadReceipts: string(settings.ReadReceipts),
+		GroupAdd:     string(settings.GroupAdd),
+		CallAdd:      string(settings.CallAdd),
+	}
+}
@@ -0,0 +1,68 @@
+package whatsmeow
+
+import (
+	"time"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+const DEFAULTEXPIRATION_WPIC time.Duration = time.Duration(1 * time.Hour)
+const DEFAULTEXPIRATION_WABC time.Duration = time.Duration(24 * time.Hour)
+
+// WhatsmeowProfileCache keeps contacts profile information per session, as privacy
+// settings may hide them from some of our sessions
+type WhatsmeowProfileCache struct {
+	library.Cache
+	Expiration time.Duration
+}
+
+// GetProfileCacheKey returns the cache key of a contact for the session of this client
+func GetProfileCacheKey(client *whatsmeow.Client, jid types.JID) string {
+	var wid string
+	if client != nil && client.Store != nil && client.Store.ID != nil {
+		wid = client.Store.ID.User
+	}
+	return wid + "/" + jid.ToNonAD().String()
+}
+
+func (source *WhatsmeowProfileCache) Set(key string, value any, from string) bool {
+	item := library.CacheItem{
+		Key:        key,
+		Value:      value,
+		Expiration: time.Now().Add(source.Expiration),
+	}
+	return source.SetCacheItem(item, "profile-"+from)
+}
+
+// GetPicture returns a copy of the cached picture info, if any
+func (source *WhatsmeowProfileCache) GetPicture(key string) (*whatsapp.WhatsappProfilePicture, bool) {
+	cached, success := source.GetAny(key)
+	if !success {
+		return nil, false
+	}
+
+	picture, ok := cached.(whatsapp.WhatsappProfilePicture)
+	if !ok {
+		return nil, false
+	}
+	return &picture, true
+}
+
+func (source *WhatsmeowProfileCache) GetString(key string) (string, bool) {
+	cached, success := source.GetAny(key)
+	if !success {
+		return "", false
+	}
+
+	value, ok := cached.(string)
+	return value, ok
+}
+
+// PictureCache holds profile pictures info requested through the contact manager, invalidated by picture events
+var PictureCache = WhatsmeowProfileCache{Expiration: DEFAULTEXPIRATION_WPIC}
+
+// AboutCache holds the last known about of contacts, to report old values on about changes
+var AboutCache = WhatsmeowProfileCache{Expiration: DEFAULTEXPIRATION_WABC}
@@ -0,0 +1,102 @@
+package whatsmeow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"go.mau.fi/whatsmeow/appstate"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+// Compile-time interface check
+var _ whatsapp.WhatsappProfileManagerInterface = (*WhatsmeowProfileManager)(nil)
+
+// WhatsmeowProfileManager handles own profile operations for WhatsmeowConnection
+type WhatsmeowProfileManager struct {
+	*WhatsmeowConnection // embedded connection for direct access
+}
+
+// NewWhatsmeowProfileManager creates a new WhatsmeowProfileManager instance
+func NewWhatsmeowProfileManager(conn *WhatsmeowConnection) *WhatsmeowProfileManager {
+	return &WhatsmeowProfileManager{
+		WhatsmeowConnection: conn,
+	}
+}
+
+// SetPushName changes the push name through the critical app state block, as the phone does
+func (pm *WhatsmeowProfileManager) SetPushName(name string) error {
+	if pm.WhatsmeowConnection == nil || pm.Client == nil {
+		return fmt.Errorf("client not defined")
+	}
+
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return fmt.Errorf("push name cannot be empty")
+	}
+
+	err := sendAppState(pm.WhatsmeowConnection, appstate.BuildSettingPushName(name))
+	if err != nil {
+		return fmt.Errorf("failed to update push name: %v", err)
+	}
+
+	// keeping local store in sync, outgoing messages carry this name
+	pm.Client.Store.PushName = name
+	if err := pm.Client.Store.Save(context.Background()); err != nil {
+		pm.GetLogger().Warnf("error on saving push name to store: %s", err.Error())
+	}
+
+	pm.GetLogger().Infof("push name updated: %s", name)
+	return nil
+}
+
+// SetAbout changes the about (status text) of this account
+func (pm *WhatsmeowProfileManager) SetAbout(about string) error {
+	if pm.WhatsmeowConnection == nil || pm.Client == nil {
+		return fmt.Errorf("client not defined")
+	}
+
+	err := pm.Client.SetStatusMessage(context.Background(), about)
+	if err != nil {
+		return fmt.Errorf("failed to update about: %v", err)
+	}
+
+	pm.GetLogger().Infof("about updated")
+	return nil
+}
+
+// SetProfilePicture replaces the profile photo of this account
+func (pm *WhatsmeowProfileManager) SetProfilePicture(imageData []byte) (string, error) {
+	if pm.WhatsmeowConnection == nil || pm.Client == nil {
+		return "", fmt.Errorf("client not defined")
+	}
+
+	if len(imageData) == 0 {
+		return "", fmt.Errorf("empty image data")
+	}
+
+	// an empty jid targets our own profile
+	pictureID, err := pm.Client.SetGroupPhoto(context.Background(), types.EmptyJID, imageData)
+	if err != nil {
+		return "", fmt.Errorf("failed to update profile picture: %v", err)
+	}
+
+	pm.GetLogger().Infof("profile picture updated: %s", pictureID)
+	return pictureID, nil
+}
+
+// RemoveProfilePicture removes the current profile photo of this account
+func (pm *WhatsmeowProfileManager) RemoveProfilePicture() error {
+	if pm.WhatsmeowConnection == nil || pm.Client == nil {
+		return fmt.Errorf("client not defined")
+	}
+
+	_, err := pm.Client.SetGroupPhoto(context.Background(), types.EmptyJID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove profile picture: %v", err)
+	}
+
+	pm.GetLogger().Infof("profile picture removed")
+	return nil
+}
@@ -2,6 +2,7 @@ package whatsmeow
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -24,6 +25,9 @@ type WhatsmeowServiceModel struct {
 	Container *sqlstore.Container
 	Options   WhatsmeowOptions
 
+	// raw access to the store database, for maintenance the container does not expose
+	db *sql.DB
+
 	library.LogStruct
 }
 
@@ -57,7 +61,14 @@ func Start(options WhatsmeowOptions, dbParameters library.DatabaseParameters, lo
 		dbParameters.DataBase = "whatsmeow"
 	}
 	connectionString := dbParameters.GetConnectionString()
-	container, err := sqlstore.New(context.TODO(), dbParameters.Driver, connectionString, dbLog)
+	db, err := sql.Open(dbParameters.Driver, connectionString)
+	if err != nil {
+		err = fmt.Errorf("error on opening db: %s", err.Error())
+		panic(err)
+	}
+
+	container := sqlstore.NewWithDB(db, dbParameters.Driver, dbLog)
+	err = container.Upgrade(context.TODO())
 	if err != nil {
 		err = fmt.Errorf("error on creating db container: %s", err.Error())
 		panic(err)
@@ -66,6 +77,7 @@ func Start(options WhatsmeowOptions, dbParameters library.DatabaseParameters, lo
 	WhatsmeowService = &WhatsmeowServiceModel{
 		Container: container,
 		Options:   options,
+		db:        db,
 	}
 
 	// logging
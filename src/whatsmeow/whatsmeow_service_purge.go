@@ -0,0 +1,118 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"strings"
+
+	types "go.mau.fi/whatsmeow/types"
+)
+
+// PurgeContacts removes the contact entries and chat settings of the given jids from the store
+// of a device, returns how many rows were deleted.
+// Entries already loaded by a running connection are kept in its memory until the next restart.
+func (source *WhatsmeowServiceModel) PurgeContacts(wid string, jids []string) (int, error) {
+	if len(wid) == 0 || len(jids) == 0 {
+		return 0, nil
+	}
+
+	if source.db == nil {
+		return 0, fmt.Errorf("whatsmeow store database not available")
+	}
+
+	args := []interface{}{wid}
+	placeholders := make([]string, len(jids))
+	for i, jid := range jids {
+		args = append(args, jid)
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+	}
+	in := strings.Join(placeholders, ", ")
+
+	tx, err := source.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var affected int64
+	queries := []string{
+		"DELETE FROM whatsmeow_contacts WHERE our_jid = $1 AND their_jid IN (" + in + ")",
+		"DELETE FROM whatsmeow_chat_settings WHERE our_jid = $1 AND chat_jid IN (" + in + ")",
+	}
+
+	for _, query := range queries {
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			return 0, fmt.Errorf("error on purging contacts of %s: %s", wid, err.Error())
+		}
+
+		if rows, err := result.RowsAffected(); err == nil {
+			affected += rows
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(affected), nil
+}
+
+// PurgeLIDMappings removes the lid to phone mappings of the given jids, lid or phone ones, returns
+// how many rows were deleted. Mappings are shared by every device of the store, not kept per session.
+func (source *WhatsmeowServiceModel) PurgeLIDMappings(jids []string) (int, error) {
+	if len(jids) == 0 {
+		return 0, nil
+	}
+
+	if source.db == nil {
+		return 0, fmt.Errorf("whatsmeow store database not available")
+	}
+
+	// mappings are stored by user part, without server
+	lids := []string{}
+	phones := []string{}
+	for _, jid := range jids {
+		user, server, _ := strings.Cut(jid, "@")
+		if len(user) == 0 {
+			continue
+		}
+
+		if server == types.HiddenUserServer {
+			lids = append(lids, user)
+		} else {
+			phones = append(phones, user)
+		}
+	}
+
+	args := []interface{}{}
+	placeholders := func(users []string) string {
+		items := make([]string, len(users))
+		for i, user := range users {
+			args = append(args, user)
+			items[i] = fmt.Sprintf("$%d", len(args))
+		}
+		return strings.Join(items, ", ")
+	}
+
+	conditions := []string{}
+	if len(lids) > 0 {
+		conditions = append(conditions, "lid IN ("+placeholders(lids)+")")
+	}
+	if len(phones) > 0 {
+		conditions = append(conditions, "pn IN ("+placeholders(phones)+")")
+	}
+	if len(conditions) == 0 {
+		return 0, nil
+	}
+
+	result, err := source.db.Exec("DELETE FROM whatsmeow_lid_map WHERE "+strings.Join(conditions, " OR "), args...)
+	if err != nil {
+		return 0, fmt.Errorf("error on purging lid mappings: %s", err.Error())
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, nil
+	}
+	return int(affected), nil
+}
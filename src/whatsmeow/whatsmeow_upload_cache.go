@@ -0,0 +1,147 @@
+package whatsmeow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+)
+
+// UploadCacheBackend stores recent upload results, satisfied by the cache module key value backends
+type UploadCacheBackend interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// WhatsmeowUploadCacheEntry is everything needed to send an already uploaded media again
+type WhatsmeowUploadCacheEntry struct {
+	URL           string `json:"url"`
+	DirectPath    string `json:"directpath"`
+	Handle        string `json:"handle,omitempty"`
+	ObjectID      string `json:"objectid,omitempty"`
+	MediaKey      []byte `json:"mediakey"`
+	FileEncSHA256 []byte `json:"fileencsha256"`
+	FileSHA256    []byte `json:"filesha256"`
+	FileLength    uint64 `json:"filelength"`
+	Thumbnail     []byte `json:"thumbnail,omitempty"`
+	PageCount     uint32 `json:"pagecount,omitempty"`
+}
+
+// GetUploadResponse returns the entry as the upload response expected by the message builders
+func (source *WhatsmeowUploadCacheEntry) GetUploadResponse() whatsmeow.UploadResponse {
+	return whatsmeow.UploadResponse{
+		URL:           source.URL,
+		DirectPath:    source.DirectPath,
+		Handle:        source.Handle,
+		ObjectID:      source.ObjectID,
+		MediaKey:      source.MediaKey,
+		FileEncSHA256: source.FileEncSHA256,
+		FileSHA256:    source.FileSHA256,
+		FileLength:    source.FileLength,
+	}
+}
+
+var (
+	uploadCacheMutex   sync.RWMutex
+	uploadCacheBackend UploadCacheBackend
+	uploadCacheTTL     time.Duration
+)
+
+// SetUploadCache enables the reuse of uploads with identical content for ttl, nil backend or zero ttl disables
+func SetUploadCache(backend UploadCacheBackend, ttl time.Duration) {
+	uploadCacheMutex.Lock()
+	defer uploadCacheMutex.Unlock()
+
+	uploadCacheBackend = backend
+	uploadCacheTTL = ttl
+}
+
+func getUploadCache() (UploadCacheBackend, time.Duration) {
+	uploadCacheMutex.RLock()
+	defer uploadCacheMutex.RUnlock()
+
+	if uploadCacheBackend == nil || uploadCacheTTL <= 0 {
+		return nil, 0
+	}
+	return uploadCacheBackend, uploadCacheTTL
+}
+
+// GetUploadCacheKey returns the cache key of a content uploaded as media type
+func GetUploadCacheKey(content []byte, mediaType whatsmeow.MediaType) string {
+	hash := sha256.Sum256(content)
+	return string(mediaType) + ":" + hex.EncodeToString(hash[:])
+}
+
+// UploadWithCache uploads the attachment content, or reuses a recent upload of the same content.
+// Returns the upload response and the thumbnail generated for it.
+func (source *WhatsmeowConnection) UploadWithCache(attach *whatsapp.WhatsappAttachment, mediaType whatsmeow.MediaType, sticker bool) (response whatsmeow.UploadResponse, thumbnail []byte, err error) {
+	content := *attach.GetContent()
+
+	backend, ttl := getUploadCache()
+	if backend == nil {
+		response, err = source.Client.Upload(context.Background(), content, mediaType)
+		if err == nil && !sticker {
+			thumbnail = GetWhatsmeowAttachmentThumbnail(attach)
+		}
+		return
+	}
+
+	logentry := source.GetLogger()
+	key := GetUploadCacheKey(content, mediaType)
+
+	if data, found, cerr := backend.Get(key); cerr != nil {
+		logentry.Warnf("upload cache get error: %s", cerr.Error())
+	} else if found {
+		entry := &WhatsmeowUploadCacheEntry{}
+		jerr := json.Unmarshal(data, entry)
+		if jerr == nil {
+			if attach.PageCount == 0 {
+				attach.PageCount = entry.PageCount
+			}
+
+			UploadCacheHits.Inc()
+			logentry.Debugf("reusing upload of %s, key: %s", mediaType, key)
+			return entry.GetUploadResponse(), entry.Thumbnail, nil
+		}
+		logentry.Warnf("invalid upload cache entry: %s", jerr.Error())
+	}
+
+	UploadCacheMisses.Inc()
+	response, err = source.Client.Upload(context.Background(), content, mediaType)
+	if err != nil {
+		return
+	}
+
+	if !sticker {
+		thumbnail = GetWhatsmeowAttachmentThumbnail(attach)
+	}
+
+	entry := &WhatsmeowUploadCacheEntry{
+		URL:           response.URL,
+		DirectPath:    response.DirectPath,
+		Handle:        response.Handle,
+		ObjectID:      response.ObjectID,
+		MediaKey:      response.MediaKey,
+		FileEncSHA256: response.FileEncSHA256,
+		FileSHA256:    response.FileSHA256,
+		FileLength:    response.FileLength,
+		Thumbnail:     thumbnail,
+		PageCount:     attach.PageCount,
+	}
+
+	data, jerr := json.Marshal(entry)
+	if jerr != nil {
+		logentry.Warnf("error encoding upload cache entry: %s", jerr.Error())
+		return
+	}
+
+	if cerr := backend.Set(key, data, ttl); cerr != nil {
+		logentry.Warnf("upload cache set error: %s", cerr.Error())
+	}
+	return
+}
@@ -0,0 +1,68 @@
+package whatsmeow
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+)
+
+type testUploadCacheBackend map[string][]byte
+
+func (source testUploadCacheBackend) Get(key string) ([]byte, bool, error) {
+	value, ok := source[key]
+	return value, ok, nil
+}
+
+func (source testUploadCacheBackend) Set(key string, value []byte, ttl time.Duration) error {
+	source[key] = value
+	return nil
+}
+
+func TestGetUploadCacheKey(t *testing.T) {
+	content := []byte("brochure")
+	if GetUploadCacheKey(content, whatsmeow.MediaDocument) == GetUploadCacheKey(content, whatsmeow.MediaImage) {
+		t.Errorf("expected distinct keys per media type")
+	}
+
+	if GetUploadCacheKey(content, whatsmeow.MediaDocument) != GetUploadCacheKey([]byte("brochure"), whatsmeow.MediaDocument) {
+		t.Errorf("expected the same key for identical content")
+	}
+}
+
+func TestUploadWithCacheReusesEntry(t *testing.T) {
+	content := []byte("%PDF-1.4 brochure")
+	attach := &whatsapp.WhatsappAttachment{Mimetype: "application/pdf"}
+	attach.SetContent(&content)
+
+	entry := WhatsmeowUploadCacheEntry{
+		URL:        "https://mmg.whatsapp.net/d/f/brochure",
+		DirectPath: "/v/t62.7119-24/brochure",
+		MediaKey:   []byte{1, 2, 3},
+		FileLength: uint64(len(content)),
+		Thumbnail:  []byte{0xFF, 0xD8},
+	}
+	data, _ := json.Marshal(entry)
+
+	backend := testUploadCacheBackend{GetUploadCacheKey(content, whatsmeow.MediaDocument): data}
+	SetUploadCache(backend, time.Hour)
+	defer SetUploadCache(nil, 0)
+
+	// a cache hit never touches the client, nil here
+	conn := &WhatsmeowConnection{}
+	response, thumbnail, err := conn.UploadWithCache(attach, whatsmeow.MediaDocument, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if response.DirectPath != entry.DirectPath || !bytes.Equal(response.MediaKey, entry.MediaKey) || response.FileLength != entry.FileLength {
+		t.Errorf("unexpected response: %+v", response)
+	}
+
+	if !bytes.Equal(thumbnail, entry.Thumbnail) {
+		t.Errorf("unexpected thumbnail: %v", thumbnail)
+	}
+}